@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// AccountConfig is another speedrun.com account whose notifications are
+// merged into the inbox, such as a moderation alt
+type AccountConfig struct {
+	Name    string `json:"name"`    // shown as the badge
+	Session string `json:"session"` // its PHPSESSID
+}
+
+var accountBadgeStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#0F0F0F")).
+	Background(lipgloss.Color("#5F89F4")).
+	Padding(0, 1)
+
+// validateAccounts checks that every account has a distinct name and a
+// session
+func validateAccounts(accounts []AccountConfig) error {
+	seen := map[string]bool{}
+	for i, a := range accounts {
+		switch {
+		case a.Name == "":
+			return fmt.Errorf("accounts: account %d has no name", i+1)
+		case a.Session == "":
+			return fmt.Errorf("accounts: %s has no session", a.Name)
+		case seen[a.Name]:
+			return fmt.Errorf("accounts: %s is listed twice", a.Name)
+		}
+		seen[a.Name] = true
+	}
+	return nil
+}
+
+// primaryAccountName names the -session account after its user, so its
+// badge matches the site
+func primaryAccountName(client *Client) string {
+	session, err := client.GetSession()
+	if err != nil || session.User == nil {
+		return "main"
+	}
+	return session.User.Name
+}
+
+// combinedInbox adds the notifications of the other accounts to the
+// primary one's, each tagged with its account, newest first. An account
+// that fails to load is left out rather than failing the app.
+func combinedInbox(primary *Client, result *NotificationResponse, cfg Config) (map[string]*Client, []string) {
+	name := primaryAccountName(primary)
+	clients := map[string]*Client{name: primary}
+	for i := range result.Notifications {
+		result.Notifications[i].Account = name
+	}
+
+	var failed []string
+	for _, a := range cfg.Accounts {
+		client := newConfiguredClient(cfg, a.Session)
+		client.dryRun = primary.dryRun
+		other, err := client.GetNotificationPages(cfg.NotificationsPerPage, cfg.NotificationPages)
+		if err != nil {
+			log.Printf("accounts: %s: %v", a.Name, err)
+			failed = append(failed, a.Name)
+			continue
+		}
+		clients[a.Name] = client
+		for _, n := range other.Notifications {
+			n.Account = a.Name
+			result.Notifications = append(result.Notifications, n)
+		}
+		result.UnreadCount += other.UnreadCount
+	}
+	sort.SliceStable(result.Notifications, func(i, j int) bool {
+		return result.Notifications[i].Date > result.Notifications[j].Date
+	})
+	return clients, failed
+}
+
+// clientFor is the session that owns an account's notifications
+func (m model) clientFor(account string) *Client {
+	if c, ok := m.accounts[account]; ok {
+		return c
+	}
+	return m.client
+}
+
+// byAccount groups notification IDs by the account they belong to, so
+// each goes to the right session. Without other accounts everything is
+// under "". Owners come from everything loaded rather than the list on
+// screen, since the rules mark read notifications they also mute.
+func (m model) byAccount(ids []string) map[string][]string {
+	groups := map[string][]string{}
+	for _, id := range ids {
+		groups[m.known[id]] = append(groups[m.known[id]], id)
+	}
+	return groups
+}
+
+// ruleWorkCmds runs the rules' mark reads with each account's session and
+// delivers the alerts once
+func (m model) ruleWorkCmds() []tea.Cmd {
+	cmds := []tea.Cmd{ruleWorkCmd(m.client, ruleWork{deliveries: m.ruleWork.deliveries})}
+	for account, ids := range m.byAccount(m.ruleWork.markRead) {
+		cmds = append(cmds, ruleWorkCmd(m.clientFor(account), ruleWork{markRead: ids}))
+	}
+	return cmds
+}
+
+func renderAccountBadge(account string) string {
+	if account == "" {
+		return ""
+	}
+	return accountBadgeStyle.Render(account) + " "
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Attempts per timer name, so each game and category keeps its own count
+const attemptsFile = "attempts.json"
+
+// attemptDays is how far back the timer's sparkline of attempts goes
+const attemptDays = 14
+
+// attemptLog counts one timer's attempts by day, YYYY-MM-DD in local time
+type attemptLog map[string]int
+
+func loadAttempts(name string) (attemptLog, error) {
+	all := make(map[string]attemptLog)
+	if err := loadState(attemptsFile, &all); err != nil {
+		return attemptLog{}, err
+	}
+	if all[name] == nil {
+		return attemptLog{}, nil
+	}
+	return all[name], nil
+}
+
+// addAttempts adds n (or takes away, for a negative n) to today's count
+// and saves it, returning the log as it's now stored. Today's count
+// doesn't go below zero.
+func addAttempts(name string, n int) (attemptLog, error) {
+	all := make(map[string]attemptLog)
+	if err := loadState(attemptsFile, &all); err != nil {
+		return nil, err
+	}
+	counts := all[name]
+	if counts == nil {
+		counts = attemptLog{}
+	}
+	today := time.Now().Format(time.DateOnly)
+	counts[today] = max(counts[today]+n, 0)
+	if counts[today] == 0 {
+		delete(counts, today)
+	}
+	all[name] = counts
+	if err := saveState(attemptsFile, all); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (a attemptLog) total() int {
+	total := 0
+	for _, n := range a {
+		total += n
+	}
+	return total
+}
+
+// recent is the count for each of the last days days, oldest first
+func (a attemptLog) recent(days int) []int {
+	counts := make([]int, days)
+	now := time.Now()
+	for i := range counts {
+		counts[i] = a[now.AddDate(0, 0, i-days+1).Format(time.DateOnly)]
+	}
+	return counts
+}
+
+// summary is the timer's attempts line: today, the last week, every
+// attempt so far and a sparkline of the last two weeks
+func (a attemptLog) summary() string {
+	days := a.recent(attemptDays)
+	week := 0
+	for _, n := range days[attemptDays-7:] {
+		week += n
+	}
+	return fmt.Sprintf("Attempts %d today • %d this week • %d in all  %s",
+		days[attemptDays-1], week, a.total(), sparkline(days))
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Every write sent from the app, one JSON object per line. Only ever
+// appended to.
+const auditFile = "audit.jsonl"
+
+// auditEntry is one write and how it went
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"` // e.g. "verify run", "mark read"
+	Target []string  `json:"target"` // run, notification or reported item IDs
+	Detail string    `json:"detail,omitempty"`
+	Result string    `json:"result"` // "ok", "dry run" or the error
+}
+
+func (e auditEntry) failed() bool {
+	return e.Result != "ok" && e.Result != "dry run"
+}
+
+// auditMu keeps writes finishing side by side from interleaving lines
+var auditMu sync.Mutex
+
+func auditPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, auditFile), nil
+}
+
+// describeWrite turns a write endpoint and its body into an audit entry
+func describeWrite(endpoint string, body any) auditEntry {
+	e := auditEntry{Action: endpoint}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return e
+	}
+	var f struct {
+		RunID           string   `json:"runId"`
+		Verified        int      `json:"verified"`
+		Reason          string   `json:"reason"`
+		NotificationIDs []string `json:"notificationIds"`
+		Read            *bool    `json:"read"`
+		Settings        struct {
+			RunID string `json:"runId"`
+		} `json:"settings"`
+		ThreadID   string `json:"threadId"`
+		Subscribed *bool  `json:"subscribed"`
+		ItemType   string `json:"itemType"`
+		ItemID     string `json:"itemId"`
+		Text       string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return e
+	}
+
+	switch endpoint {
+	case "PutRunVerification":
+		e.Action, e.Target, e.Detail = "verify run", []string{f.RunID}, f.Reason
+		if f.Verified == runRejected {
+			e.Action = "reject run"
+		}
+	case "PutNotificationsRead":
+		e.Action, e.Target = "mark read", f.NotificationIDs
+		if f.Read != nil && !*f.Read {
+			e.Action = "mark unread"
+		}
+	case "PutRunSettings":
+		e.Action, e.Target = "submit run", nil
+		if f.Settings.RunID != "" {
+			e.Action, e.Target = "edit run", []string{f.Settings.RunID}
+		}
+	case "PutRunDelete":
+		e.Action, e.Target = "withdraw run", []string{f.RunID}
+	case "PutReport":
+		e.Action, e.Target, e.Detail = "report "+f.ItemType, []string{f.ItemID}, f.Text
+	case "PutComment":
+		e.Action, e.Target, e.Detail = "comment on "+f.ItemType, []string{f.ItemID}, f.Text
+	case "PutThreadSubscription":
+		e.Action, e.Target = "subscribe to thread", []string{f.ThreadID}
+		if f.Subscribed != nil && !*f.Subscribed {
+			e.Action = "unsubscribe from thread"
+		}
+	}
+	return e
+}
+
+// audit records a write once it's been answered. Failing to record it
+// doesn't fail the write.
+func (c *Client) audit(endpoint string, body any, err error) {
+	e := describeWrite(endpoint, body)
+	e.Time = time.Now()
+	switch {
+	case err != nil:
+		e.Result = err.Error()
+	case c.dryRun:
+		e.Result = "dry run"
+	default:
+		e.Result = "ok"
+	}
+	if err := appendAudit(e); err != nil {
+		log.Printf("audit: %v", err)
+	}
+}
+
+func appendAudit(e auditEntry) error {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	path, err := auditPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(e); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return nil
+}
+
+// loadAudit reads the log. Lines that fail to parse are skipped.
+func loadAudit() ([]auditEntry, error) {
+	path, err := auditPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			logOnce(fmt.Sprintf("audit.line.%d", line), "audit: skipping line %d: %v", line, err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("reading audit log: %w", err)
+	}
+	return entries, nil
+}
+
+type auditMsg struct {
+	entries []auditEntry
+	err     error
+}
+
+func loadAuditCmd() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := loadAudit()
+		return auditMsg{entries: entries, err: err}
+	}
+}
+
+// activityModel is the activity tab: the audit log, newest first, to
+// answer "did I already verify that?"
+type activityModel struct {
+	entries    []auditEntry
+	failedOnly bool
+	loading    bool
+	err        error
+}
+
+func (a activityModel) activate() (activityModel, tea.Cmd) {
+	a.loading = true
+	return a, loadAuditCmd()
+}
+
+func (a activityModel) update(msg tea.Msg) (activityModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case auditMsg:
+		a.loading = false
+		a.entries, a.err = msg.entries, msg.err
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "e":
+			a.failedOnly = !a.failedOnly
+		case "r":
+			return a.activate()
+		}
+	}
+	return a, nil
+}
+
+func (a activityModel) view() string {
+	switch {
+	case a.loading:
+		return "Loading activity..."
+	case a.err != nil:
+		return fmt.Sprintf("Error: %v", a.err)
+	}
+
+	var b strings.Builder
+	shown := 0
+	for i := len(a.entries) - 1; i >= 0; i-- {
+		e := a.entries[i]
+		if a.failedOnly && !e.failed() {
+			continue
+		}
+		shown++
+		result := okStyle.Render("✓")
+		switch {
+		case e.Result == "dry run":
+			result = dryRunStyle.Render("dry run")
+		case e.failed():
+			result = failStyle.Render("✗ " + e.Result)
+		}
+		b.WriteString(fmt.Sprintf("%s  %s %s  %s\n", e.Time.Local().Format("2006-01-02 15:04"), e.Action, strings.Join(e.Target, ", "), result))
+		if e.Detail != "" {
+			b.WriteString(urlStyle.Render("    "+truncate(e.Detail, 70)) + "\n")
+		}
+	}
+	if shown == 0 {
+		if a.failedOnly {
+			return "No failed actions"
+		}
+		return "Nothing done from the app yet"
+	}
+	return b.String()
+}
+
+func (a activityModel) help() string {
+	if a.failedOnly {
+		return "e show all • r reload"
+	}
+	return "e errors only • r reload"
+}
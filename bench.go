@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// benchCall is one endpoint the bench command times
+type benchCall struct {
+	name string
+	call func() error
+}
+
+// benchResult is how an endpoint did over every iteration. Failures are
+// split by where they came from: no answer (network), an error status
+// (site) or an answer we couldn't read (tool).
+type benchResult struct {
+	name      string
+	latencies []time.Duration // of the calls that succeeded
+	network   int
+	site      int
+	tool      int
+}
+
+func (r *benchResult) add(latency time.Duration, err error) {
+	var apiErr *APIError
+	var urlErr *url.Error
+	switch {
+	case err == nil:
+		r.latencies = append(r.latencies, latency)
+	case errors.As(err, &apiErr):
+		r.site++
+	case errors.As(err, &urlErr):
+		r.network++
+	default:
+		r.tool++
+	}
+}
+
+// percentile is the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (p*len(sorted)+99)/100 - 1
+	return sorted[max(i, 0)]
+}
+
+// runBench calls the endpoints the app leans on n times each and prints
+// how fast and how reliably they answered, to tell a slow site from a
+// broken network or a bug here
+func runBench(args []string, cfg Config, sessionID string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	n := fs.Int("n", 10, "calls per endpoint")
+	game := fs.String("game", "sm64", "game abbreviation to fetch")
+	fs.Parse(args)
+
+	if *n < 1 {
+		return fmt.Errorf("-n must be at least 1, got %d", *n)
+	}
+	client := newConfiguredClient(cfg, sessionID)
+
+	calls := []benchCall{
+		{"GetSession", func() error { _, err := client.GetSession(); return err }},
+		{"GetGameData", func() error { _, err := client.GetGameData(*game); return err }},
+	}
+	if sessionID != "" {
+		calls = append(calls, benchCall{"GetNotifications", func() error {
+			_, err := client.GetNotifications(1, cfg.NotificationsPerPage)
+			return err
+		}})
+	} else {
+		fmt.Fprintln(os.Stderr, "No session, skipping GetNotifications")
+	}
+	// v1 needs the game's ID rather than its abbreviation
+	if data, err := client.GetGameData(*game); err == nil {
+		calls = append(calls, benchCall{"v1 games", func() error {
+			_, err := client.GetModerators(data.Game.ID)
+			return err
+		}})
+	} else {
+		fmt.Fprintf(os.Stderr, "Skipping v1: %v\n", err)
+	}
+
+	results := make([]*benchResult, len(calls))
+	for i, c := range calls {
+		results[i] = &benchResult{name: c.name}
+	}
+	// Interleaved, so a slow patch on the site hits every endpoint alike
+	for i := 0; i < *n; i++ {
+		for j, c := range calls {
+			start := time.Now()
+			err := c.call()
+			if err != nil {
+				log.Printf("bench: %s: %v", c.name, err)
+			}
+			results[j].add(time.Since(start), err)
+		}
+		fmt.Fprintf(os.Stderr, "\r%d/%d", i+1, *n)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENDPOINT\tOK\tP50\tP90\tP99\tMAX\tNETWORK\tSITE\tTOOL")
+	failed := false
+	for _, r := range results {
+		sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+		ok := len(r.latencies)
+		if ok < *n {
+			failed = true
+		}
+		row := fmt.Sprintf("%s\t%d/%d", r.name, ok, *n)
+		if ok == 0 {
+			row += "\t-\t-\t-\t-"
+		} else {
+			for _, p := range []int{50, 90, 99, 100} {
+				row += "\t" + percentile(r.latencies, p).Round(time.Millisecond).String()
+			}
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", row, r.network, r.site, r.tool)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed {
+		fmt.Println("\nNETWORK: no answer (DNS, connection, timeout). SITE: an error status. TOOL: an answer this version can't read.")
+		fmt.Println("Details are in the log.")
+	}
+	return nil
+}
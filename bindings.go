@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Binding steps that aren't plain keys
+const (
+	stepKey    = iota
+	stepVerify // verify the selected queue run
+	stepReject // reject it with a rejection template
+)
+
+// bindingStep is one action of a binding: a named action or a key press
+type bindingStep struct {
+	kind     int
+	template int // for stepReject, 0 based
+	key      tea.KeyMsg
+}
+
+func (s bindingStep) destructive() bool {
+	return s.kind != stepKey
+}
+
+// bindings are the keys bound to actions in the config, by screen
+type bindings map[screen]map[string][]bindingStep
+
+// Named actions that stand for a key on every screen
+var commonAliases = map[string]string{
+	"next": "down",
+	"prev": "up",
+	"open": "enter",
+}
+
+// And those that only mean something on one screen
+var screenAliases = map[screen]map[string]string{
+	screenNotifications: {"mark-read": "r", "pin": "b", "copy": "y"},
+	screenQueue:         {"pin": "b", "copy": "y", "play": "v"},
+}
+
+// Named keys a step can send; anything else must be a single character
+var stepKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"space":     tea.KeySpace,
+	"backspace": tea.KeyBackspace,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+}
+
+func stepKeyMsg(name string) (tea.KeyMsg, bool) {
+	if t, ok := stepKeys[name]; ok {
+		return tea.KeyMsg{Type: t}, true
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}, true
+	}
+	return tea.KeyMsg{}, false
+}
+
+func parseStep(s screen, name string, templates int) (bindingStep, error) {
+	if alias, ok := screenAliases[s][name]; ok {
+		name = alias
+	} else if alias, ok := commonAliases[name]; ok {
+		name = alias
+	}
+
+	if s == screenQueue {
+		if name == "verify" {
+			return bindingStep{kind: stepVerify}, nil
+		}
+		if n, ok := strings.CutPrefix(name, "reject:"); ok {
+			i, err := strconv.Atoi(n)
+			if err != nil || i < 1 || i > templates {
+				return bindingStep{}, fmt.Errorf("%q: there are %d rejection templates", name, templates)
+			}
+			return bindingStep{kind: stepReject, template: i - 1}, nil
+		}
+	}
+
+	key, ok := stepKeyMsg(name)
+	if !ok {
+		return bindingStep{}, fmt.Errorf("unknown action %q", name)
+	}
+	return bindingStep{kind: stepKey, key: key}, nil
+}
+
+// parseBindings checks the bindings config, keyed by lowercase screen name
+// then key, e.g. {"queue": {"V": ["verify", "next"]}}
+func parseBindings(raw map[string]map[string][]string, rejectTemplates int) (bindings, error) {
+	if rejectTemplates == 0 {
+		rejectTemplates = len(defaultRejectionTemplates)
+	}
+	parsed := bindings{}
+	for name, keys := range raw {
+		s, ok := screenNamed(name)
+		if !ok {
+			return nil, fmt.Errorf("bindings: unknown screen %q", name)
+		}
+		parsed[s] = map[string][]bindingStep{}
+		for key, actions := range keys {
+			if len(actions) == 0 {
+				return nil, fmt.Errorf("bindings: %s %s has no actions", name, key)
+			}
+			for _, a := range actions {
+				step, err := parseStep(s, a, rejectTemplates)
+				if err != nil {
+					return nil, fmt.Errorf("bindings: %s %s: %w", name, key, err)
+				}
+				parsed[s][key] = append(parsed[s][key], step)
+			}
+		}
+	}
+	return parsed, nil
+}
+
+func screenNamed(name string) (screen, bool) {
+	for s, n := range screenNames {
+		if strings.EqualFold(n, name) {
+			return screen(s), true
+		}
+	}
+	return 0, false
+}
+
+// bindingMsg is a confirmed binding that verifies or rejects runID
+type bindingMsg struct {
+	screen screen
+	key    string
+	runID  string
+}
+
+// bindingsActive reports whether a key could be a binding rather than
+// text or an answer to a dialog
+func (m model) bindingsActive() bool {
+	if m.replaying || m.typing() {
+		return false
+	}
+	switch m.screen {
+	case screenNotifications:
+		return m.detail == nil
+	case screenQueue:
+		return !m.queue.showStats && !m.queue.busy()
+	}
+	return true
+}
+
+// startBinding runs a binding, asking first when it verifies or rejects
+func (m model) startBinding(key string, steps []bindingStep) (model, tea.Cmd) {
+	for _, s := range steps {
+		if !s.destructive() {
+			continue
+		}
+		r, ok := m.queue.selectedRun()
+		if !ok {
+			return m, nil
+		}
+		return m, confirmCmd(bindingMsg{screen: m.screen, key: key, runID: r.ID},
+			"%s: %s the run by %s?", key, describeSteps(steps), strings.Join(r.Players, ", "))
+	}
+	return m.runBinding(steps)
+}
+
+func describeSteps(steps []bindingStep) string {
+	var parts []string
+	for _, s := range steps {
+		switch s.kind {
+		case stepVerify:
+			parts = append(parts, "verify")
+		case stepReject:
+			parts = append(parts, fmt.Sprintf("reject with template %d", s.template+1))
+		default:
+			parts = append(parts, s.key.String())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// confirmedBinding runs a binding once it's confirmed, if the run it was
+// asked about is still the selected one
+func (m model) confirmedBinding(msg bindingMsg) (model, tea.Cmd) {
+	r, ok := m.queue.selectedRun()
+	if m.screen != msg.screen || !ok || r.ID != msg.runID {
+		m.status = "Cancelled: the selection changed"
+		return m, nil
+	}
+	return m.runBinding(m.bindings[msg.screen][msg.key])
+}
+
+// runBinding plays the steps in order. Verifying or rejecting already
+// moves the cursor on, so a "next" straight after one doesn't move again.
+func (m model) runBinding(steps []bindingStep) (model, tea.Cmd) {
+	var (
+		cmds     []tea.Cmd
+		advanced bool
+	)
+	m.replaying = true
+	for _, s := range steps {
+		var cmd tea.Cmd
+		switch s.kind {
+		case stepVerify:
+			m.queue, cmd = m.queue.verifySelected()
+			advanced = true
+		case stepReject:
+			m.queue, cmd = m.queue.rejectSelected(s.template)
+			advanced = true
+		default:
+			if k := s.key.String(); advanced && (k == "down" || k == "j") {
+				advanced = false
+				continue
+			}
+			var next tea.Model
+			next, cmd = m.Update(s.key)
+			m = next.(model)
+			advanced = false
+		}
+		cmds = append(cmds, cmd)
+	}
+	m.replaying = false
+	m.viewport.SetContent(m.renderContent())
+	return m, tea.Batch(cmds...)
+}
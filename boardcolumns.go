@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// Widest a variable column gets before its values are cut short
+	boardColumnMax = 18
+
+	// Place, movement, runners, time and platform, ahead of the columns
+	boardRowPrefix = 4 + 1 + 4 + 1 + 36 + 1 + 12 + 2 + platformBadgeWidth
+
+	boardDateWidth = 2 + 10
+)
+
+// boardColumn is a variable shown as a column on a board, like the
+// version or character runs were done with
+type boardColumn struct {
+	Variable Variable
+	Width    int
+}
+
+// columns are the board's variables that don't split it into subcategories,
+// narrowed to the ones board_columns names for the game
+func (b boardsModel) columns() []boardColumn {
+	if b.game == nil || b.board == nil {
+		return nil
+	}
+	names, picked := b.columnNames[b.game.Game.URL]
+
+	var vars []Variable
+	for _, v := range b.game.Variables {
+		if v.IsSubcategory || (v.CategoryID != "" && v.CategoryID != b.category.ID) {
+			continue
+		}
+		if picked && !slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, v.Name) }) {
+			continue
+		}
+		vars = append(vars, v)
+	}
+	sort.SliceStable(vars, func(i, j int) bool { return vars[i].Pos < vars[j].Pos })
+
+	cols := make([]boardColumn, 0, len(vars))
+	for _, v := range vars {
+		width := len([]rune(v.Name))
+		for _, r := range b.board.Runs {
+			width = max(width, len([]rune(b.valueName(r, v))))
+		}
+		cols = append(cols, boardColumn{Variable: v, Width: max(min(width, boardColumnMax), 1)})
+	}
+	return cols
+}
+
+// valueName is what a run has for a variable, or "" when it has nothing
+func (b boardsModel) valueName(r Run, v Variable) string {
+	for _, val := range b.game.Values {
+		if val.VariableID == v.ID && slices.Contains(r.ValueIDs, val.ID) {
+			return val.Name
+		}
+	}
+	return ""
+}
+
+// visibleColumns are the columns from the scroll offset on that fit the
+// screen, and whether any are cut off to the right
+func (b boardsModel) visibleColumns() ([]boardColumn, bool) {
+	cols := b.columns()
+	if b.colOffset >= len(cols) {
+		return nil, false
+	}
+	cols = cols[b.colOffset:]
+	if b.width == 0 {
+		return cols, false
+	}
+	room := b.width - boardRowPrefix - boardDateWidth - 1
+	for i, c := range cols {
+		room -= 2 + c.Width
+		// The first column always shows, squeezed if it must be
+		if room < 0 && i > 0 {
+			return cols[:i], true
+		}
+	}
+	return cols, false
+}
+
+// columnHeader names the visible columns above the board, with arrows
+// where more are scrolled off
+func (b boardsModel) columnHeader(cols []boardColumn, more bool) string {
+	if len(cols) == 0 {
+		return ""
+	}
+	left := " "
+	if b.colOffset > 0 {
+		left = "‹"
+	}
+	line := fmt.Sprintf("%-*s", boardRowPrefix+1, left)
+	for _, c := range cols {
+		line += "  " + fmt.Sprintf("%-*s", c.Width, truncate(c.Variable.Name, c.Width))
+	}
+	if more {
+		line += " ›"
+	}
+	return line
+}
+
+// renderColumns is a run's cells for the visible columns
+func (b boardsModel) renderColumns(r Run, cols []boardColumn) string {
+	var out strings.Builder
+	for _, c := range cols {
+		out.WriteString("  " + fmt.Sprintf("%-*s", c.Width, truncate(b.valueName(r, c.Variable), c.Width)))
+	}
+	return out.String()
+}
+
+// scrollColumns moves the first visible column by delta, keeping at least
+// one column on screen
+func (b boardsModel) scrollColumns(delta int) boardsModel {
+	n := len(b.columns())
+	b.colOffset = max(min(b.colOffset+delta, n-1), 0)
+	return b
+}
+
+// headerLines counts the lines above a board's first run: the rank line,
+// and the column names when there are columns
+func (b boardsModel) headerLines() int {
+	if cols, _ := b.visibleColumns(); len(cols) > 0 {
+		return 2
+	}
+	return 1
+}
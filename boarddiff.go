@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Places on every board as of the last time it was opened, so the next
+// visit can show who moved
+const boardSnapshotsFile = "board_snapshots.json"
+
+var (
+	rankUpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#4CAF50"))
+	rankDownStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B"))
+	rankNewStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700"))
+)
+
+type boardSnapshot struct {
+	Taken  time.Time      `json:"taken"`
+	Places map[string]int `json:"places"` // runner key -> place
+	// Only the first page is kept, so entries below its last place aren't
+	// new, just unknown
+	Depth int `json:"depth,omitempty"`
+}
+
+// boardKey identifies a board by everything that selects it
+func boardKey(params LeaderboardParams) string {
+	params.Verified = 0
+	raw, _ := json.Marshal(params)
+	return string(raw)
+}
+
+// runnerKey identifies an entry across visits. Run IDs change with every
+// new PB, the players don't.
+func runnerKey(r Run) string {
+	ids := slices.Clone(r.PlayerIDs)
+	slices.Sort(ids)
+	return strings.Join(ids, ",")
+}
+
+func snapshotBoard(board *Leaderboard, now time.Time) boardSnapshot {
+	s := boardSnapshot{Taken: now, Places: make(map[string]int, len(board.Runs))}
+	for _, r := range board.Runs {
+		if key := runnerKey(r); key != "" {
+			s.Places[key] = r.Place
+		}
+		s.Depth = max(s.Depth, r.Place)
+	}
+	return s
+}
+
+// swapBoardSnapshot saves the board as seen now and returns how it looked
+// the previous time, or nil on the first visit
+func swapBoardSnapshot(params LeaderboardParams, board *Leaderboard) (*boardSnapshot, error) {
+	snapshots := make(map[string]boardSnapshot)
+	if err := loadState(boardSnapshotsFile, &snapshots); err != nil {
+		return nil, err
+	}
+
+	key := boardKey(params)
+	var previous *boardSnapshot
+	if s, ok := snapshots[key]; ok {
+		// Snapshots from before depth was kept
+		for _, place := range s.Places {
+			s.Depth = max(s.Depth, place)
+		}
+		previous = &s
+	}
+	snapshots[key] = snapshotBoard(board, time.Now())
+	if err := saveState(boardSnapshotsFile, snapshots); err != nil {
+		return previous, fmt.Errorf("saving board snapshot: %w", err)
+	}
+	return previous, nil
+}
+
+// movement renders how far a run moved since the snapshot, padded to a
+// fixed width: ▲2, ▼1, NEW, or blank when it held its place
+func (s *boardSnapshot) movement(r Run) string {
+	const width = 4
+	if s == nil {
+		return strings.Repeat(" ", width)
+	}
+	key := runnerKey(r)
+	before, ok := s.Places[key]
+	var out string
+	switch {
+	case key == "", !ok && r.Place > s.Depth:
+	case !ok:
+		out = rankNewStyle.Render("NEW")
+	case before > r.Place:
+		out = rankUpStyle.Render(fmt.Sprintf("▲%d", before-r.Place))
+	case before < r.Place:
+		out = rankDownStyle.Render(fmt.Sprintf("▼%d", r.Place-before))
+	}
+	return out + strings.Repeat(" ", max(width-lipgloss.Width(out), 0))
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The next page is fetched once the cursor is this close to the end of
+// what's loaded
+const boardPrefetch = 20
+
+type boardPagesMsg struct {
+	key   string
+	pages []*Leaderboard // in page order
+	place int            // select this place once they're in, for M
+	err   error
+}
+
+type myRankMsg struct {
+	key   string
+	place int // 0 when the user has no run on the board
+	err   error
+}
+
+// extend appends the next page of the board
+func (lb *Leaderboard) extend(next *Leaderboard) {
+	lb.Runs = append(lb.Runs, next.Runs...)
+	for _, p := range next.Players {
+		if !slices.ContainsFunc(lb.Players, func(q Player) bool { return q.ID == p.ID }) {
+			lb.Players = append(lb.Players, p)
+		}
+	}
+	lb.Pagination = next.Pagination
+}
+
+// more reports whether the board has pages left to fetch
+func (b boardsModel) more() bool {
+	return b.board != nil && b.board.Pagination.Page < b.board.Pagination.Pages
+}
+
+// loadPagesCmd fetches the pages after the ones loaded up to last, side
+// by side, then selects place if it's set
+func (b boardsModel) loadPagesCmd(last, place int) tea.Cmd {
+	client, params, first := b.client, b.params, b.board.Pagination.Page+1
+	return func() tea.Msg {
+		key := boardKey(params)
+		pages := make([]*Leaderboard, last-first+1)
+		var (
+			mu   sync.Mutex
+			jobs []Job
+		)
+		for page := first; page <= last; page++ {
+			jobs = append(jobs, Job{Name: fmt.Sprintf("page %d", page), Host: hostOf(client.baseURL), Run: func() error {
+				lb, err := client.GetLeaderboard(params, page)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				pages[page-first] = lb
+				return nil
+			}})
+		}
+		if err := NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs); err != nil {
+			return boardPagesMsg{key: key, err: err}
+		}
+		return boardPagesMsg{key: key, pages: pages, place: place}
+	}
+}
+
+// sameBoard compares two boards' params, whatever order the subcategory
+// values come in
+func sameBoard(a, b LeaderboardParams) bool {
+	if a.GameID != b.GameID || a.CategoryID != b.CategoryID || a.LevelID != b.LevelID || len(a.Values) != len(b.Values) {
+		return false
+	}
+	values := func(p LeaderboardParams) []string {
+		var vs []string
+		for _, f := range p.Values {
+			for _, id := range f.ValueIDs {
+				vs = append(vs, f.VariableID+"="+id)
+			}
+		}
+		sort.Strings(vs)
+		return vs
+	}
+	return slices.Equal(values(a), values(b))
+}
+
+// myRankCmd finds the signed in user's place on the board from their PBs
+func (b boardsModel) myRankCmd() tea.Cmd {
+	client, params := b.client, b.params
+	return func() tea.Msg {
+		key := boardKey(params)
+		session, err := client.GetSession()
+		if err != nil {
+			return myRankMsg{key: key, err: err}
+		}
+		if !session.SignedIn || session.User == nil {
+			return myRankMsg{key: key, err: errNoSession}
+		}
+		pbs, err := client.GetUserLeaderboard(session.User.ID)
+		if err != nil {
+			return myRankMsg{key: key, err: err}
+		}
+		for _, r := range pbs.Runs {
+			if !r.Obsolete && r.Place > 0 && sameBoard(pbs.BoardParams(r), params) {
+				return myRankMsg{key: key, place: r.Place}
+			}
+		}
+		return myRankMsg{key: key}
+	}
+}
+
+// prefetch starts on the next page when the cursor nears the end of the
+// loaded runs
+func (b boardsModel) prefetch() (boardsModel, tea.Cmd) {
+	if b.level != boardsBoard || b.fetching || !b.more() || b.selected < len(b.board.Runs)-boardPrefetch {
+		return b, nil
+	}
+	b.fetching = true
+	return b, b.loadPagesCmd(b.board.Pagination.Page+1, 0)
+}
+
+// jumpToPlace selects the first run at place, fetching the pages down to
+// it if they aren't loaded yet
+func (b boardsModel) jumpToPlace(place int) (boardsModel, tea.Cmd) {
+	for i, r := range b.board.Runs {
+		if r.Place >= place {
+			b.selected = i
+			b.jumped = true
+			return b.prefetch()
+		}
+	}
+	if !b.more() {
+		return b, statusCmd("Place %d isn't on the board anymore", place)
+	}
+	// Ties can push a place past its page, so one more is fetched
+	p := b.board.Pagination
+	last := p.Pages
+	if p.Per > 0 {
+		last = min((place-1)/p.Per+2, p.Pages)
+	}
+	b.fetching = true
+	return b, tea.Batch(b.loadPagesCmd(last, place), statusCmd("Fetching down to place %d...", place))
+}
+
+// updatePages handles the board's paging messages
+func (b boardsModel) updatePages(msg tea.Msg) (boardsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case boardPagesMsg:
+		if b.board == nil || msg.key != boardKey(b.params) {
+			return b, nil
+		}
+		b.fetching = false
+		if msg.err != nil {
+			return b, statusCmd("Couldn't load more of the board: %v", msg.err)
+		}
+		var runs []Run
+		for _, page := range msg.pages {
+			if page.Pagination.Page != b.board.Pagination.Page+1 {
+				continue
+			}
+			b.board.extend(page)
+			runs = append(runs, page.Runs...)
+		}
+		cmd := b.liveRunnersCmd(runs)
+		if msg.place > 0 {
+			var jump tea.Cmd
+			b, jump = b.jumpToPlace(msg.place)
+			cmd = tea.Batch(cmd, jump)
+		}
+		return b, cmd
+
+	case myRankMsg:
+		if b.board == nil || msg.key != boardKey(b.params) {
+			return b, nil
+		}
+		b.fetching = false
+		switch {
+		case errors.Is(msg.err, errNoSession):
+			return b, statusCmd("Sign in to find your rank")
+		case msg.err != nil:
+			return b, statusCmd("Couldn't find your rank: %v", msg.err)
+		case msg.place == 0:
+			return b, statusCmd("You have no run on this board")
+		}
+		return b.jumpToPlace(msg.place)
+	}
+	return b, nil
+}
+
+// rankLine is "Rank 12 of 3402" for the selected run, with how much of
+// the board is loaded while that isn't all of it
+func (b boardsModel) rankLine() string {
+	r, ok := b.selectedRun()
+	if !ok {
+		return ""
+	}
+	line := fmt.Sprintf("Rank %d of %d", r.Place, max(b.board.Pagination.Count, len(b.board.Runs)))
+	if b.more() {
+		line += fmt.Sprintf(" • %d loaded", len(b.board.Runs))
+	}
+	return line
+}
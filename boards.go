@@ -0,0 +1,580 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	boardRowStyle = lipgloss.NewStyle().
+			PaddingLeft(1)
+
+	boardSelectedRowStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#2C2A1C")).
+				Foreground(lipgloss.Color("#FFD700")).
+				PaddingLeft(1)
+
+	liveStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF5555")).
+			Bold(true)
+)
+
+type boardsView int
+
+const (
+	boardsGames boardsView = iota
+	boardsCategories
+	boardsBoard
+	boardsHistory
+	boardsDistribution
+	boardsInfo // moderators and submission rules
+)
+
+type gameDataMsg struct {
+	data *GameData
+	mods map[string]string // user ID -> name
+	err  error
+}
+
+type boardMsg struct {
+	board    *Leaderboard
+	key      string
+	previous *boardSnapshot // the board on the last visit
+	err      error
+}
+
+type liveRunnersMsg struct {
+	live map[string]TwitchStream
+	err  error
+}
+
+// boardsModel is the leaderboards tab: pick a followed game, then one of
+// its categories, then browse the board.
+type boardsModel struct {
+	client   *Client
+	games    *GameCache
+	twitch   *TwitchClient
+	followed []string
+	times    TimeFormat
+	flags    string
+
+	level        boardsView
+	selected     int
+	game         *GameData
+	mods         map[string]string
+	categories   []Category
+	category     Category
+	params       LeaderboardParams
+	board        *Leaderboard
+	baseline     *boardSnapshot // the board as last seen, for movement
+	baselineKey  string
+	history      []wrRecord
+	distribution []time.Duration
+	info         *gameInfo
+	infoFrom     boardsView              // where esc goes back to from the info
+	live         map[string]TwitchStream // by speedrun.com user ID
+	loading      bool
+	columnNames  map[string][]string // board_columns
+	colOffset    int                 // first variable column shown
+	width        int                 // of the screen, for fitting columns
+	fetching     bool                // more of the board, in the background
+	jumped       bool                // the selection moved far, for the viewport to follow
+	goals        []Goal
+	err          error
+}
+
+func newBoardsModel(client *Client, cfg Config) boardsModel {
+	b := boardsModel{
+		client:      client,
+		twitch:      NewTwitchClient(cfg.Twitch),
+		followed:    cfg.FollowedGames,
+		columnNames: cfg.BoardColumns,
+		goals:       cfg.Goals,
+		times:       cfg.TimeFormat,
+		flags:       cfg.CountryFlags,
+	}
+	b.games, b.err = NewGameCache(client)
+	return b
+}
+
+func (b boardsModel) loadGameCmd(game string) tea.Cmd {
+	client, games := b.client, b.games
+	return func() tea.Msg {
+		data, err := games.Get(game)
+		if err != nil {
+			return gameDataMsg{err: err}
+		}
+		// Only used to color names, so the board works without them
+		mods, err := client.GetModerators(data.Game.ID)
+		if err != nil {
+			log.Printf("boards: %v", err)
+		}
+		return gameDataMsg{data: data, mods: mods}
+	}
+}
+
+func (b boardsModel) loadBoardCmd() tea.Cmd {
+	client, params := b.client, b.params
+	return func() tea.Msg {
+		board, err := client.GetLeaderboard(params, 1)
+		if err != nil {
+			return boardMsg{err: err}
+		}
+		// Movement arrows are a nicety, so a snapshot failure isn't fatal
+		previous, err := swapBoardSnapshot(params, board)
+		if err != nil {
+			log.Printf("boards: %v", err)
+		}
+		return boardMsg{board: board, key: boardKey(params), previous: previous}
+	}
+}
+
+// liveRunnersCmd looks up which of the runners of runs are streaming
+func (b boardsModel) liveRunnersCmd(runs []Run) tea.Cmd {
+	if b.twitch == nil || len(runs) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, r := range runs {
+		for _, id := range r.PlayerIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	client, twitch := b.client, b.twitch
+	return func() tea.Msg {
+		live, err := liveRunners(client, twitch, ids)
+		return liveRunnersMsg{live: live, err: err}
+	}
+}
+
+// defaultParams picks the board the site shows first for a category: each
+// subcategory variable set to its default value.
+func defaultParams(data *GameData, cat Category) LeaderboardParams {
+	params := LeaderboardParams{GameID: data.Game.ID, CategoryID: cat.ID}
+	for _, v := range data.Variables {
+		if !v.IsSubcategory || (v.CategoryID != "" && v.CategoryID != cat.ID) {
+			continue
+		}
+		value := v.DefaultValue
+		if value == "" {
+			for _, val := range data.Values {
+				if val.VariableID == v.ID {
+					value = val.ID
+					break
+				}
+			}
+		}
+		if value != "" {
+			params.Values = append(params.Values, ValueFilter{VariableID: v.ID, ValueIDs: []string{value}})
+		}
+	}
+	return params
+}
+
+func (b boardsModel) update(msg tea.Msg) (boardsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case gameDataMsg:
+		b.loading = false
+		b.err = msg.err
+		if msg.err != nil {
+			return b, nil
+		}
+		b.game = msg.data
+		b.mods = msg.mods
+		b.categories = nil
+		for _, c := range msg.data.Categories {
+			if !c.IsPerLevel && !c.Archived {
+				b.categories = append(b.categories, c)
+			}
+		}
+		sort.SliceStable(b.categories, func(i, j int) bool {
+			if b.categories[i].IsMisc != b.categories[j].IsMisc {
+				return !b.categories[i].IsMisc
+			}
+			return b.categories[i].Pos < b.categories[j].Pos
+		})
+		b.level = boardsCategories
+		b.selected = 0
+
+	case boardMsg:
+		b.loading = false
+		b.err = msg.err
+		if msg.err != nil {
+			return b, nil
+		}
+		b.board = msg.board
+		// Refreshing keeps comparing against the board before this visit
+		if msg.key != b.baselineKey {
+			b.baseline, b.baselineKey = msg.previous, msg.key
+		}
+		b.live = nil
+		b.fetching = false
+		b.colOffset = 0
+		b.level = boardsBoard
+		b.selected = 0
+		return b, b.liveRunnersCmd(b.board.Runs)
+
+	case boardPagesMsg, myRankMsg:
+		return b.updatePages(msg)
+
+	case wrHistoryMsg:
+		b.loading = false
+		b.err = msg.err
+		if msg.err != nil {
+			return b, nil
+		}
+		b.history = msg.records
+		b.level = boardsHistory
+		b.selected = 0
+
+	case gameInfoMsg:
+		b.loading = false
+		b.err = msg.err
+		if msg.err != nil {
+			return b, nil
+		}
+		b.info = msg.info
+		b.infoFrom = b.level
+		b.level = boardsInfo
+		b.selected = 0
+
+	case distributionMsg:
+		b.loading = false
+		b.err = msg.err
+		if msg.err != nil {
+			return b, nil
+		}
+		b.distribution = msg.times
+		b.level = boardsDistribution
+		b.selected = 0
+
+	case liveRunnersMsg:
+		if msg.err != nil {
+			log.Printf("twitch: %v", msg.err)
+		}
+		// Later pages add to what's known
+		if msg.live != nil && b.live == nil {
+			b.live = make(map[string]TwitchStream)
+		}
+		for id, stream := range msg.live {
+			b.live[id] = stream
+		}
+
+	case tea.KeyMsg:
+		if b.loading {
+			return b, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			if b.selected > 0 {
+				b.selected--
+			}
+		case "down", "j":
+			if b.selected < b.length()-1 {
+				b.selected++
+			}
+			return b.prefetch()
+		case "left":
+			if b.level == boardsBoard {
+				b = b.scrollColumns(-1)
+			}
+		case "right":
+			if b.level == boardsBoard {
+				b = b.scrollColumns(1)
+			}
+		case "M":
+			if b.level == boardsBoard && !b.fetching {
+				b.fetching = true
+				return b, b.myRankCmd()
+			}
+		case "enter":
+			return b.open()
+		case "esc", "backspace":
+			b.err = nil
+			switch {
+			case b.level == boardsInfo:
+				b.level = b.infoFrom
+			case b.level > boardsBoard:
+				b.level = boardsBoard
+			case b.level > boardsGames:
+				b.level--
+			}
+			b.selected = 0
+		case "r":
+			if b.level == boardsBoard {
+				b.loading = true
+				return b, b.loadBoardCmd()
+			}
+		case "h":
+			if b.level == boardsBoard {
+				b.loading = true
+				return b, b.loadHistoryCmd()
+			}
+		case "d":
+			if b.level == boardsBoard {
+				b.loading = true
+				return b, b.loadDistributionCmd()
+			}
+		case "e":
+			return b, b.exportChartCmd()
+		case "i":
+			if b.level == boardsCategories || b.level == boardsBoard {
+				b.loading = true
+				return b, b.loadInfoCmd()
+			}
+		case "m":
+			if b.level >= boardsBoard && b.game != nil {
+				b.times = b.times.toggleMillis(b.game.Game.Milliseconds)
+			}
+		case "w":
+			if stream, ok := b.selectedStream(); ok {
+				openBrowser(stream.URL())
+			}
+		}
+	}
+
+	return b, nil
+}
+
+func (b boardsModel) length() int {
+	switch b.level {
+	case boardsGames:
+		return len(b.followed)
+	case boardsCategories:
+		return len(b.categories)
+	case boardsBoard:
+		if b.board != nil {
+			return len(b.board.Runs)
+		}
+	case boardsInfo:
+		return len(b.info.Moderators)
+	}
+	return 0
+}
+
+// openGame jumps straight to a game's categories, followed or not
+func (b boardsModel) openGame(game string) (boardsModel, tea.Cmd) {
+	if b.games == nil {
+		return b, nil
+	}
+	b.level = boardsGames
+	b.loading = true
+	b.err = nil
+	return b, b.loadGameCmd(game)
+}
+
+// open drills into the selected entry, or opens the run page on a board
+func (b boardsModel) open() (boardsModel, tea.Cmd) {
+	if b.selected >= b.length() {
+		return b, nil
+	}
+
+	switch b.level {
+	case boardsGames:
+		if b.games == nil {
+			return b, nil
+		}
+		b.loading = true
+		b.err = nil
+		return b, b.loadGameCmd(b.followed[b.selected])
+	case boardsCategories:
+		b.category = b.categories[b.selected]
+		b.params = defaultParams(b.game, b.category)
+		b.loading = true
+		b.err = nil
+		return b, b.loadBoardCmd()
+	case boardsBoard:
+		openBrowser(b.runURL(b.board.Runs[b.selected]))
+	case boardsInfo:
+		openBrowser(b.info.Moderators[b.selected].URL)
+	}
+	return b, nil
+}
+
+func (b boardsModel) runURL(r Run) string {
+	return fmt.Sprintf("https://www.speedrun.com/%s/runs/%s", b.game.Game.URL, r.ID)
+}
+
+func (b boardsModel) selectedRun() (Run, bool) {
+	if b.level != boardsBoard || b.board == nil || b.selected >= len(b.board.Runs) {
+		return Run{}, false
+	}
+	return b.board.Runs[b.selected], true
+}
+
+func (b boardsModel) selectedStream() (TwitchStream, bool) {
+	if b.level != boardsBoard || b.board == nil || b.selected >= len(b.board.Runs) {
+		return TwitchStream{}, false
+	}
+	for _, id := range b.board.Runs[b.selected].PlayerIDs {
+		if s, ok := b.live[id]; ok {
+			return s, true
+		}
+	}
+	return TwitchStream{}, false
+}
+
+// title describes where in the game > category > board path we are
+func (b boardsModel) title() string {
+	switch b.level {
+	case boardsCategories:
+		return b.game.Game.Name
+	case boardsInfo:
+		return b.game.Game.Name + " › Moderation"
+	case boardsBoard, boardsHistory, boardsDistribution:
+		title := b.game.Game.Name + " › " + b.category.Name
+		for _, f := range b.params.Values {
+			for _, val := range b.game.Values {
+				if val.ID == f.ValueIDs[0] {
+					title += " / " + val.Name
+				}
+			}
+		}
+		switch b.level {
+		case boardsHistory:
+			title += " › WR history"
+		case boardsDistribution:
+			title += " › Times"
+		}
+		return title
+	}
+	return "Followed games"
+}
+
+func (b boardsModel) view() string {
+	if b.err != nil {
+		return fmt.Sprintf("Error: %v", b.err)
+	}
+	if b.loading {
+		return "Loading..."
+	}
+	switch b.level {
+	case boardsHistory:
+		return b.historyView()
+	case boardsDistribution:
+		return b.distributionView()
+	case boardsInfo:
+		return b.infoView()
+	}
+
+	var rows []string
+	switch b.level {
+	case boardsGames:
+		if len(b.followed) == 0 {
+			return "Add games to followed_games in your config to browse their leaderboards"
+		}
+		rows = b.followed
+	case boardsCategories:
+		for _, c := range b.categories {
+			name := c.Name
+			if c.IsMisc {
+				name += " (misc)"
+			}
+			rows = append(rows, name)
+		}
+	case boardsBoard:
+		if len(b.board.Runs) == 0 {
+			return "No runs on this board yet"
+		}
+		cols, _ := b.visibleColumns()
+		for _, r := range b.board.Runs {
+			rows = append(rows, b.renderRun(r, cols))
+		}
+	}
+
+	var out strings.Builder
+	if b.level == boardsBoard {
+		header := b.rankLine()
+		if b.baseline != nil {
+			header += " • changes since " + b.baseline.Taken.Local().Format("Mon Jan 2 15:04")
+		}
+		out.WriteString(urlStyle.Render(header))
+		if goal := b.goalLine(); goal != "" {
+			out.WriteString(urlStyle.Render(" • ") + goal)
+		}
+		out.WriteString("\n")
+		if cols, more := b.visibleColumns(); len(cols) > 0 {
+			out.WriteString(urlStyle.Render(b.columnHeader(cols, more)))
+			out.WriteString("\n")
+		}
+	}
+	for i, row := range rows {
+		style := boardRowStyle
+		if i == b.selected {
+			style = boardSelectedRowStyle
+		}
+		out.WriteString(style.Render(row))
+		out.WriteString("\n")
+	}
+	if b.level == boardsBoard && b.fetching {
+		out.WriteString(urlStyle.Render(" Loading more...") + "\n")
+	}
+	return out.String()
+}
+
+// formatTime renders a run time in the configured format, with
+// milliseconds if the game times them
+func (b boardsModel) formatTime(r Run) string {
+	return b.times.render(r.Duration(), b.millis())
+}
+
+func (b boardsModel) millis() bool {
+	return b.game != nil && b.game.Game.Milliseconds
+}
+
+func (b boardsModel) renderRun(r Run, cols []boardColumn) string {
+	date := ""
+	if r.Date != 0 {
+		date = time.Unix(r.Date, 0).Format("2006-01-02")
+	}
+
+	runners := renderRunners(b.board.Runners(r, b.mods), b.flags, 36)
+	badge := padBadge(platformBadge(b.platformName(r.PlatformID), r.Emulator))
+	row := fmt.Sprintf("%4d %s %s %12s  %s%s  %s", r.Place, b.baseline.movement(r), runners, b.formatTime(r), badge, b.renderColumns(r, cols), date)
+	for _, id := range r.PlayerIDs {
+		if _, ok := b.live[id]; ok {
+			row += "  " + liveStyle.Render("● LIVE")
+			break
+		}
+	}
+	return row
+}
+
+func (b boardsModel) help() string {
+	switch b.level {
+	case boardsCategories:
+		return "j/k navigate • enter board • i moderation • esc back"
+	case boardsBoard:
+		help := "j/k navigate • ←/→ scroll columns • M my rank • enter open run • v play video • b pin • y/Y copy • ! report • h WR history • d time distribution • i moderation • m milliseconds • r refresh • esc back"
+		if b.twitch != nil {
+			help += " • w watch live • c chat"
+		}
+		return help
+	case boardsHistory, boardsDistribution:
+		return "e export chart • m milliseconds • esc back"
+	case boardsInfo:
+		return "j/k navigate • enter open profile • esc back"
+	}
+	return "j/k navigate • enter categories"
+}
+
+// truncate shortens s to n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
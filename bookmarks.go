@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Notes taken while watching a run's video, by run ID
+const bookmarksFile = "bookmarks.json"
+
+// Bookmark is a moment in a run's video worth a second look
+type Bookmark struct {
+	At    time.Duration `json:"at"`
+	Note  string        `json:"note"`
+	Added time.Time     `json:"added"`
+}
+
+func loadBookmarks() (map[string][]Bookmark, error) {
+	all := make(map[string][]Bookmark)
+	if err := loadState(bookmarksFile, &all); err != nil {
+		return map[string][]Bookmark{}, err
+	}
+	return all, nil
+}
+
+// addBookmark saves a bookmark, returning the run's bookmarks in video order
+func addBookmark(runID string, b Bookmark) ([]Bookmark, error) {
+	all, err := loadBookmarks()
+	if err != nil {
+		return nil, err
+	}
+	marks := append(all[runID], b)
+	slices.SortStableFunc(marks, func(x, y Bookmark) int { return int(x.At - y.At) })
+	all[runID] = marks
+	if err := saveState(bookmarksFile, all); err != nil {
+		return nil, err
+	}
+	return marks, nil
+}
+
+// bookmarkNotes are a run's bookmarks as text for the verification notes,
+// one "12:34 possible splice" per line
+func bookmarkNotes(marks []Bookmark) string {
+	lines := make([]string, len(marks))
+	for i, b := range marks {
+		lines[i] = formatClock(b.At) + " " + b.Note
+	}
+	return strings.Join(lines, "\n")
+}
+
+// copyBookmarksCmd copies a run's notes to paste into its verification
+func copyBookmarksCmd(marks []Bookmark) tea.Cmd {
+	return func() tea.Msg {
+		if len(marks) == 0 {
+			return statusMsg("No video notes on this run, n adds one while it plays")
+		}
+		if err := copyToClipboard(bookmarkNotes(marks)); err != nil {
+			return statusMsg(fmt.Sprintf("Copying failed: %v", err))
+		}
+		return statusMsg(fmt.Sprintf("Copied %d video notes", len(marks)))
+	}
+}
+
+type bookmarkPositionMsg struct {
+	run QueueRun
+	at  time.Duration
+	err error
+}
+
+// bookmarkCmd asks mpv where the video is, to start a bookmark there
+func bookmarkCmd(run QueueRun) tea.Cmd {
+	return func() tea.Msg {
+		at, err := mpvPosition()
+		return bookmarkPositionMsg{run: run, at: at, err: err}
+	}
+}
+
+// bookmarkDialog takes the note for a bookmark at a moment already picked,
+// so the video can keep playing while it's typed
+type bookmarkDialog struct {
+	run   QueueRun
+	at    time.Duration
+	input textinput.Model
+}
+
+func newBookmarkDialog(run QueueRun, at time.Duration) (*bookmarkDialog, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "possible splice"
+	input.CharLimit = 200
+	input.Width = 60
+	d := &bookmarkDialog{run: run, at: at, input: input}
+	return d, d.input.Focus()
+}
+
+type bookmarkedMsg struct {
+	run   QueueRun
+	marks []Bookmark
+	err   error
+}
+
+// update returns a nil dialog once it's closed, with the command saving
+// the bookmark if one was entered
+func (d *bookmarkDialog) update(msg tea.Msg) (*bookmarkDialog, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "enter":
+			note := strings.TrimSpace(d.input.Value())
+			if note == "" {
+				note = d.input.Placeholder
+			}
+			run, b := d.run, Bookmark{At: d.at, Note: note, Added: time.Now()}
+			return nil, func() tea.Msg {
+				marks, err := addBookmark(run.ID, b)
+				return bookmarkedMsg{run: run, marks: marks, err: err}
+			}
+		case "esc":
+			return nil, nil
+		}
+	}
+	var cmd tea.Cmd
+	d.input, cmd = d.input.Update(msg)
+	return d, cmd
+}
+
+func (d *bookmarkDialog) view() string {
+	return alertBannerStyle.Render(fmt.Sprintf("Bookmark at %s in the run by %s\n\n%s",
+		formatClock(d.at), strings.Join(d.run.Players, ", "), d.input.View()))
+}
+
+func (d *bookmarkDialog) help() string {
+	return "enter save • esc cancel"
+}
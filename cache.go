@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	appName = "speedrunner-tui"
+
+	// Game metadata rarely changes; use refresh-cache after a game is edited
+	staticDataTTL = 7 * 24 * time.Hour
+)
+
+type cacheEntry struct {
+	Fetched time.Time       `json:"fetched"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// diskCache stores JSON blobs under the user cache directory, one file per key
+type diskCache struct {
+	dir string
+}
+
+func openCache(name string) (*diskCache, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating cache dir: %w", err)
+	}
+
+	dir := filepath.Join(base, appName, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache dir: %w", err)
+	}
+
+	return &diskCache{dir: dir}, nil
+}
+
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// load decodes the entry for key into v. It reports false when the entry is
+// missing, unreadable or older than ttl.
+func (c *diskCache) load(key string, ttl time.Duration, v any) bool {
+	raw, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return false
+	}
+	if ttl > 0 && time.Since(entry.Fetched) > ttl {
+		return false
+	}
+
+	return json.Unmarshal(entry.Data, v) == nil
+}
+
+func (c *diskCache) store(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	raw, err := json.Marshal(cacheEntry{Fetched: time.Now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a half-written entry behind
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return os.Rename(tmp, c.path(key))
+}
+
+func (c *diskCache) keys() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading cache dir: %w", err)
+	}
+
+	var keys []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GameCache serves game metadata from disk and only hits the API when the
+// cached copy is missing or has expired.
+type GameCache struct {
+	client *Client
+	disk   *diskCache
+}
+
+func NewGameCache(client *Client) (*GameCache, error) {
+	disk, err := openCache("games")
+	if err != nil {
+		return nil, err
+	}
+	return &GameCache{client: client, disk: disk}, nil
+}
+
+func (gc *GameCache) Get(game string) (*GameData, error) {
+	var data GameData
+	if gc.disk.load(game, staticDataTTL, &data) {
+		return &data, nil
+	}
+	return gc.fetch(game)
+}
+
+func (gc *GameCache) fetch(game string) (*GameData, error) {
+	data, err := gc.client.GetGameData(game)
+	if err != nil {
+		return nil, err
+	}
+	if err := gc.disk.store(game, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Refresh re-downloads the given games, or every cached game when none are
+// given, regardless of age. It returns the games that were refreshed.
+func (gc *GameCache) Refresh(games ...string) ([]string, error) {
+	if len(games) == 0 {
+		var err error
+		if games, err = gc.disk.keys(); err != nil {
+			return nil, err
+		}
+	}
+
+	jobs := make([]Job, len(games))
+	for i, game := range games {
+		jobs[i] = Job{
+			Name: game,
+			Host: hostOf(gc.client.baseURL),
+			Run: func() error {
+				_, err := gc.fetch(game)
+				return err
+			},
+		}
+	}
+
+	if err := NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+func runRefreshCache(client *Client, games []string) error {
+	cache, err := NewGameCache(client)
+	if err != nil {
+		return err
+	}
+
+	refreshed, err := cache.Refresh(games...)
+	if err != nil {
+		return err
+	}
+
+	if len(refreshed) == 0 {
+		fmt.Println("Cache is empty, nothing to refresh")
+		return nil
+	}
+	fmt.Printf("Refreshed %d game(s): %s\n", len(refreshed), strings.Join(refreshed, ", "))
+	return nil
+}
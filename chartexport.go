@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Exported charts are sized for a chat embed
+const (
+	chartWidth   = 800
+	chartHeight  = 400
+	chartMargin  = 90
+	chartLineGap = 16
+)
+
+var (
+	chartBackground = color.RGBA{0x2B, 0x2D, 0x31, 0xFF} // Discord's dark theme
+	chartAxis       = color.RGBA{0x66, 0x66, 0x66, 0xFF}
+	chartText       = color.RGBA{0xDD, 0xDD, 0xDD, 0xFF}
+	chartAccent     = color.RGBA{0xFF, 0xD7, 0x00, 0xFF} // the app's gold
+)
+
+// chartCanvas is a PNG being drawn
+type chartCanvas struct {
+	img *image.RGBA
+}
+
+func newChartCanvas(title string) chartCanvas {
+	c := chartCanvas{img: image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))}
+	draw.Draw(c.img, c.img.Bounds(), image.NewUniform(chartBackground), image.Point{}, draw.Src)
+	// The built in font only has ASCII
+	c.text(chartMargin, chartMargin/2, strings.ReplaceAll(title, "›", ">"), chartText)
+	// Axes
+	c.rect(chartMargin, chartMargin, chartMargin+1, chartHeight-chartMargin, chartAxis)
+	c.rect(chartMargin, chartHeight-chartMargin, chartWidth-chartMargin, chartHeight-chartMargin+1, chartAxis)
+	return c
+}
+
+func (c chartCanvas) rect(x0, y0, x1, y1 int, col color.Color) {
+	draw.Draw(c.img, image.Rect(x0, y0, x1, y1), image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+// text draws s with its baseline at y
+func (c chartCanvas) text(x, y int, s string, col color.Color) {
+	d := font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(col),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+// textRight draws s ending at x
+func (c chartCanvas) textRight(x, y int, s string, col color.Color) {
+	c.text(x-font.MeasureString(basicfont.Face7x13, s).Ceil(), y, s, col)
+}
+
+func (c chartCanvas) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("encoding chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// The plot area inside the axes
+const (
+	plotLeft   = chartMargin + 2
+	plotRight  = chartWidth - chartMargin
+	plotTop    = chartMargin + chartLineGap
+	plotBottom = chartHeight - chartMargin
+)
+
+// wrHistoryPNG draws the record progression as a step chart, time on the
+// x axis and the standing record on the y axis
+func wrHistoryPNG(title string, records []wrRecord, now time.Time, render func(time.Duration) string) ([]byte, error) {
+	c := newChartCanvas(title)
+	if len(records) == 0 {
+		return c.encode()
+	}
+	start, slowest, best := records[0].Date, records[0].Time, records[len(records)-1].Time
+	span := max(now.Sub(start), time.Hour)
+	x := func(t time.Time) int {
+		return plotLeft + int(float64(plotRight-plotLeft)*float64(t.Sub(start))/float64(span))
+	}
+	y := func(d time.Duration) int {
+		if slowest == best {
+			return (plotTop + plotBottom) / 2
+		}
+		return plotTop + int(float64(plotBottom-plotTop)*float64(slowest-d)/float64(slowest-best))
+	}
+
+	for i, r := range records {
+		end := now
+		if i+1 < len(records) {
+			end = records[i+1].Date
+		}
+		// The record holds until the next one, then drops to it
+		c.rect(x(r.Date), y(r.Time)-1, x(end)+1, y(r.Time)+1, chartAccent)
+		if i+1 < len(records) {
+			c.rect(x(end)-1, y(r.Time), x(end)+1, y(records[i+1].Time)+1, chartAccent)
+		}
+	}
+
+	c.textRight(chartMargin-6, y(slowest)+4, render(slowest), chartText)
+	c.textRight(chartMargin-6, y(best)+4, render(best), chartText)
+	c.text(plotLeft, plotBottom+chartLineGap, start.Format("Jan 2006"), chartText)
+	c.textRight(plotRight, plotBottom+chartLineGap, now.Format("Jan 2006"), chartText)
+	c.text(plotLeft, plotBottom+2*chartLineGap, fmt.Sprintf("%d records", len(records)), chartAxis)
+	return c.encode()
+}
+
+// histogramPNG draws the same buckets as the in-app histogram as bars
+func histogramPNG(title string, times []time.Duration, render func(time.Duration) string) ([]byte, error) {
+	c := newChartCanvas(title)
+	buckets, step, slower := bucketTimes(times, histogramBuckets)
+	if len(buckets) == 0 {
+		return c.encode()
+	}
+	counts := make([]int, 0, len(buckets)+1)
+	for _, bk := range buckets {
+		counts = append(counts, bk.Count)
+	}
+	if slower > 0 {
+		counts = append(counts, slower)
+	}
+	top := 1
+	for _, n := range counts {
+		top = max(top, n)
+	}
+
+	width := (plotRight - plotLeft) / len(counts)
+	for i, n := range counts {
+		h := (plotBottom - plotTop) * n / top
+		col := color.Color(chartAccent)
+		if i == len(buckets) {
+			col = chartAxis
+		}
+		c.rect(plotLeft+i*width+2, plotBottom-h, plotLeft+(i+1)*width-2, plotBottom, col)
+	}
+
+	c.textRight(chartMargin-6, plotTop+4, fmt.Sprint(top), chartText)
+	c.text(plotLeft, plotBottom+chartLineGap, render(buckets[0].From), chartText)
+	c.textRight(plotLeft+len(buckets)*width, plotBottom+chartLineGap, render(buckets[len(buckets)-1].From+step), chartText)
+	if slower > 0 {
+		c.textRight(plotRight, plotBottom+2*chartLineGap, "slower", chartAxis)
+	}
+	c.text(plotLeft, plotBottom+2*chartLineGap, fmt.Sprintf("%d runs", len(times)), chartAxis)
+	return c.encode()
+}
+
+var chartNameSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// chartFileName is a file name from a chart's title, like
+// "super-mario-64-120-star-wr-history"
+func chartFileName(title string) string {
+	name := chartNameSeparators.ReplaceAllString(strings.ToLower(title), "-")
+	return strings.Trim(name, "-")
+}
+
+// exportChartCmd writes the chart on screen next to where the app was
+// started, as text and as a PNG
+func (b boardsModel) exportChartCmd() tea.Cmd {
+	var (
+		text    = ansi.Strip(b.view())
+		title   = b.title()
+		drawPNG func() ([]byte, error)
+	)
+	render := func(d time.Duration) string { return b.times.render(d, b.millis()) }
+	switch b.level {
+	case boardsHistory:
+		records := b.history
+		drawPNG = func() ([]byte, error) { return wrHistoryPNG(title, records, time.Now(), render) }
+	case boardsDistribution:
+		times := b.distribution
+		drawPNG = func() ([]byte, error) { return histogramPNG(title, times, render) }
+	default:
+		return nil
+	}
+
+	return func() tea.Msg {
+		base := chartFileName(title)
+		chart, err := drawPNG()
+		if err != nil {
+			return statusMsg(fmt.Sprintf("Export failed: %v", err))
+		}
+		if err := os.WriteFile(base+".txt", []byte(title+"\n\n"+text), 0o644); err != nil {
+			return statusMsg(fmt.Sprintf("Export failed: %v", err))
+		}
+		if err := os.WriteFile(base+".png", chart, 0o644); err != nil {
+			return statusMsg(fmt.Sprintf("Export failed: %v", err))
+		}
+		dir, _ := filepath.Abs(".")
+		return statusMsg(fmt.Sprintf("Saved %s.txt and %s.png in %s", base, base, dir))
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	twitchChatAddr = "irc.chat.twitch.tv:6697"
+	chatWidth      = 40  // of the pane, border included
+	chatKeep       = 200 // messages kept for scrolling back over a resize
+)
+
+var (
+	chatPaneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#874BFD")).
+			Padding(0, 1)
+
+	chatNameStyle = lipgloss.NewStyle().
+			Bold(true)
+)
+
+// chatLine is one chat message, or a note from the pane itself with no name
+type chatLine struct {
+	name  string
+	color string // the chatter's chosen color, "#RRGGBB"
+	text  string
+}
+
+type chatMsg struct {
+	pane   *chatPane
+	line   chatLine
+	err    error
+	closed bool
+}
+
+// chatPane follows a Twitch channel's chat read-only. Twitch lets anyone
+// read chat anonymously, as a justinfan user, so no credentials are needed.
+type chatPane struct {
+	channel  string
+	incoming chan chatMsg
+	done     chan struct{}
+	once     sync.Once
+	lines    []chatLine
+	err      error
+}
+
+// openChat connects to a channel's chat, returning the command that waits
+// for the first message
+func openChat(channel string) (*chatPane, tea.Cmd) {
+	c := &chatPane{
+		channel:  strings.ToLower(channel),
+		incoming: make(chan chatMsg, 64),
+		done:     make(chan struct{}),
+	}
+	go c.run()
+	return c, c.wait()
+}
+
+func (c *chatPane) run() {
+	defer close(c.incoming)
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", twitchChatAddr, appTLS)
+	if err != nil {
+		c.send(chatMsg{err: fmt.Errorf("connecting to chat: %w", err)})
+		return
+	}
+	// Closing the pane hangs up, which ends the read below
+	go func() {
+		<-c.done
+		conn.Close()
+	}()
+
+	fmt.Fprintf(conn, "CAP REQ :twitch.tv/tags\r\n")
+	fmt.Fprintf(conn, "PASS SCHMOOPIIE\r\n")
+	fmt.Fprintf(conn, "NICK justinfan%d\r\n", 10000+rand.Intn(90000))
+	fmt.Fprintf(conn, "JOIN #%s\r\n", c.channel)
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if server, ok := strings.CutPrefix(line, "PING "); ok {
+			fmt.Fprintf(conn, "PONG %s\r\n", server)
+			continue
+		}
+		if msg, ok := parseChatLine(line); ok && !c.send(chatMsg{line: msg}) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		c.send(chatMsg{err: fmt.Errorf("reading chat: %w", err)})
+	}
+}
+
+// send hands a message to the app, false once the pane is closed
+func (c *chatPane) send(msg chatMsg) bool {
+	select {
+	case c.incoming <- msg:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// parseChatLine picks the messages out of Twitch's IRC: chat, /me
+// actions, and the notices Twitch sends when it won't let us in
+func parseChatLine(raw string) (chatLine, bool) {
+	tags := make(map[string]string)
+	if rest, ok := strings.CutPrefix(raw, "@"); ok {
+		var tagPart string
+		tagPart, raw, _ = strings.Cut(rest, " ")
+		for _, t := range strings.Split(tagPart, ";") {
+			k, v, _ := strings.Cut(t, "=")
+			tags[k] = v
+		}
+	}
+	prefix := ""
+	if rest, ok := strings.CutPrefix(raw, ":"); ok {
+		prefix, raw, _ = strings.Cut(rest, " ")
+	}
+	command, params, _ := strings.Cut(raw, " ")
+	_, text, _ := strings.Cut(params, " :")
+
+	switch command {
+	case "PRIVMSG":
+		name := tags["display-name"]
+		if name == "" {
+			name, _, _ = strings.Cut(prefix, "!")
+		}
+		if action, ok := strings.CutPrefix(text, "\x01ACTION "); ok {
+			text = "* " + strings.TrimSuffix(action, "\x01")
+		}
+		return chatLine{name: stripControl(name), color: tags["color"], text: stripControl(text)}, true
+	case "NOTICE", "RECONNECT":
+		if text == "" {
+			text = "Twitch closed the chat connection"
+		}
+		return chatLine{text: stripControl(text)}, true
+	}
+	return chatLine{}, false
+}
+
+// stripControl drops control characters, so anyone in chat can't send
+// escape sequences to the terminal
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// wait delivers the next message, and a closed message once the
+// connection ends
+func (c *chatPane) wait() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-c.incoming
+		if !ok {
+			return chatMsg{pane: c, closed: true}
+		}
+		msg.pane = c
+		return msg
+	}
+}
+
+// add takes in a message for this pane, returning the command for the
+// next one while the connection is up
+func (c *chatPane) add(msg chatMsg) tea.Cmd {
+	switch {
+	case msg.closed:
+		c.lines = append(c.lines, chatLine{text: "Chat disconnected"})
+		return nil
+	case msg.err != nil:
+		c.err = msg.err
+	default:
+		c.lines = append(c.lines, msg.line)
+		if len(c.lines) > chatKeep {
+			c.lines = c.lines[len(c.lines)-chatKeep:]
+		}
+	}
+	return c.wait()
+}
+
+func (c *chatPane) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// view is the pane at the given height, newest messages at the bottom
+func (c *chatPane) view(height int) string {
+	inner := chatWidth - 4
+	rows := max(height-2, 1)
+	wrap := lipgloss.NewStyle().Width(inner)
+
+	var out []string
+	switch {
+	case c.err != nil:
+		out = strings.Split(wrap.Render(c.err.Error()), "\n")
+	case len(c.lines) == 0:
+		out = []string{urlStyle.Render("Connecting to chat...")}
+	}
+	for i := len(c.lines) - 1; i >= 0 && len(out) < rows-1; i-- {
+		l := c.lines[i]
+		text := urlStyle.Render(l.text)
+		if l.name != "" {
+			name := chatNameStyle
+			if l.color != "" {
+				name = name.Foreground(lipgloss.Color(l.color))
+			}
+			text = name.Render(l.name) + ": " + l.text
+		}
+		out = append(strings.Split(wrap.Render(text), "\n"), out...)
+	}
+	if len(out) > rows-1 {
+		out = out[len(out)-(rows-1):]
+	}
+	title := chatNameStyle.Render("#" + c.channel + " chat")
+	body := lipgloss.JoinVertical(lipgloss.Left, append([]string{title}, out...)...)
+	return chatPaneStyle.Width(chatWidth - 2).Height(rows).Render(body)
+}
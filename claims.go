@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Claims nobody released, say from a crashed session, lapse after this
+const claimTTL = 2 * time.Hour
+
+var (
+	ownClaimStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#3B82F6"))
+	otherClaimStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9F43")).Bold(true)
+)
+
+// Claim marks a pending run as being looked at by a moderator, so others
+// sharing the claims file leave it to them
+type Claim struct {
+	Game string    `json:"game"` // ID, for pruning runs that left the queue
+	By   string    `json:"by"`
+	At   time.Time `json:"at"`
+}
+
+// claimsPath is the configured claims file, or claims.json next to the
+// config
+func claimsPath(configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "claims.json"), nil
+}
+
+// loadClaims reads the claims file by run ID, leaving out lapsed claims.
+// A missing file has no claims.
+func loadClaims(path string) (map[string]Claim, error) {
+	claims := make(map[string]Claim)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return claims, nil
+		}
+		return nil, fmt.Errorf("reading claims: %w", err)
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for id, c := range claims {
+		if time.Since(c.At) > claimTTL {
+			delete(claims, id)
+		}
+	}
+	return claims, nil
+}
+
+// saveClaims replaces the claims file atomically, so a moderator reading
+// it over a synced folder never sees half of it
+func saveClaims(path string, claims map[string]Claim) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating claims dir: %w", err)
+	}
+	raw, err := json.MarshalIndent(claims, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding claims: %w", err)
+	}
+	if err := os.WriteFile(path+".tmp", raw, 0o644); err != nil {
+		return fmt.Errorf("writing claims: %w", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// pruneClaims drops claims on runs of the loaded games that are no longer
+// pending, verified or rejected by whoever. Claims on other moderators'
+// games are left for them.
+func pruneClaims(path string, claims map[string]Claim, games []Game, runs []QueueRun) error {
+	loaded := make(map[string]bool, len(games))
+	for _, g := range games {
+		loaded[g.ID] = true
+	}
+	pending := make(map[string]bool, len(runs))
+	for _, r := range runs {
+		pending[r.ID] = true
+	}
+	changed := false
+	for id, c := range claims {
+		if loaded[c.Game] && !pending[id] {
+			delete(claims, id)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return saveClaims(path, claims)
+}
+
+type claimsMsg struct {
+	claims map[string]Claim
+	status string
+	err    error
+}
+
+// toggleClaimCmd claims the run for me, or releases it if I already had
+// it. The file is read fresh so claims made since the queue loaded count.
+func toggleClaimCmd(path string, run QueueRun, me string) tea.Cmd {
+	return func() tea.Msg {
+		claims, err := loadClaims(path)
+		if err != nil {
+			return claimsMsg{err: err}
+		}
+		who := strings.Join(run.Players, ", ")
+		c, ok := claims[run.ID]
+		switch {
+		case ok && c.By != me:
+			return claimsMsg{claims: claims, status: fmt.Sprintf("Already claimed by %s %s ago", c.By, formatAge(time.Since(c.At)))}
+		case ok:
+			delete(claims, run.ID)
+		default:
+			claims[run.ID] = Claim{Game: run.GameID, By: me, At: time.Now()}
+		}
+		if err := saveClaims(path, claims); err != nil {
+			return claimsMsg{err: err}
+		}
+		if ok {
+			return claimsMsg{claims: claims, status: fmt.Sprintf("Released run by %s", who)}
+		}
+		return claimsMsg{claims: claims, status: fmt.Sprintf("Claimed run by %s", who)}
+	}
+}
+
+// otherClaim is someone else's claim on a run
+func (q queueModel) otherClaim(r QueueRun) (Claim, bool) {
+	c, ok := q.claims[r.ID]
+	if !ok || c.By == q.me || time.Since(c.At) > claimTTL {
+		return Claim{}, false
+	}
+	return c, true
+}
+
+// claimLine is shown under a claimed run in the queue
+func (q queueModel) claimLine(r QueueRun) string {
+	c, ok := q.claims[r.ID]
+	if !ok || time.Since(c.At) > claimTTL {
+		return ""
+	}
+	ago := formatAge(time.Since(c.At))
+	if c.By == q.me {
+		return ownClaimStyle.Render(fmt.Sprintf("claimed by you %s ago", ago))
+	}
+	return otherClaimStyle.Render(fmt.Sprintf("claimed by %s %s ago", c.By, ago))
+}
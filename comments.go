@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Comment is one comment in a forum thread or under a run
+type Comment struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId"`
+	Text   string `json:"text"`
+	Date   int64  `json:"date"`
+}
+
+// CommentPage is one page of the comments under a run, oldest first
+type CommentPage struct {
+	Comments   []Comment  `json:"commentList"`
+	Users      []Player   `json:"userList"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// GetRunComments fetches a page of the comments under a run, counting from 1
+func (c *Client) GetRunComments(runID string, page int) (*CommentPage, error) {
+	body := struct {
+		ItemType string `json:"itemType"`
+		ItemID   string `json:"itemId"`
+		Page     int    `json:"page"`
+	}{
+		ItemType: "run",
+		ItemID:   runID,
+		Page:     page,
+	}
+
+	var result CommentPage
+	if err := c.post("GetCommentList", body, &result); err != nil {
+		return nil, fmt.Errorf("fetching comments: %w", err)
+	}
+	return &result, nil
+}
+
+// PostRunComment adds a comment under a run
+func (c *Client) PostRunComment(runID, text string) error {
+	body := struct {
+		ItemType string `json:"itemType"`
+		ItemID   string `json:"itemId"`
+		Text     string `json:"text"`
+	}{
+		ItemType: "run",
+		ItemID:   runID,
+		Text:     text,
+	}
+	if err := c.write("PutComment", body, nil); err != nil {
+		return fmt.Errorf("commenting: %w", err)
+	}
+	return nil
+}
+
+// commentAuthor names a comment's author, or gives their ID when the
+// page didn't include them
+func commentAuthor(users []Player, c Comment) string {
+	for _, u := range users {
+		if u.ID == c.UserID {
+			return u.Name
+		}
+	}
+	return c.UserID
+}
+
+// writeComment renders a comment the way threads and runs show them
+func writeComment(b *strings.Builder, author string, c Comment) {
+	b.WriteString(titleStyle.Render(author))
+	b.WriteString(urlStyle.Render("  " + time.Unix(c.Date, 0).Local().Format("2006-01-02 15:04")))
+	b.WriteString("\n" + strings.TrimSpace(c.Text) + "\n\n")
+}
+
+type runCommentsMsg struct {
+	runID string
+	page  *CommentPage
+	err   error
+}
+
+type commentPostedMsg struct {
+	runID  string
+	text   string
+	err    error
+	queued bool // failed, but waiting in the retry queue
+}
+
+// commentThread is the discussion under a run, a page at a time, with a
+// box for replying
+type commentThread struct {
+	runID    string
+	page     *CommentPage
+	loading  bool
+	err      error
+	input    textinput.Model
+	replying bool
+	sending  bool
+}
+
+func newCommentThread(runID string) *commentThread {
+	input := textinput.New()
+	input.Placeholder = "reply"
+	input.CharLimit = 1000
+	input.Width = 70
+	return &commentThread{runID: runID, input: input, loading: true}
+}
+
+func (t *commentThread) loadCmd(client *Client, page int) tea.Cmd {
+	runID := t.runID
+	return func() tea.Msg {
+		p, err := client.GetRunComments(runID, page)
+		return runCommentsMsg{runID: runID, page: p, err: err}
+	}
+}
+
+// openComments is the comment thread of the run open in the detail view
+func (m model) openComments() *commentThread {
+	if m.detail == nil {
+		return nil
+	}
+	return m.detail.comments
+}
+
+func postCommentCmd(client *Client, runID, text string) tea.Cmd {
+	return func() tea.Msg {
+		return commentPostedMsg{runID: runID, text: text, err: client.PostRunComment(runID, text)}
+	}
+}
+
+// current is the page on screen, 1 before anything has loaded
+func (t *commentThread) current() int {
+	if t.page == nil || t.page.Pagination.Page == 0 {
+		return 1
+	}
+	return t.page.Pagination.Page
+}
+
+// lastPage is where a new reply lands
+func (t *commentThread) lastPage() int {
+	if t.page == nil {
+		return 1
+	}
+	p := t.page.Pagination
+	if p.Per == 0 {
+		return max(p.Pages, 1)
+	}
+	return max((p.Count+p.Per)/p.Per, 1)
+}
+
+// update takes the thread's own messages and, while the reply box is
+// open, every key
+func (t *commentThread) update(msg tea.Msg, client *Client) tea.Cmd {
+	switch msg := msg.(type) {
+	case runCommentsMsg:
+		if msg.runID != t.runID {
+			return nil
+		}
+		t.loading = false
+		t.err = msg.err
+		if msg.err == nil {
+			t.page = msg.page
+		}
+	case commentPostedMsg:
+		if msg.runID != t.runID {
+			return nil
+		}
+		t.sending = false
+		if msg.queued {
+			// Sending it again would post it twice
+			t.input.SetValue("")
+			return statusCmd("Couldn't post the comment yet, will retry: %v", msg.err)
+		}
+		if msg.err != nil {
+			// The reply stays in the box to try again
+			t.replying = true
+			t.input.Focus()
+			return statusCmd("Couldn't post the comment: %v", msg.err)
+		}
+		t.input.SetValue("")
+		t.loading = true
+		return tea.Batch(t.loadCmd(client, t.lastPage()), statusCmd("Comment posted"))
+	case tea.KeyMsg:
+		if t.replying {
+			switch msg.String() {
+			case "enter":
+				text := strings.TrimSpace(t.input.Value())
+				if text == "" {
+					return nil
+				}
+				t.replying = false
+				t.sending = true
+				t.input.Blur()
+				return postCommentCmd(client, t.runID, text)
+			case "esc":
+				t.replying = false
+				t.input.Blur()
+				return nil
+			}
+			var cmd tea.Cmd
+			t.input, cmd = t.input.Update(msg)
+			return cmd
+		}
+		if t.loading || t.sending {
+			return nil
+		}
+		switch msg.String() {
+		case "c":
+			t.replying = true
+			return t.input.Focus()
+		case "]":
+			if t.page != nil && t.current() < t.page.Pagination.Pages {
+				t.loading = true
+				return t.loadCmd(client, t.current()+1)
+			}
+		case "[":
+			if t.current() > 1 {
+				t.loading = true
+				return t.loadCmd(client, t.current()-1)
+			}
+		}
+	default:
+		// Cursor blinks
+		if t.replying {
+			var cmd tea.Cmd
+			t.input, cmd = t.input.Update(msg)
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (t *commentThread) view() string {
+	var b strings.Builder
+	header := "Comments"
+	if t.page != nil && t.page.Pagination.Pages > 1 {
+		header += fmt.Sprintf(" (page %d/%d)", t.current(), t.page.Pagination.Pages)
+	}
+	b.WriteString(titleStyle.Render(header) + "\n\n")
+
+	switch {
+	case t.loading:
+		b.WriteString("Loading comments...\n")
+	case t.err != nil:
+		b.WriteString(fmt.Sprintf("Error: %v\n", t.err))
+	case t.page == nil || len(t.page.Comments) == 0:
+		b.WriteString(urlStyle.Render("No comments") + "\n")
+	default:
+		for _, c := range t.page.Comments {
+			writeComment(&b, commentAuthor(t.page.Users, c), c)
+		}
+	}
+
+	switch {
+	case t.replying:
+		b.WriteString("\n" + t.input.View() + "\n")
+	case t.sending:
+		b.WriteString("\nPosting...\n")
+	}
+	return b.String()
+}
+
+func (t *commentThread) help() string {
+	if t.replying {
+		return "enter post • esc cancel"
+	}
+	hints := "c reply"
+	if t.page != nil && t.page.Pagination.Pages > 1 {
+		hints = "[/] comment pages • " + hints
+	}
+	return hints
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// The subcommands stay on the flag package rather than a framework like
+// cobra: its pflag parsing would break the single-dash flags (-session)
+// that scripts and the daemon's service files already pass.
+
+// commandSpec is what the shell completion knows about a subcommand
+type commandSpec struct {
+	subcommands []string
+	flags       []string
+	games       bool // its arguments are game abbreviations
+}
+
+var commandSpecs = map[string]commandSpec{
+	"refresh-cache": {games: true},
+	"livesplit":     {flags: []string{"-addr", "-obs"}},
+	"timer":         {flags: []string{"-name"}},
+	"check-video":   {flags: []string{"-time"}},
+	"retime":        {flags: []string{"-fps"}},
+	"submit":        {flags: []string{"-video"}},
+	"export":        {flags: []string{"-format", "-from", "-to", "-o"}},
+	"bench":         {flags: []string{"-n", "-game"}},
+	"update":        {flags: []string{"-force"}},
+	"pick":          {subcommands: []string{"notification", "game", "run"}, flags: []string{"-id", "-video"}},
+	"daemon":        {subcommands: []string{"run", "healthcheck", "install", "uninstall"}, flags: []string{"-once"}},
+	"config":        {subcommands: []string{"init", "edit", "validate", "path"}, flags: []string{"-force"}},
+	"completion":    {subcommands: []string{"bash", "zsh", "fish", "powershell"}},
+}
+
+// Flags of the app itself, and the ones that take a value
+var (
+	globalFlags = []string{"-session", "-api-base", "-per-page", "-pages", "-dry-run", "-popup", "-print-on-exit"}
+	valueFlags  = map[string]bool{
+		"-session": true, "-api-base": true, "-per-page": true, "-pages": true,
+		"-addr": true, "-name": true, "-time": true, "-format": true, "-from": true,
+		"-to": true, "-o": true, "-n": true, "-game": true, "-print-on-exit": true,
+		"-fps": true,
+	}
+)
+
+// completionGames is every game abbreviation worth suggesting: the ones in
+// the metadata cache and the followed ones
+func completionGames() []string {
+	seen := map[string]bool{}
+	var games []string
+	add := func(g string) {
+		if !seen[g] {
+			seen[g] = true
+			games = append(games, g)
+		}
+	}
+	if cache, err := openCache("games"); err == nil {
+		keys, _ := cache.keys()
+		for _, k := range keys {
+			add(k)
+		}
+	}
+	if cfg, err := loadConfig(); err == nil {
+		for _, g := range cfg.FollowedGames {
+			add(g)
+		}
+	}
+	return games
+}
+
+// completeWords suggests what can follow words, the last of which is the
+// one being typed (empty for a new one)
+func completeWords(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current, before := words[len(words)-1], words[:len(words)-1]
+
+	// Find the subcommand and how many arguments it has so far, skipping
+	// flags and their values
+	command, args := "", 0
+	for i := 0; i < len(before); i++ {
+		w := before[i]
+		if strings.HasPrefix(w, "-") {
+			if valueFlags[w] && !strings.Contains(w, "=") {
+				i++
+			}
+			continue
+		}
+		if command == "" {
+			command = w
+		} else {
+			args++
+		}
+	}
+
+	var candidates []string
+	prev := ""
+	if len(before) > 0 {
+		prev = before[len(before)-1]
+	}
+	spec := commandSpecs[command]
+	switch {
+	case prev == "-game":
+		candidates = completionGames()
+	case prev == "-format":
+		candidates = []string{"json", "csv"}
+	case prev == "-print-on-exit":
+		candidates = []string{"url", "json"}
+	case valueFlags[prev]:
+		// Something only the user knows
+	case strings.HasPrefix(current, "-"):
+		candidates = spec.flags
+		if command == "" {
+			candidates = globalFlags
+		}
+	case command == "":
+		for name := range commandSpecs {
+			candidates = append(candidates, name)
+		}
+	case len(spec.subcommands) > 0 && args == 0:
+		candidates = spec.subcommands
+	case spec.games:
+		candidates = completionGames()
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, current) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// runComplete answers the completion scripts: it prints the candidates for
+// the words after the program name, one per line
+func runComplete(words []string) {
+	// PowerShell can't pass an empty argument to a native command, so its
+	// script stands in a quoted empty string
+	if n := len(words); n > 0 && words[n-1] == `""` {
+		words[n-1] = ""
+	}
+	candidates := completeWords(words)
+	sort.Strings(candidates)
+	for _, c := range candidates {
+		fmt.Println(c)
+	}
+}
+
+// Each script hands the words typed so far to "speedrunner __complete"
+var completionScripts = map[string]string{
+	"bash": `_speedrunner() {
+    local IFS=$'\n'
+    COMPREPLY=($(speedrunner __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _speedrunner speedrunner
+`,
+	"zsh": `#compdef speedrunner
+_speedrunner() {
+    local -a completions
+    completions=("${(@f)$(speedrunner __complete "${(@)words[2,$CURRENT]}" 2>/dev/null)}")
+    compadd -a completions
+}
+compdef _speedrunner speedrunner
+`,
+	"fish": `complete -c speedrunner -f -a '(speedrunner __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+	"powershell": `Register-ArgumentCompleter -Native -CommandName speedrunner -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') { $words += '""' }
+    & speedrunner __complete @words 2>$null | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
+
+// runCompletion prints the completion script for a shell
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: speedrunner completion bash|zsh|fish|powershell")
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown shell %q, use bash, zsh, fish or powershell", args[0])
+	}
+	_, err := os.Stdout.WriteString(script)
+	return err
+}
@@ -0,0 +1,478 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// The config file. It can hold session cookies, so a new one is only
+// readable by its owner.
+const configFile = "config.json"
+
+// Config is read from config.json in the user config directory. Command
+// line flags take precedence over anything set here.
+type Config struct {
+	// PHPSESSID cookie, used when -session isn't given. The daemon
+	// service reads it from here.
+	Session string `json:"session,omitempty"`
+
+	// How often the daemon checks for notifications, e.g. "5m"
+	PollInterval string `json:"poll_interval,omitempty"`
+
+	// Where the daemon serves its HTTP API, like "localhost:7878"; off
+	// when empty
+	APIAddr string `json:"api_addr,omitempty"`
+
+	// Base URL of the v2 API, for stub servers or caching mirrors
+	APIBase string `json:"api_base,omitempty"`
+
+	// User-Agent sent to the site instead of the app's own, and headers
+	// added to every request, for networks with a picky firewall
+	UserAgent string            `json:"user_agent,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+
+	// Log every request to the site with its status and time
+	APILog bool `json:"api_log,omitempty"`
+
+	// Extra CA certificates and the TLS version floor for every request
+	TLS TLSConfig `json:"tls"`
+
+	// Send crash reports somewhere; off unless set
+	CrashReports CrashReportConfig `json:"crash_reports"`
+
+	// Notifications fetched per request, and how many pages to load at
+	// startup. Zero keeps the site's page size and a single page.
+	NotificationsPerPage int `json:"notifications_per_page,omitempty"`
+	NotificationPages    int `json:"notification_pages,omitempty"`
+
+	// Mark notifications read when they're opened, like the site does.
+	// M toggles it for the session.
+	AutoMarkRead bool `json:"auto_mark_read,omitempty"`
+
+	// Go template for each notification in the list, with the fields of
+	// notificationFields. Empty keeps the built in layout.
+	NotificationTemplate string `json:"notification_template,omitempty"`
+
+	// Skip the y/n question before rejecting, withdrawing, deleting and
+	// bulk actions
+	SkipConfirmations bool `json:"skip_confirmations,omitempty"`
+
+	// Don't look for new releases at startup
+	SkipUpdateCheck bool `json:"skip_update_check,omitempty"`
+
+	// Game abbreviations as used in site URLs, e.g. "sm64". Also used as
+	// racetime.gg category slugs, which match for most games.
+	FollowedGames []string `json:"followed_games,omitempty"`
+
+	// Runners whose submissions, verified runs and records show on the
+	// Runners tab and go to the sinks, by user name
+	FollowedRunners []string `json:"followed_runners,omitempty"`
+
+	// How many days back the Records tab marks a record as new. Defaults
+	// to 7.
+	RecordDays int `json:"record_days,omitempty"`
+
+	// Other accounts whose notifications are merged into the inbox, each
+	// item badged with its account
+	Accounts []AccountConfig `json:"accounts,omitempty"`
+
+	// Games whose verification queue to show. When empty, every game the
+	// signed in user moderates is used.
+	ModeratedGames []string `json:"moderated_games,omitempty"`
+
+	// How each game's queue opens until its filter is changed, by game
+	// abbreviation, e.g. {"sm64": {"category": "120 Star", "min_age_days": 3}}
+	QueueViews map[string]QueueView `json:"queue_views,omitempty"`
+
+	// App credentials from dev.twitch.tv, enables live markers on boards
+	Twitch TwitchConfig `json:"twitch"`
+
+	// Which variables show as columns on each game's boards, by game
+	// abbreviation, e.g. {"sm64": ["Version"]}. Without an entry every
+	// variable that doesn't split the board gets a column.
+	BoardColumns map[string][]string `json:"board_columns,omitempty"`
+
+	// What the timer's PB, best segments and attempts are kept under,
+	// set with n on the timer. Defaults to "default".
+	TimerName string `json:"timer_name,omitempty"`
+
+	// Times to beat per category, e.g. {"game": "sm64", "category":
+	// "120 Star", "time": "1:40:00", "timer": "sm64-120"}; "wr" for a time
+	// follows the world record
+	Goals []Goal `json:"goals,omitempty"`
+
+	// Keys that work the timer while another window has focus, e.g.
+	// {"split": "kp1"}
+	TimerHotkeys TimerHotkeys `json:"timer_hotkeys"`
+
+	// OBS's websocket server, for timing a run by its recording, e.g.
+	// {"password": "...", "timer_source": "Timer"}
+	OBS OBSConfig `json:"obs"`
+
+	// How run times are shown on boards and in the queue
+	TimeFormat TimeFormat `json:"time_format"`
+
+	// How runners' countries are shown: "emoji" flags (the default),
+	// two letter "code"s or "off"
+	CountryFlags string `json:"country_flags,omitempty"`
+
+	// Tint the app with a game's colors from the site while browsing its
+	// boards
+	GameThemes bool `json:"game_themes,omitempty"`
+
+	// Number the notifications, for jumping to one by typing its number
+	IndexColumn bool `json:"index_column,omitempty"`
+
+	// Command used by v to play run videos; the URL is appended.
+	// Defaults to mpv.
+	VideoPlayer []string `json:"video_player,omitempty"`
+
+	// Where queue claims are kept. Point it at a folder shared with the
+	// other moderators, like a synced drive, so everyone sees who is on
+	// which run. Defaults to claims.json next to the config.
+	ClaimsFile string `json:"claims_file,omitempty"`
+
+	// Reasons offered when rejecting a run from the queue. Defaults to a
+	// few common ones.
+	RejectionTemplates []RejectionTemplate `json:"rejection_templates,omitempty"`
+
+	// Keys bound to a list of actions per screen, e.g. in the queue
+	// "V": ["verify", "next"]. Actions are named ones or key presses.
+	Bindings map[string]map[string][]string `json:"bindings,omitempty"`
+
+	// Words highlighted wherever they appear in notifications and run
+	// comments, such as your name or your games
+	Keywords []string `json:"keywords,omitempty"`
+
+	// Named notification filters, saved from the app with S
+	Views []SavedView `json:"views,omitempty"`
+
+	// What to do with matching notifications: mute, mark read,
+	// highlight, alert or forward to a named sink
+	Rules []Rule `json:"rules,omitempty"`
+
+	// Where alerts such as lost PB ranks are delivered
+	Sinks []SinkConfig `json:"sinks,omitempty"`
+
+	// Which named sinks get alerts about which games. Without routes
+	// every sink gets every alert.
+	Routes []Route `json:"routes,omitempty"`
+}
+
+// configDir holds the config file and anything else the user would want to
+// keep, like timer records. Unlike the cache dir it is never wiped.
+func configDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config dir: %w", err)
+	}
+	return filepath.Join(base, appName), nil
+}
+
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFile), nil
+}
+
+// loadConfig reads the config file. A missing file is not an error and
+// yields the zero Config.
+func loadConfig() (Config, error) {
+	var cfg Config
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("reading config: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// saveConfigField sets one top level key in the config file. Only that
+// key's value is rewritten: the rest of the file keeps the order, layout
+// and mode the user gave it.
+func saveConfigField(key string, v any) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	doc, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return saveState(configFile, map[string]any{key: v})
+	}
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	value, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", key, err)
+	}
+	doc, err = setJSONField(doc, key, value)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := replaceFile(path, doc, 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", configFile, err)
+	}
+	return nil
+}
+
+// setJSONField replaces the value of a top level key in a JSON object, or
+// adds the key last, leaving every other byte as it was
+func setJSONField(doc []byte, key string, value []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("the config isn't a JSON object")
+	}
+	open := int(dec.InputOffset())
+	last := -1 // end of the last value
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		// RawMessage gets the value's bytes as they are in the file
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		last = int(dec.InputOffset())
+		if tok == key {
+			return slices.Concat(doc[:last-len(raw)], value, doc[last:]), nil
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	name, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	field := slices.Concat([]byte("\n  "), name, []byte(": "), value)
+	if last < 0 {
+		closing := int(dec.InputOffset()) - 1
+		return slices.Concat(doc[:open], field, []byte("\n"), doc[closing:]), nil
+	}
+	return slices.Concat(doc[:last], []byte(","), field, doc[last:]), nil
+}
+
+// validate checks everything in the config that can be checked without
+// the site, stopping at the first problem
+func (c Config) validate() error {
+	if err := validateAPIBase(c.APIBase); err != nil {
+		return fmt.Errorf("api_base: %w", err)
+	}
+	if err := validateHeaders(c.UserAgent, c.Headers); err != nil {
+		return err
+	}
+	if _, err := c.TLS.build(); err != nil {
+		return err
+	}
+	if err := c.CrashReports.validate(); err != nil {
+		return err
+	}
+	if _, err := parseNotificationTemplate(c.NotificationTemplate); err != nil {
+		return err
+	}
+	if _, err := parseBindings(c.Bindings, len(c.RejectionTemplates)); err != nil {
+		return err
+	}
+	if err := validateAccounts(c.Accounts); err != nil {
+		return err
+	}
+	if err := c.validatePaging(); err != nil {
+		return err
+	}
+	if !c.TimerHotkeys.Off {
+		if _, err := c.TimerHotkeys.keys(); err != nil {
+			return err
+		}
+	}
+	if err := validateGoals(c.Goals); err != nil {
+		return err
+	}
+	if err := validateQueueViews(c.QueueViews); err != nil {
+		return err
+	}
+	if err := c.TimeFormat.validate(); err != nil {
+		return err
+	}
+	if err := validateFlagMode(c.CountryFlags); err != nil {
+		return err
+	}
+	if _, err := c.pollInterval(); err != nil {
+		return err
+	}
+	if err := validateAPIAddr(c.APIAddr); err != nil {
+		return err
+	}
+	if _, err := buildSinks(c.Sinks); err != nil {
+		return fmt.Errorf("sinks: %w", err)
+	}
+	if _, err := buildRouter(c.Routes, c.Sinks); err != nil {
+		return fmt.Errorf("routes: %w", err)
+	}
+	if _, err := compileRules(c.Rules, c.Sinks); err != nil {
+		return fmt.Errorf("rules: %w", err)
+	}
+	return nil
+}
+
+// maxNotificationsPerPage is the most the site returns in one request
+const maxNotificationsPerPage = 100
+
+// validatePaging checks the notification page size and page count, and
+// how far back records count as new
+func (c Config) validatePaging() error {
+	if c.NotificationsPerPage < 0 || c.NotificationsPerPage > maxNotificationsPerPage {
+		return fmt.Errorf("notifications_per_page must be between 1 and %d, got %d", maxNotificationsPerPage, c.NotificationsPerPage)
+	}
+	if c.NotificationPages < 0 {
+		return fmt.Errorf("notification_pages can't be negative, got %d", c.NotificationPages)
+	}
+	if c.RecordDays < 0 {
+		return fmt.Errorf("record_days can't be negative, got %d", c.RecordDays)
+	}
+	return nil
+}
+
+// validateAPIBase checks that an api_base override is an absolute http(s) URL
+func validateAPIBase(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must start with http:// or https://", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	return nil
+}
+
+// validateHeaders checks user_agent and headers. The session cookie is
+// the app's own to send.
+func validateHeaders(userAgent string, headers map[string]string) error {
+	if strings.ContainsAny(userAgent, "\r\n") {
+		return errors.New("user_agent can't span lines")
+	}
+	for name, value := range headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("headers: %q isn't a header name", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("headers: %s can't span lines", name)
+		}
+		if strings.EqualFold(name, "Cookie") {
+			return errors.New("headers: set the session with \"session\" or -session, not a Cookie header")
+		}
+	}
+	return nil
+}
+
+// loadState reads a JSON file kept next to the config. A missing file leaves
+// v untouched and is not an error.
+func loadState(name string, v any) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+	return nil
+}
+
+// saveState writes v as JSON next to the config, replacing the old file
+// atomically so a crash can't truncate it.
+func saveState(name string, v any) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+
+	perm := fs.FileMode(0o644)
+	if name == configFile {
+		perm = 0o600
+	}
+	if err := replaceFile(filepath.Join(dir, name), raw, perm); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// replaceFile writes raw over path through a temp file. A new file gets
+// perm and an existing one keeps its mode, so a config the user locked
+// down stays that way.
+func replaceFile(path string, raw []byte, perm fs.FileMode) error {
+	keep := false
+	if info, err := os.Stat(path); err == nil {
+		perm, keep = info.Mode().Perm(), true
+	}
+
+	tmp := path + ".tmp"
+	// A leftover temp file would keep its own mode
+	os.Remove(tmp)
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(raw)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && keep {
+		// The umask may have narrowed it
+		err = os.Chmod(tmp, perm)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
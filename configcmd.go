@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// runConfig handles "config init|edit|validate|path"
+func runConfig(args []string, apiBase, sessionID string) error {
+	if len(args) == 0 {
+		return errors.New("usage: speedrunner config init|edit|validate|path")
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "path":
+		fmt.Println(path)
+		return nil
+	case "init":
+		fs := flag.NewFlagSet("config init", flag.ExitOnError)
+		force := fs.Bool("force", false, "run the setup even if there's a config, replacing it")
+		fs.Parse(args[1:])
+		if configExists() && !*force {
+			return fmt.Errorf("%s already exists; use config edit, or -force to start over", path)
+		}
+		saved, err := runOnboarding(apiBase, sessionID)
+		if err != nil {
+			return err
+		}
+		if saved {
+			fmt.Printf("Wrote %s\n", path)
+		}
+		return nil
+	case "edit":
+		if err := editConfig(path); err != nil {
+			return err
+		}
+		// Say straight away if the edit broke something
+		if err := checkConfig(path); err != nil {
+			return err
+		}
+		fmt.Println("Config OK")
+		return nil
+	case "validate":
+		if err := checkConfig(path); err != nil {
+			return err
+		}
+		fmt.Printf("%s is OK\n", path)
+		return nil
+	}
+	return fmt.Errorf("unknown config command %q", args[0])
+}
+
+// checkConfig reads the config more strictly than loadConfig: unknown
+// keys, usually typos, are errors rather than ignored
+func checkConfig(path string) error {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s doesn't exist yet; run config init", path)
+	}
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+// editConfig opens the config in $VISUAL or $EDITOR, creating an empty
+// one first if there's none
+func editConfig(path string) error {
+	if !configExists() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("creating config dir: %w", err)
+		}
+		if err := os.WriteFile(path, []byte("{\n}\n"), 0o600); err != nil {
+			return fmt.Errorf("writing config: %w", err)
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	// The variable may carry flags, like "code --wait"
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", editor+` "`+path+`"`)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", editor, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmDialog asks y/n before an action that can't easily be taken
+// back. Screens ask for one with confirmCmd and get yes back as a message
+// when it's confirmed.
+type confirmDialog struct {
+	question string
+	yes      tea.Msg
+}
+
+type confirmMsg struct {
+	question string
+	yes      tea.Msg
+}
+
+// confirmCmd asks the question, sending yes once it's answered with y.
+// With skip_confirmations set, yes is sent straight away.
+func confirmCmd(yes tea.Msg, format string, args ...any) tea.Cmd {
+	return func() tea.Msg {
+		return confirmMsg{question: fmt.Sprintf(format, args...), yes: yes}
+	}
+}
+
+// update returns the command sending the confirmed message, or a note
+// that it was cancelled if the answer was anything but y
+func (d *confirmDialog) update(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() != "y" {
+		return statusCmd("Cancelled")
+	}
+	yes := d.yes
+	return func() tea.Msg { return yes }
+}
+
+func (d *confirmDialog) view() string {
+	return alertBannerStyle.Render(d.question + " y/n")
+}
+
+func (d *confirmDialog) help() string {
+	return "y confirm • any other key cancels"
+}
+
+// openConfirm shows the dialog for a confirmMsg, or skips it
+func (m model) openConfirm(msg confirmMsg) (model, tea.Cmd) {
+	if m.skipConfirm {
+		yes := msg.yes
+		return m, func() tea.Msg { return yes }
+	}
+	m.confirm = &confirmDialog{question: msg.question, yes: msg.yes}
+	return m, nil
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Messages kept for a crash report
+const crashRecent = 30
+
+// crashRecorder keeps what a crash report needs: the last messages and a
+// summary of the state. Panics can happen in any command's goroutine, so
+// it's shared.
+type crashRecorder struct {
+	mu     sync.Mutex
+	recent []string
+	state  string
+	report string // path, once one has been written
+	upload string // where to send reports, when the user opted in
+}
+
+var crashLog = &crashRecorder{}
+
+func (c *crashRecorder) message(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recent = append(c.recent, time.Now().Format("15:04:05.000")+" "+s)
+	if len(c.recent) > crashRecent {
+		c.recent = c.recent[1:]
+	}
+}
+
+func (c *crashRecorder) setState(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = s
+}
+
+// reportPath is where the crash report went, empty when nothing crashed
+func (c *crashRecorder) reportPath() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.report
+}
+
+// write saves a crash report in the log dir. Only the first crash is
+// written; whatever follows is usually fallout from it.
+func (c *crashRecorder) write(r any, stack []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.report != "" {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "speedrunner %s crashed at %s\n", version, time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "%s %s/%s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic: %v\n\n%s\n", r, stack)
+	if c.state != "" {
+		fmt.Fprintf(&b, "State:\n%s\n", c.state)
+	}
+	b.WriteString("Last messages, oldest first:\n")
+	for _, m := range c.recent {
+		b.WriteString("  " + m + "\n")
+	}
+
+	dir, err := logDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	path := filepath.Join(dir, "crash-"+time.Now().Format("20060102-150405")+".txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Writing crash report: %v\n%s", err, b.String())
+		return
+	}
+	c.report = path
+}
+
+// CrashReportConfig opts in to sending crash reports. Reports hold the
+// stack, the app's version and platform, the last keys and messages by
+// type and a count of what was on screen, never the session or any text
+// from the site.
+type CrashReportConfig struct {
+	Send bool   `json:"send,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+func (c CrashReportConfig) validate() error {
+	if !c.Send {
+		return nil
+	}
+	if c.URL == "" {
+		return errors.New("crash_reports: send is on but there's no url to send to")
+	}
+	// Reports carry stacks and recent keys, so never in the clear
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("crash_reports: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("crash_reports: %q must be an https:// URL", c.URL)
+	}
+	return nil
+}
+
+// sendReports sets where reports go once written, if the user opted in
+func (c *crashRecorder) sendReports(cfg CrashReportConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cfg.Send {
+		c.upload = cfg.URL
+	}
+}
+
+// send uploads the crash report, if there is one and the user opted in.
+// It reports where it went, empty when it wasn't sent.
+func (c *crashRecorder) send() (string, error) {
+	c.mu.Lock()
+	path, url := c.report, c.upload
+	c.mu.Unlock()
+	if path == "" || url == "" {
+		return "", nil
+	}
+
+	report, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading crash report: %w", err)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(report))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("User-Agent", defaultUserAgent())
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sending crash report: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("sending crash report: %s answered %s", url, resp.Status)
+	}
+	return url, nil
+}
+
+// reportSent tells the user the report went out, or why it didn't
+func reportSent() {
+	if to, err := crashLog.send(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	} else if to != "" {
+		fmt.Fprintf(os.Stderr, "The report was sent to %s, thanks.\n", to)
+	}
+}
+
+// recoverCrash writes a crash report for a panic and panics again, so
+// Bubble Tea still restores the terminal. It must be deferred directly.
+func recoverCrash() {
+	if r := recover(); r != nil {
+		crashLog.write(r, debug.Stack())
+		panic(r)
+	}
+}
+
+// crashGuard wraps the app's model so a panic in an update, a view or a
+// command leaves a crash report behind
+type crashGuard struct {
+	m model
+}
+
+func (g crashGuard) Init() tea.Cmd {
+	return guardCmd(g.m.Init())
+}
+
+func (g crashGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// The once a second redraw would push everything else out
+	if _, tick := msg.(refreshTickMsg); !tick {
+		crashLog.message(g.m.describeMsg(msg))
+	}
+	defer recoverCrash()
+	next, cmd := g.m.Update(msg)
+	g.m = next.(model)
+	crashLog.setState(g.m.crashState())
+	return g, guardCmd(cmd)
+}
+
+func (g crashGuard) View() string {
+	defer recoverCrash()
+	return g.m.View()
+}
+
+// guardCmd wraps a command, and those in a batch, in recoverCrash
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		defer recoverCrash()
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i := range batch {
+				batch[i] = guardCmd(batch[i])
+			}
+			return batch
+		}
+		return msg
+	}
+}
+
+// describeMsg names a message for the report. Keys typed into a text box
+// are left out, since they could be anything.
+func (m model) describeMsg(msg tea.Msg) string {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyRunes && (m.typing() || m.report != nil) {
+			return "key (text)"
+		}
+		return fmt.Sprintf("key %q", msg.String())
+	case tea.WindowSizeMsg:
+		return fmt.Sprintf("resize %dx%d", msg.Width, msg.Height)
+	}
+	return fmt.Sprintf("%T", msg)
+}
+
+// crashState sums up the state without anything personal: no session, no
+// notification text
+func (m model) crashState() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  screen: %s\n", screenNames[m.screen])
+	fmt.Fprintf(&b, "  size: %dx%d\n", m.width, m.height)
+	fmt.Fprintf(&b, "  notifications: %d, selected %d\n", len(m.notifications), m.selected)
+	fmt.Fprintf(&b, "  accounts: %d\n", len(m.accounts))
+	fmt.Fprintf(&b, "  detail open: %t, confirm open: %t\n", m.detail != nil, m.confirm != nil)
+	fmt.Fprintf(&b, "  retries waiting: %d\n", len(m.retries.actions))
+	if m.client != nil {
+		on, skipped := m.client.DryRun()
+		fmt.Fprintf(&b, "  dry run: %t (%d skipped)\n", on, skipped)
+	}
+	return b.String()
+}
+
+// exitOnCrash reports a panic outside the app's screens, like in a
+// subcommand. Deferred first thing in main.
+func exitOnCrash() {
+	if r := recover(); r != nil {
+		crashLog.write(r, debug.Stack())
+		fmt.Fprintf(os.Stderr, "speedrunner crashed: %v\n", r)
+		if path := crashLog.reportPath(); path != "" {
+			fmt.Fprintf(os.Stderr, "A crash report was written to %s\n", path)
+			reportSent()
+		}
+		os.Exit(2)
+	}
+}
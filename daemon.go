@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"text/template"
+	"time"
+)
+
+const defaultPollInterval = 2 * time.Minute
+
+// runDaemon handles "daemon run|install|uninstall"
+func runDaemon(args []string, cfg Config, sessionID string) error {
+	if len(args) == 0 {
+		return errors.New("usage: speedrunner daemon run|healthcheck|install|uninstall")
+	}
+	switch args[0] {
+	case "run":
+		return runWatch(args[1:], cfg, sessionID)
+	case "healthcheck":
+		return runHealthcheck()
+	case "install":
+		if cfg.Session == "" {
+			return errors.New("set \"session\" in the config first, so the service doesn't need the cookie on its command line")
+		}
+		return installService()
+	case "uninstall":
+		return uninstallService()
+	}
+	return fmt.Errorf("unknown daemon command %q", args[0])
+}
+
+// pollInterval is how often the daemon checks for notifications
+func (c Config) pollInterval() (time.Duration, error) {
+	if c.PollInterval == "" {
+		return defaultPollInterval, nil
+	}
+	d, err := time.ParseDuration(c.PollInterval)
+	if err != nil {
+		return 0, fmt.Errorf("poll_interval: %w", err)
+	}
+	if d < 30*time.Second {
+		return 0, fmt.Errorf("poll_interval must be at least 30s, got %s", d)
+	}
+	return d, nil
+}
+
+// runWatch polls notifications until it's stopped, archiving them,
+// applying the rules and alerting the sinks about new unread ones
+func runWatch(args []string, cfg Config, sessionID string) error {
+	fs := flag.NewFlagSet("daemon run", flag.ExitOnError)
+	once := fs.Bool("once", false, "poll once and exit")
+	fs.Parse(args)
+
+	if sessionID == "" {
+		return errNoSession
+	}
+	interval, err := cfg.pollInterval()
+	if err != nil {
+		return err
+	}
+	router, err := buildRouter(cfg.Routes, cfg.Sinks)
+	if err != nil {
+		return fmt.Errorf("sink config: %w", err)
+	}
+	rules, err := compileRules(cfg.Rules, cfg.Sinks)
+	if err != nil {
+		return fmt.Errorf("rules config: %w", err)
+	}
+	client := newConfiguredClient(cfg, sessionID)
+
+	if _, err := setupDaemonLogging(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	state := &daemonState{status: daemonStatus{PID: os.Getpid(), Started: time.Now(), Interval: interval}}
+	pollOnce := func() {
+		notifications, err := poll(client, cfg, rules, router)
+		if err != nil {
+			log.Printf("daemon: %v", err)
+		}
+		state.record(notifications, err)
+	}
+
+	if *once {
+		pollOnce()
+		return nil
+	}
+	listener, err := serveStatus(state)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	if cfg.APIAddr != "" {
+		srv, err := serveAPI(cfg.APIAddr, state, cfg)
+		if err != nil {
+			return err
+		}
+		defer srv.Close()
+		log.Printf("daemon: api on http://%s", cfg.APIAddr)
+	}
+	log.Printf("daemon: polling every %s", interval)
+	pollOnce()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pollOnce()
+		case <-stop:
+			log.Printf("daemon: stopping")
+			return nil
+		}
+	}
+}
+
+// poll fetches notifications once. Unread ones that weren't in the history
+// yet are alerted through the routes, unless a rule muted them or already
+// alerted. With no history at all nothing is new, so a first run doesn't
+// alert everything. It returns the notifications the rules kept.
+func poll(client *Client, cfg Config, rules *ruleSet, router *alertRouter) ([]Notification, error) {
+	result, err := client.GetNotificationPages(cfg.NotificationsPerPage, cfg.NotificationPages)
+	if err != nil {
+		return nil, err
+	}
+	history, err := loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(history))
+	for _, e := range history {
+		known[e.Notification.ID] = true
+	}
+	if err := archiveNotifications(result.Notifications); err != nil {
+		log.Printf("history: %v", err)
+	}
+
+	kept, _, _, work := applyRules(rules, result.Notifications)
+	alerted := make(map[string]bool, len(work.deliveries))
+	for _, d := range work.deliveries {
+		alerted[d.id] = true
+	}
+	ruleWorkCmd(client, work)()
+
+	for _, n := range kept {
+		if len(history) == 0 || n.Read || known[n.ID] || alerted[n.ID] {
+			continue
+		}
+		router.send(Alert{Title: "speedrun.com", Body: n.Title, URL: "https://www.speedrun.com" + n.Path, Game: notificationGame(n)})
+	}
+	return kept, nil
+}
+
+const (
+	serviceName = "speedrunner"
+	launchLabel = "com.speedrunner-tui.daemon"
+)
+
+var systemdUnit = template.Must(template.New("unit").Parse(`[Unit]
+Description=speedrunner notification watcher
+After=network-online.target
+
+[Service]
+ExecStart="{{.}}" daemon run
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=default.target
+`))
+
+var launchdPlist = template.Must(template.New("plist").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + launchLabel + `</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{.}}</string>
+		<string>daemon</string>
+		<string>run</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+</dict>
+</plist>
+`))
+
+// servicePath is where the unit or plist goes for the current user
+func servicePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating home dir: %w", err)
+	}
+	switch runtime.GOOS {
+	case "linux":
+		base := os.Getenv("XDG_CONFIG_HOME")
+		if base == "" {
+			base = filepath.Join(home, ".config")
+		}
+		return filepath.Join(base, "systemd", "user", serviceName+".service"), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", launchLabel+".plist"), nil
+	}
+	return "", fmt.Errorf("installing the daemon isn't supported on %s", runtime.GOOS)
+}
+
+// installService writes a user service running "daemon run" at login and
+// starts it
+func installService() error {
+	path, err := servicePath()
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating executable: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("locating executable: %w", err)
+	}
+
+	tmpl := systemdUnit
+	if runtime.GOOS == "darwin" {
+		tmpl = launchdPlist
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, exe); err != nil {
+		return fmt.Errorf("writing service: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating service dir: %w", err)
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing service: %w", err)
+	}
+
+	if runtime.GOOS == "darwin" {
+		err = runCommand("launchctl", "load", "-w", path)
+	} else {
+		err = runCommand("systemctl", "--user", "daemon-reload")
+		if err == nil {
+			err = runCommand("systemctl", "--user", "enable", "--now", serviceName+".service")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installed %s and started the daemon\n", path)
+	return nil
+}
+
+// uninstallService stops the daemon and removes its service file
+func uninstallService() error {
+	path, err := servicePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return errors.New("the daemon isn't installed")
+	}
+
+	if runtime.GOOS == "darwin" {
+		err = runCommand("launchctl", "unload", "-w", path)
+	} else {
+		err = runCommand("systemctl", "--user", "disable", "--now", serviceName+".service")
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing service: %w", err)
+	}
+	if runtime.GOOS == "linux" {
+		if err := runCommand("systemctl", "--user", "daemon-reload"); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Stopped the daemon and removed %s\n", path)
+	return nil
+}
+
+func runCommand(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
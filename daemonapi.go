@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// The daemon's HTTP API serves what it fetched on its last poll, so status
+// bars and other tools can read notifications without a session of their
+// own. It's read only and only listens on the loopback interface.
+
+// validateAPIAddr checks that api_addr is a host:port on this machine
+func validateAPIAddr(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("api_addr: %w", err)
+	}
+	if !loopbackHost(host) {
+		return fmt.Errorf("api_addr must be on localhost, got %q", addr)
+	}
+	return nil
+}
+
+func loopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// apiNotification is a notification as the API serves it
+type apiNotification struct {
+	Notification
+	URL string `json:"url"`
+}
+
+// apiWatches is what the daemon keeps an eye on for the user
+type apiWatches struct {
+	FollowedGames  []string `json:"followed_games"`
+	ModeratedGames []string `json:"moderated_games"`
+	Keywords       []string `json:"keywords"`
+	Pins           []Pin    `json:"pins"`
+}
+
+// serveAPI answers the API on addr until the server is closed
+func serveAPI(addr string, state *daemonState, cfg Config) (*http.Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("opening api: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /notifications", func(w http.ResponseWriter, r *http.Request) {
+		notifications, fetched, ok := state.notificationsSnapshot()
+		if !ok {
+			writeAPIError(w, http.StatusServiceUnavailable, errors.New("no successful poll yet"))
+			return
+		}
+		out := make([]apiNotification, len(notifications))
+		for i, n := range notifications {
+			out[i] = apiNotification{Notification: n, URL: "https://www.speedrun.com" + n.Path}
+		}
+		writeAPI(w, struct {
+			Fetched       time.Time         `json:"fetched"`
+			Notifications []apiNotification `json:"notifications"`
+		}{fetched, out})
+	})
+	mux.HandleFunc("GET /unread", func(w http.ResponseWriter, r *http.Request) {
+		notifications, fetched, ok := state.notificationsSnapshot()
+		if !ok {
+			writeAPIError(w, http.StatusServiceUnavailable, errors.New("no successful poll yet"))
+			return
+		}
+		unread := 0
+		for _, n := range notifications {
+			if !n.Read {
+				unread++
+			}
+		}
+		writeAPI(w, struct {
+			Fetched time.Time `json:"fetched"`
+			Unread  int       `json:"unread"`
+		}{fetched, unread})
+	})
+	mux.HandleFunc("GET /watches", func(w http.ResponseWriter, r *http.Request) {
+		// Pins are read fresh, since the app changes them while the
+		// daemon runs
+		pins, err := loadPins()
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeAPI(w, apiWatches{
+			FollowedGames:  nonNil(cfg.FollowedGames),
+			ModeratedGames: nonNil(cfg.ModeratedGames),
+			Keywords:       nonNil(cfg.Keywords),
+			Pins:           nonNil(pins),
+		})
+	})
+
+	srv := &http.Server{
+		Handler:           localOnly(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("daemon: api: %v", err)
+		}
+	}()
+	return srv, nil
+}
+
+// localOnly turns away requests naming another host, so a web page can't
+// reach the API through DNS rebinding
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if !loopbackHost(host) {
+			writeAPIError(w, http.StatusForbidden, errors.New("only local requests are answered"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeAPI(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("daemon: api: %v", err)
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+// nonNil makes an empty list encode as [] rather than null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
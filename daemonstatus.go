@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The daemon answers on this socket in the cache dir with its status as
+// JSON, then hangs up
+const daemonSocket = "daemon.sock"
+
+// daemonStatus is how the daemon is doing
+type daemonStatus struct {
+	PID         int           `json:"pid"`
+	Started     time.Time     `json:"started"`
+	Interval    time.Duration `json:"interval"`
+	Polls       int           `json:"polls"`
+	Errors      int           `json:"errors"`
+	LastPoll    time.Time     `json:"last_poll"`
+	LastSuccess time.Time     `json:"last_success"`
+	LastError   string        `json:"last_error,omitempty"` // of the last poll, if it failed
+}
+
+// healthy means a poll has gone through within the last three intervals
+func (s daemonStatus) healthy(now time.Time) bool {
+	return !s.LastSuccess.IsZero() && now.Sub(s.LastSuccess) < 3*s.Interval
+}
+
+// daemonState is the status the daemon keeps while it runs
+type daemonState struct {
+	mu     sync.Mutex
+	status daemonStatus
+
+	// What the last successful poll fetched, after the rules, for the API
+	notifications []Notification
+}
+
+func (d *daemonState) record(notifications []Notification, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	d.status.Polls++
+	d.status.LastPoll = now
+	if err != nil {
+		d.status.Errors++
+		d.status.LastError = err.Error()
+		return
+	}
+	d.status.LastSuccess = now
+	d.status.LastError = ""
+	d.notifications = notifications
+}
+
+func (d *daemonState) snapshot() daemonStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status
+}
+
+// notificationsSnapshot is what the last successful poll fetched and when;
+// ok is false until a poll has gone through
+func (d *daemonState) notificationsSnapshot() ([]Notification, time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.status.LastSuccess.IsZero() {
+		return nil, time.Time{}, false
+	}
+	return nonNil(d.notifications), d.status.LastSuccess, true
+}
+
+func daemonSocketPath() (string, error) {
+	dir, err := logDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, daemonSocket), nil
+}
+
+// serveStatus answers status queries until the listener is closed. A
+// socket left behind by a daemon that crashed is replaced; one that still
+// answers means another daemon is running.
+func serveStatus(state *daemonState) (net.Listener, error) {
+	path, err := daemonSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := queryDaemon(); err == nil {
+		return nil, errors.New("another daemon is already running")
+	}
+	os.Remove(path)
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening status socket: %w", err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			if err := json.NewEncoder(conn).Encode(state.snapshot()); err != nil {
+				log.Printf("daemon: status: %v", err)
+			}
+			conn.Close()
+		}
+	}()
+	return l, nil
+}
+
+// queryDaemon asks a running daemon for its status
+func queryDaemon() (daemonStatus, error) {
+	var status daemonStatus
+	path, err := daemonSocketPath()
+	if err != nil {
+		return status, err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return status, errors.New("the daemon isn't running")
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+	if err := json.NewDecoder(conn).Decode(&status); err != nil {
+		return status, fmt.Errorf("reading daemon status: %w", err)
+	}
+	return status, nil
+}
+
+// runHealthcheck prints the daemon's status, failing when it isn't
+// running or hasn't polled successfully for a while, for scripts and
+// monitoring
+func runHealthcheck() error {
+	status, err := queryDaemon()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	fmt.Printf("pid %d, up %s, polling every %s\n", status.PID, formatAge(now.Sub(status.Started)), status.Interval)
+	fmt.Printf("%d polls, %d errors\n", status.Polls, status.Errors)
+	if status.LastSuccess.IsZero() {
+		fmt.Println("last successful poll: never")
+	} else {
+		fmt.Printf("last successful poll: %s ago (%s)\n", formatAge(now.Sub(status.LastSuccess)), status.LastSuccess.Local().Format(time.DateTime))
+	}
+	if status.LastError != "" {
+		fmt.Printf("last error: %s\n", status.LastError)
+	}
+	if !status.healthy(now) {
+		return errors.New("unhealthy: no successful poll in the last three intervals")
+	}
+	return nil
+}
+
+type daemonStatusMsg struct {
+	status daemonStatus
+	err    error
+}
+
+const daemonCheckInterval = 30 * time.Second
+
+// daemonStatusCmd asks the daemon how it's doing, after a delay when it's
+// a recheck
+func daemonStatusCmd(after time.Duration) tea.Cmd {
+	query := func(time.Time) tea.Msg {
+		status, err := queryDaemon()
+		return daemonStatusMsg{status: status, err: err}
+	}
+	if after == 0 {
+		return func() tea.Msg { return query(time.Now()) }
+	}
+	return tea.Tick(after, query)
+}
+
+// renderDaemon is the status bar's note about the daemon, empty when none
+// is running
+func renderDaemon(status *daemonStatus) string {
+	if status == nil {
+		return ""
+	}
+	now := time.Now()
+	if status.LastSuccess.IsZero() {
+		return "daemon running, not synced yet"
+	}
+	synced := "just now"
+	if age := now.Sub(status.LastSuccess); age >= time.Minute {
+		synced = formatAge(age) + " ago"
+	}
+	text := "daemon running, last sync " + synced
+	if !status.healthy(now) {
+		return failStyle.Render(text)
+	}
+	return text
+}
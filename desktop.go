@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// desktopSink shows a desktop notification with whatever the platform
+// has: notify-send on Linux, terminal-notifier or osascript on macOS and
+// a PowerShell toast on Windows. Clicking it opens the alert's URL where
+// the platform allows.
+type desktopSink struct{}
+
+func (desktopSink) Send(a Alert) error {
+	switch runtime.GOOS {
+	case "linux":
+		return notifySend(a)
+	case "darwin":
+		return macNotification(a)
+	case "windows":
+		return windowsToast(a)
+	}
+	return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+}
+
+// notifySend offers an Open action when there's a URL. notify-send waits
+// for the click, so that runs in the background, falling back to a plain
+// notification on versions without actions.
+func notifySend(a Alert) error {
+	body := a.Body
+	if a.URL != "" {
+		body += "\n" + a.URL
+	}
+	plain := func() error {
+		return exec.Command("notify-send", "--app-name="+appName, a.Title, body).Run()
+	}
+	if a.URL == "" {
+		return plain()
+	}
+
+	go func() {
+		out, err := exec.Command("notify-send", "--app-name="+appName, "--action=open=Open", a.Title, body).Output()
+		if err != nil {
+			if err := plain(); err != nil {
+				log.Printf("sink desktop: %v", err)
+			}
+			return
+		}
+		if strings.TrimSpace(string(out)) == "open" {
+			openBrowser(a.URL)
+		}
+	}()
+	return nil
+}
+
+// macNotification uses terminal-notifier when it's installed, since
+// Notification Center only opens a URL on click for a real app bundle,
+// and AppleScript's display notification otherwise
+func macNotification(a Alert) error {
+	if _, err := exec.LookPath("terminal-notifier"); err == nil {
+		args := []string{"-title", appName, "-subtitle", a.Title, "-message", a.Body}
+		if a.URL != "" {
+			args = append(args, "-open", a.URL)
+		}
+		return exec.Command("terminal-notifier", args...).Run()
+	}
+
+	body := a.Body
+	if a.URL != "" {
+		body += "\n" + a.URL
+	}
+	// Text goes in as arguments so it never needs quoting for AppleScript
+	return exec.Command("osascript",
+		"-e", "on run argv",
+		"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+		"-e", "end run",
+		a.Title, body).Run()
+}
+
+// PowerShell's own app ID, which Windows lets any script show toasts as
+const powershellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] > $null
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml($env:SPEEDRUNNER_TOAST)
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:SPEEDRUNNER_APP_ID).Show($toast)
+`
+
+// windowsToast shows a toast through PowerShell. A protocol activation
+// makes clicking it open the URL in the browser.
+func windowsToast(a Alert) error {
+	var text bytes.Buffer
+	for _, s := range []string{a.Title, a.Body} {
+		text.WriteString("<text>")
+		if err := xml.EscapeText(&text, []byte(s)); err != nil {
+			return fmt.Errorf("building toast: %w", err)
+		}
+		text.WriteString("</text>")
+	}
+	launch := ""
+	if a.URL != "" {
+		var url bytes.Buffer
+		if err := xml.EscapeText(&url, []byte(a.URL)); err != nil {
+			return fmt.Errorf("building toast: %w", err)
+		}
+		launch = fmt.Sprintf(` activationType="protocol" launch="%s"`, url.String())
+	}
+	toast := fmt.Sprintf(`<toast%s><visual><binding template="ToastGeneric">%s</binding></visual></toast>`, launch, text.String())
+
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	cmd.Env = append(os.Environ(), "SPEEDRUNNER_TOAST="+toast, "SPEEDRUNNER_APP_ID="+powershellAppID)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("showing toast: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// How far back the Runners tab goes
+	followWindow = 14 * 24 * time.Hour
+
+	followCheckInterval = 15 * time.Minute
+
+	followSeenFile = "followed_runners.json"
+)
+
+type runnerEventKind int
+
+const (
+	eventSubmitted runnerEventKind = iota
+	eventVerified
+	eventRecord
+)
+
+var runnerEventNames = [...]string{
+	eventSubmitted: "submitted",
+	eventVerified:  "verified",
+	eventRecord:    "record",
+}
+
+// runnerEvent is something a followed runner did: a run submitted, one
+// verified or a world record set
+type runnerEvent struct {
+	Kind   runnerEventKind
+	Runner string
+	follow string // the name as followed, which may differ in case
+	RunID  string
+	Board  string // e.g. "Super Mario 64 - 120 Star"
+	Time   time.Duration
+	Millis bool
+	URL    string
+	At     time.Time
+}
+
+// key tells events apart for alerting each one once
+func (e runnerEvent) key() string {
+	return e.RunID + "/" + runnerEventNames[e.Kind]
+}
+
+func (e runnerEvent) Alert() Alert {
+	title := e.Runner + " submitted a run"
+	switch e.Kind {
+	case eventVerified:
+		title = e.Runner + " got a run verified"
+	case eventRecord:
+		title = e.Runner + " set a world record"
+	}
+	return Alert{
+		Title: title,
+		Body:  e.Board + " in " + formatRunTime(e.Time),
+		URL:   e.URL,
+		Game:  resolveLink(e.URL).Game,
+	}
+}
+
+// runnerActivity collects one runner's events since a time: runs they
+// submitted or had verified, and records among their PBs
+func runnerActivity(client *Client, cache *GameCache, name string, since time.Time) ([]runnerEvent, error) {
+	user, err := client.getUser(name)
+	if err != nil {
+		return nil, err
+	}
+	runner := user.Names.International
+
+	submitted, err := client.listRuns(url.Values{
+		"user":      {user.ID},
+		"orderby":   {"submitted"},
+		"direction": {"desc"},
+	}, func(r v1Run) bool {
+		at, err := time.Parse(time.RFC3339, r.Submitted)
+		return err != nil || at.After(since)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s's runs: %w", runner, err)
+	}
+	verified, err := client.listRuns(url.Values{
+		"user":      {user.ID},
+		"status":    {"verified"},
+		"orderby":   {"verify-date"},
+		"direction": {"desc"},
+	}, func(r v1Run) bool {
+		at, err := time.Parse(time.RFC3339, r.Status.VerifyDate)
+		return err != nil || at.After(since)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s's runs: %w", runner, err)
+	}
+	pbs, err := client.GetUserLeaderboard(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []runnerEvent
+	event := func(kind runnerEventKind, r v1Run, at time.Time) runnerEvent {
+		e := runnerEvent{Kind: kind, Runner: runner, follow: name, RunID: r.ID, Time: r.queueRun().Time, URL: r.Weblink, At: at}
+		e.Board = r.Game
+		if data, err := cache.Get(resolveLink(r.Weblink).Game); err == nil {
+			e.Board, e.Millis = data.Game.Name, data.Game.Milliseconds
+			for _, c := range data.Categories {
+				if c.ID == r.Category {
+					e.Board += " - " + c.Name
+				}
+			}
+		}
+		return e
+	}
+	for _, r := range submitted {
+		if at, err := time.Parse(time.RFC3339, r.Submitted); err == nil && at.After(since) {
+			events = append(events, event(eventSubmitted, r, at))
+		}
+	}
+
+	// A record is told as a record rather than as a verification
+	records := make(map[string]bool)
+	for _, r := range pbs.Runs {
+		at := time.Unix(r.DateVerified, 0)
+		if r.Place != 1 || r.Obsolete || !at.After(since) {
+			continue
+		}
+		records[r.ID] = true
+		game := pbs.game(r.GameID)
+		events = append(events, runnerEvent{
+			Kind:   eventRecord,
+			Runner: runner,
+			follow: name,
+			RunID:  r.ID,
+			Board:  pbs.BoardName(r),
+			Time:   r.Duration(),
+			Millis: game.Milliseconds,
+			URL:    pbs.RunURL(r),
+			At:     at,
+		})
+	}
+	for _, r := range verified {
+		if at, err := time.Parse(time.RFC3339, r.Status.VerifyDate); err == nil && at.After(since) && !records[r.ID] {
+			events = append(events, event(eventVerified, r, at))
+		}
+	}
+	return events, nil
+}
+
+// runnerFeed collects every followed runner's events, newest first
+func runnerFeed(client *Client, cache *GameCache, names []string, since time.Time) ([]runnerEvent, error) {
+	var (
+		mu     sync.Mutex
+		events []runnerEvent
+		jobs   []Job
+	)
+	host := hostOf(client.baseURL)
+	for _, name := range names {
+		jobs = append(jobs, Job{Name: name, Host: host, Run: func() error {
+			found, err := runnerActivity(client, cache, name, since)
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, found...)
+			return err
+		}})
+	}
+	err := NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.After(events[j].At) })
+	return events, err
+}
+
+// followSeen is what's already been alerted, kept between checks
+type followSeen struct {
+	Runners []string             `json:"runners"` // followed as of the last check
+	Events  map[string]time.Time `json:"events"`
+}
+
+// freshEvents picks out the events not alerted before and records them.
+// A runner's first check only records, so following someone doesn't
+// alert two weeks of their runs.
+func freshEvents(names []string, events []runnerEvent) ([]runnerEvent, error) {
+	seen := followSeen{Events: make(map[string]time.Time)}
+	if err := loadState(followSeenFile, &seen); err != nil {
+		return nil, err
+	}
+	if seen.Events == nil {
+		seen.Events = make(map[string]time.Time)
+	}
+
+	var fresh []runnerEvent
+	for _, e := range events {
+		if _, ok := seen.Events[e.key()]; ok {
+			continue
+		}
+		seen.Events[e.key()] = e.At
+		if slices.ContainsFunc(seen.Runners, func(name string) bool { return strings.EqualFold(name, e.follow) }) {
+			fresh = append(fresh, e)
+		}
+	}
+	// Events that fell out of the window can't come back
+	for key, at := range seen.Events {
+		if time.Since(at) > followWindow {
+			delete(seen.Events, key)
+		}
+	}
+	seen.Runners = names
+	return fresh, saveState(followSeenFile, seen)
+}
+
+type runnerFeedMsg struct {
+	events []runnerEvent
+	fresh  []runnerEvent // new since the last check, for the sinks
+	err    error
+}
+
+type followCheckMsg struct{}
+
+func scheduleFollowCheck() tea.Cmd {
+	return tea.Tick(followCheckInterval, func(time.Time) tea.Msg {
+		return followCheckMsg{}
+	})
+}
+
+// followModel is the Runners tab: what the followed runners have been up
+// to lately
+type followModel struct {
+	client *Client
+	games  *GameCache
+	times  TimeFormat
+	names  []string
+
+	events   []runnerEvent
+	selected int
+	loaded   bool
+	loading  bool
+	err      error
+
+	input  textinput.Model
+	adding bool
+}
+
+func newFollowModel(client *Client, cfg Config) followModel {
+	input := textinput.New()
+	input.Prompt = "Follow: "
+	input.Placeholder = "user name"
+	input.CharLimit = 50
+	f := followModel{client: client, times: cfg.TimeFormat, names: cfg.FollowedRunners, input: input}
+	f.games, f.err = NewGameCache(client)
+	// The first load starts with the app, for alerts
+	f.loading = f.games != nil && len(f.names) > 0
+	return f
+}
+
+func (f followModel) loadCmd() tea.Cmd {
+	client, cache, names := f.client, f.games, slices.Clone(f.names)
+	return func() tea.Msg {
+		events, err := runnerFeed(client, cache, names, time.Now().Add(-followWindow))
+		if err != nil {
+			// A runner missing from a partial feed would look new next
+			// time, so alerts wait for a full one
+			return runnerFeedMsg{events: events, err: err}
+		}
+		fresh, err := freshEvents(names, events)
+		if err != nil {
+			log.Printf("following: %v", err)
+		}
+		return runnerFeedMsg{events: events, fresh: fresh}
+	}
+}
+
+// check reloads the feed in the background, unless it's loading already
+// or nobody is followed
+func (f followModel) check() (followModel, tea.Cmd) {
+	if f.loading || f.games == nil || len(f.names) == 0 {
+		return f, nil
+	}
+	f.loading = true
+	return f, f.loadCmd()
+}
+
+func (f followModel) activate() (followModel, tea.Cmd) {
+	if f.loaded {
+		return f, nil
+	}
+	return f.check()
+}
+
+func (f followModel) capturing() bool {
+	return f.adding
+}
+
+func (f followModel) following(name string) bool {
+	return slices.ContainsFunc(f.names, func(n string) bool { return strings.EqualFold(n, name) })
+}
+
+// toggle follows or unfollows a runner and saves the list to the config
+func (f followModel) toggle(name string) (followModel, tea.Cmd) {
+	names := slices.DeleteFunc(slices.Clone(f.names), func(n string) bool { return strings.EqualFold(n, name) })
+	followed := len(names) == len(f.names)
+	if followed {
+		names = append(names, name)
+	}
+	if err := saveConfigField("followed_runners", names); err != nil {
+		return f, statusCmd("Couldn't save followed runners: %v", err)
+	}
+	f.names = names
+
+	if !followed {
+		f.events = slices.DeleteFunc(f.events, func(e runnerEvent) bool { return strings.EqualFold(e.Runner, name) })
+		f.selected = min(f.selected, max(len(f.events)-1, 0))
+		return f, statusCmd("Unfollowed %s", name)
+	}
+	f, cmd := f.check()
+	return f, tea.Batch(cmd, statusCmd("Following %s", name))
+}
+
+func (f followModel) selectedEvent() (runnerEvent, bool) {
+	if f.selected >= len(f.events) {
+		return runnerEvent{}, false
+	}
+	return f.events[f.selected], true
+}
+
+func (f followModel) update(msg tea.Msg) (followModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case runnerFeedMsg:
+		f.loading = false
+		f.loaded = true
+		f.events, f.err = msg.events, msg.err
+		f.selected = min(f.selected, max(len(f.events)-1, 0))
+	case tea.KeyMsg:
+		if f.adding {
+			switch msg.String() {
+			case "enter":
+				name := strings.TrimSpace(f.input.Value())
+				f.adding = false
+				f.input.Blur()
+				f.input.SetValue("")
+				if name == "" || f.following(name) {
+					return f, nil
+				}
+				return f.toggle(name)
+			case "esc":
+				f.adding = false
+				f.input.Blur()
+				return f, nil
+			}
+			var cmd tea.Cmd
+			f.input, cmd = f.input.Update(msg)
+			return f, cmd
+		}
+		switch msg.String() {
+		case "up", "k":
+			if f.selected > 0 {
+				f.selected--
+			}
+		case "down", "j":
+			if f.selected < len(f.events)-1 {
+				f.selected++
+			}
+		case "a":
+			f.adding = true
+			return f, f.input.Focus()
+		case "r":
+			return f.check()
+		}
+	default:
+		// Cursor blinks
+		if f.adding {
+			var cmd tea.Cmd
+			f.input, cmd = f.input.Update(msg)
+			return f, cmd
+		}
+	}
+	return f, nil
+}
+
+func (f followModel) view() string {
+	var b strings.Builder
+	if f.adding {
+		b.WriteString(f.input.View() + "\n\n")
+	}
+	if len(f.names) == 0 {
+		b.WriteString(urlStyle.Render("Not following anyone yet. Press a to follow a runner, or f on their profile."))
+		return b.String()
+	}
+	b.WriteString(urlStyle.Render("Following "+strings.Join(f.names, ", ")) + "\n\n")
+	if f.loading && !f.loaded {
+		b.WriteString("Catching up on runners...")
+		return b.String()
+	}
+	if f.err != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n\n", f.err))
+	}
+	if len(f.events) == 0 {
+		b.WriteString("Nothing in the last 14 days")
+		return b.String()
+	}
+
+	day := ""
+	for i, e := range f.events {
+		if d := e.At.Local().Format("Monday, January 2"); d != day {
+			if day != "" {
+				b.WriteString("\n")
+			}
+			day = d
+			b.WriteString(titleStyle.Render(day) + "\n")
+		}
+		what := fmt.Sprintf("%-9s", runnerEventNames[e.Kind])
+		if e.Kind == eventRecord {
+			what = newRecordStyle.Render(what)
+		}
+		line := fmt.Sprintf("%s  %s %s  %s in %s", e.At.Local().Format("15:04"), what, e.Runner, e.Board, f.times.render(e.Time, e.Millis))
+		style := unselectedItemStyle
+		if i == f.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(line) + "\n")
+	}
+	return b.String()
+}
+
+func (f followModel) help() string {
+	if f.adding {
+		return "enter follow • esc cancel"
+	}
+	return "↑/↓ navigate • enter open run • a follow a runner • r refresh"
+}
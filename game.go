@@ -0,0 +1,89 @@
+package main
+
+import "fmt"
+
+// Static game metadata. These only change when a moderator edits the game,
+// so they're served from the disk cache (see cache.go).
+type Game struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	Milliseconds bool   `json:"milliseconds"`
+	RequireVideo bool   `json:"requireVideo"`
+	Emulator     int    `json:"emulator"`
+}
+
+type Category struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Pos        int    `json:"pos"`
+	GameID     string `json:"gameId"`
+	IsMisc     bool   `json:"isMisc"`
+	IsPerLevel bool   `json:"isPerLevel"`
+	Archived   bool   `json:"archived"`
+}
+
+type Level struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Pos    int    `json:"pos"`
+	GameID string `json:"gameId"`
+}
+
+type Variable struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	Pos           int    `json:"pos"`
+	GameID        string `json:"gameId"`
+	CategoryID    string `json:"categoryId"`
+	IsSubcategory bool   `json:"isSubcategory"`
+	IsMandatory   bool   `json:"isMandatory"`
+	DefaultValue  string `json:"defaultValue"`
+}
+
+type VariableValue struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Pos        int    `json:"pos"`
+	VariableID string `json:"variableId"`
+	IsMisc     bool   `json:"isMisc"`
+}
+
+type Platform struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Year int    `json:"year"`
+}
+
+type GameData struct {
+	Game       Game            `json:"game"`
+	Categories []Category      `json:"categories"`
+	Levels     []Level         `json:"levels"`
+	Variables  []Variable      `json:"variables"`
+	Values     []VariableValue `json:"values"`
+	Platforms  []Platform      `json:"platforms"`
+	Theme      *GameTheme      `json:"theme"`
+}
+
+// GetGameData fetches a game's categories, levels, variables, platforms
+// and site theme.
+// game is the abbreviation used in site URLs, e.g. "sm64".
+func (c *Client) GetGameData(game string) (*GameData, error) {
+	body := struct {
+		GameURL string `json:"gameUrl"`
+	}{
+		GameURL: game,
+	}
+
+	var result GameData
+	if err := c.post("GetGameData", body, &result); err != nil {
+		return nil, fmt.Errorf("fetching game data for %s: %w", game, err)
+	}
+
+	return &result, nil
+}
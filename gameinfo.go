@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Moderator roles on v1, most powerful first
+var modRoles = map[string]int{
+	"super-moderator": 0,
+	"moderator":       1,
+	"verifier":        2,
+}
+
+// gameModerator is someone who moderates or verifies a game
+type gameModerator struct {
+	Name string
+	Role string // "super-moderator", "moderator" or "verifier"
+	URL  string
+}
+
+// gameInfo is who runs a game and what it takes to submit there
+type gameInfo struct {
+	Moderators          []gameModerator
+	RequireVideo        bool
+	EmulatorsAllowed    bool
+	RequireVerification bool
+	Milliseconds        bool
+	RunTimes            []string // "realtime", "realtime_noloads", "ingame"
+	DefaultTime         string
+}
+
+type gameInfoMsg struct {
+	info *gameInfo
+	err  error
+}
+
+// GetGameInfo fetches a game's moderators with their roles and its
+// submission rules. Embedding the moderators on v1 drops their roles, so
+// it takes a request for each.
+func (c *Client) GetGameInfo(gameID string) (*gameInfo, error) {
+	var game struct {
+		Data struct {
+			Moderators map[string]string `json:"moderators"`
+			Ruleset    struct {
+				ShowMilliseconds    bool     `json:"show-milliseconds"`
+				RequireVerification bool     `json:"require-verification"`
+				RequireVideo        bool     `json:"require-video"`
+				RunTimes            []string `json:"run-times"`
+				DefaultTime         string   `json:"default-time"`
+				EmulatorsAllowed    bool     `json:"emulators-allowed"`
+			} `json:"ruleset"`
+		} `json:"data"`
+	}
+	if err := c.getV1("/games/"+url.PathEscape(gameID), &game); err != nil {
+		return nil, fmt.Errorf("fetching game: %w", err)
+	}
+
+	var users struct {
+		Data struct {
+			Moderators struct {
+				Data []v1User `json:"data"`
+			} `json:"moderators"`
+		} `json:"data"`
+	}
+	if err := c.getV1("/games/"+url.PathEscape(gameID)+"?embed=moderators", &users); err != nil {
+		return nil, fmt.Errorf("fetching moderators: %w", err)
+	}
+
+	rules := game.Data.Ruleset
+	info := &gameInfo{
+		RequireVideo:        rules.RequireVideo,
+		EmulatorsAllowed:    rules.EmulatorsAllowed,
+		RequireVerification: rules.RequireVerification,
+		Milliseconds:        rules.ShowMilliseconds,
+		RunTimes:            rules.RunTimes,
+		DefaultTime:         rules.DefaultTime,
+	}
+	for _, u := range users.Data.Moderators.Data {
+		info.Moderators = append(info.Moderators, gameModerator{
+			Name: u.Names.International,
+			Role: game.Data.Moderators[u.ID],
+			URL:  u.Weblink,
+		})
+	}
+	sort.SliceStable(info.Moderators, func(i, j int) bool {
+		a, b := info.Moderators[i], info.Moderators[j]
+		if modRoles[a.Role] != modRoles[b.Role] {
+			return modRoles[a.Role] < modRoles[b.Role]
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return info, nil
+}
+
+func (b boardsModel) loadInfoCmd() tea.Cmd {
+	client, gameID := b.client, b.game.Game.ID
+	return func() tea.Msg {
+		info, err := client.GetGameInfo(gameID)
+		return gameInfoMsg{info: info, err: err}
+	}
+}
+
+// runTimeNames are the site's names for the timing methods
+var runTimeNames = map[string]string{
+	"realtime":         "real time",
+	"realtime_noloads": "without loads",
+	"ingame":           "in-game time",
+}
+
+func (b boardsModel) infoView() string {
+	info := b.info
+	yesNo := func(v bool) string {
+		if v {
+			return "yes"
+		}
+		return "no"
+	}
+	var timing []string
+	for _, t := range info.RunTimes {
+		name := runTimeNames[t]
+		if name == "" {
+			name = t
+		}
+		if t == info.DefaultTime {
+			name += " (default)"
+		}
+		timing = append(timing, name)
+	}
+
+	var out strings.Builder
+	out.WriteString(titleStyle.Render("Submitting") + "\n")
+	fmt.Fprintf(&out, "  Video required      %s\n", yesNo(info.RequireVideo))
+	fmt.Fprintf(&out, "  Emulators allowed   %s\n", yesNo(info.EmulatorsAllowed))
+	fmt.Fprintf(&out, "  Runs are verified   %s\n", yesNo(info.RequireVerification))
+	fmt.Fprintf(&out, "  Milliseconds        %s\n", yesNo(info.Milliseconds))
+	if len(timing) > 0 {
+		fmt.Fprintf(&out, "  Timing              %s\n", strings.Join(timing, ", "))
+	}
+
+	out.WriteString("\n" + titleStyle.Render(fmt.Sprintf("Moderators (%d)", len(info.Moderators))) + "\n")
+	if len(info.Moderators) == 0 {
+		out.WriteString(urlStyle.Render("  nobody moderates this game") + "\n")
+	}
+	for i, mod := range info.Moderators {
+		role := strings.ReplaceAll(mod.Role, "-", " ")
+		row := fmt.Sprintf("%-24s %-16s %s", truncate(mod.Name, 24), role, urlStyle.Render(mod.URL))
+		style := boardRowStyle
+		if i == b.selected {
+			style = boardSelectedRowStyle
+		}
+		out.WriteString(style.Render(row) + "\n")
+	}
+	return out.String()
+}
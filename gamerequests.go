@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// Last seen status of each game request, by ID
+	gameRequestsFile = "game_requests.json"
+
+	// Requests take days to be looked at, so there's no hurry
+	gameRequestCheckInterval = 30 * time.Minute
+)
+
+// GameRequest is a request to add a game to the site
+type GameRequest struct {
+	ID       string `json:"id"`
+	GameName string `json:"gameName"`
+	GameURL  string `json:"gameUrl"` // the new game's abbreviation, once accepted
+	Status   string `json:"status"`  // pending, accepted or rejected
+	Reason   string `json:"reason"`  // rejections
+	Date     int64  `json:"date"`    // filed, Unix seconds
+}
+
+// GetGameRequests lists the game requests a user has filed, newest first
+func (c *Client) GetGameRequests(userID string) ([]GameRequest, error) {
+	body := struct {
+		UserID string `json:"userId"`
+	}{
+		UserID: userID,
+	}
+
+	var result struct {
+		GameRequests []GameRequest `json:"gameRequestList"`
+	}
+	if err := c.post("GetGameRequestList", body, &result); err != nil {
+		return nil, fmt.Errorf("fetching game requests: %w", err)
+	}
+	return result.GameRequests, nil
+}
+
+// GameRequestAlert reports a game request whose status changed since the
+// last check
+type GameRequestAlert struct {
+	Request GameRequest
+	Old     string
+}
+
+func (a GameRequestAlert) String() string {
+	s := fmt.Sprintf("● Game request %s: %s → %s", a.Request.GameName, a.Old, a.Request.Status)
+	if a.Request.Reason != "" {
+		s += " • " + a.Request.Reason
+	}
+	return s
+}
+
+func (a GameRequestAlert) Alert() Alert {
+	alert := Alert{
+		Title: fmt.Sprintf("Your request for %s was %s", a.Request.GameName, a.Request.Status),
+		Body:  a.Request.Reason,
+		Game:  a.Request.GameURL,
+	}
+	if a.Request.GameURL != "" {
+		alert.URL = "https://www.speedrun.com/" + a.Request.GameURL
+	}
+	return alert
+}
+
+// checkGameRequests fetches the signed in user's game requests and reports
+// every one whose status changed. A request seen for the first time
+// doesn't alert, nor does anything on the first check.
+func checkGameRequests(client *Client) ([]GameRequest, []GameRequestAlert, error) {
+	session, err := client.GetSession()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !session.SignedIn || session.User == nil {
+		return nil, nil, errors.New("session is not signed in")
+	}
+	requests, err := client.GetGameRequests(session.User.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous := make(map[string]string)
+	if err := loadState(gameRequestsFile, &previous); err != nil {
+		return requests, nil, err
+	}
+	current := make(map[string]string, len(requests))
+	var alerts []GameRequestAlert
+	for _, r := range requests {
+		current[r.ID] = r.Status
+		if old, ok := previous[r.ID]; ok && old != r.Status {
+			alerts = append(alerts, GameRequestAlert{Request: r, Old: old})
+		}
+	}
+	if err := saveState(gameRequestsFile, current); err != nil {
+		return requests, alerts, err
+	}
+	return requests, alerts, nil
+}
+
+type gameRequestsMsg struct {
+	requests []GameRequest
+	alerts   []GameRequestAlert
+	err      error
+}
+
+type gameRequestCheckMsg struct{}
+
+func checkGameRequestsCmd(client *Client) tea.Cmd {
+	return func() tea.Msg {
+		requests, alerts, err := checkGameRequests(client)
+		return gameRequestsMsg{requests: requests, alerts: alerts, err: err}
+	}
+}
+
+func scheduleGameRequestCheck() tea.Cmd {
+	return tea.Tick(gameRequestCheckInterval, func(time.Time) tea.Msg {
+		return gameRequestCheckMsg{}
+	})
+}
+
+// gameRequestsView lists the requests for the Submissions tab
+func gameRequestsView(requests []GameRequest) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Game requests (%d)", len(requests))) + "\n")
+	now := time.Now()
+	for _, r := range requests {
+		status := urlStyle.Render(r.Status)
+		switch r.Status {
+		case "accepted":
+			status = okStyle.Render(r.Status)
+		case "rejected":
+			status = failStyle.Render(r.Status)
+		}
+		line := fmt.Sprintf("%s  %s", r.GameName, status)
+		if r.Date > 0 {
+			line += urlStyle.Render("  filed " + formatAge(now.Sub(time.Unix(r.Date, 0))) + " ago")
+		}
+		if r.Reason != "" {
+			line += "\n  " + urlStyle.Render(truncate(r.Reason, 70))
+		}
+		b.WriteString(unselectedItemStyle.Render(line) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Goal is a time to beat in a category, like sub 20 or the world record's
+// pace. Boards and personal bests show how far runs are from it, and the
+// timer paces against it.
+type Goal struct {
+	Game     string `json:"game"`     // abbreviation, e.g. "sm64"
+	Category string `json:"category"` // name as the site shows it
+	// A run time like "1:40:00", or "wr" for the current world record
+	Time string `json:"time"`
+	// The timer name it paces, e.g. "sm64-120"
+	Timer string `json:"timer,omitempty"`
+}
+
+func validateGoals(goals []Goal) error {
+	for i, g := range goals {
+		if g.Game == "" || g.Category == "" {
+			return fmt.Errorf("goals: goal %d needs a game and a category", i+1)
+		}
+		if strings.EqualFold(g.Time, "wr") {
+			continue
+		}
+		if _, err := parseRunTime(g.Time); err != nil {
+			return fmt.Errorf("goals: %s %s: %w", g.Game, g.Category, err)
+		}
+	}
+	return nil
+}
+
+// fixed is the goal's time, or false when it follows the world record
+func (g Goal) fixed() (time.Duration, bool) {
+	d, err := parseRunTime(g.Time)
+	return d, err == nil
+}
+
+// label is "sub 1:40:00" or "WR pace"
+func (g Goal) label() string {
+	if d, ok := g.fixed(); ok {
+		return "sub " + formatRunTime(d)
+	}
+	return "WR pace"
+}
+
+// findGoal is the goal for a game's category, if one is set
+func findGoal(goals []Goal, game, category string) (Goal, bool) {
+	for _, g := range goals {
+		if strings.EqualFold(g.Game, game) && strings.EqualFold(g.Category, category) {
+			return g, true
+		}
+	}
+	return Goal{}, false
+}
+
+// timerGoal is the goal that paces a timer name
+func timerGoal(goals []Goal, name string) (Goal, bool) {
+	for _, g := range goals {
+		if g.Timer != "" && strings.EqualFold(g.Timer, name) {
+			return g, true
+		}
+	}
+	return Goal{}, false
+}
+
+// goalLine says where the selected run stands against the board's goal,
+// with the world record as a "wr" goal's time
+func (b boardsModel) goalLine() string {
+	if b.game == nil || b.board == nil || len(b.board.Runs) == 0 {
+		return ""
+	}
+	goal, ok := findGoal(b.goals, b.game.Game.URL, b.category.Name)
+	if !ok {
+		return ""
+	}
+	target, ok := goal.fixed()
+	if !ok {
+		if top := b.board.Runs[0]; top.Place == 1 {
+			target = top.Duration()
+		}
+	}
+	r, selected := b.selectedRun()
+	if target == 0 || !selected {
+		return ""
+	}
+	return urlStyle.Render("Goal "+goal.label()+" ") + renderDelta(r.Duration()-target)
+}
+
+type timerGoalMsg struct {
+	name string
+	time time.Duration
+	err  error
+}
+
+// goalCmd looks up the world record's time for a timer paced against it
+func (t timerModel) goalCmd() tea.Cmd {
+	goal, ok := timerGoal(t.opts.goals, t.name)
+	if !ok || t.opts.client == nil {
+		return nil
+	}
+	if _, fixed := goal.fixed(); fixed {
+		return nil
+	}
+	client, name := t.opts.client, t.name
+	return func() tea.Msg {
+		wr, err := worldRecordTime(client, goal)
+		return timerGoalMsg{name: name, time: wr, err: err}
+	}
+}
+
+// worldRecordTime is the top time on a goal category's default board
+func worldRecordTime(client *Client, goal Goal) (time.Duration, error) {
+	data, err := client.GetGameData(goal.Game)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range data.Categories {
+		if !strings.EqualFold(c.Name, goal.Category) {
+			continue
+		}
+		lb, err := client.GetLeaderboard(defaultParams(data, c), 1)
+		if err != nil {
+			return 0, err
+		}
+		if len(lb.Runs) == 0 {
+			return 0, fmt.Errorf("%s has no runs yet", goal.Category)
+		}
+		return lb.Runs[0].Duration(), nil
+	}
+	return 0, fmt.Errorf("%s has no category %q", goal.Game, goal.Category)
+}
+
+// goalSplits are the timer's goal as splits: the PB's split times scaled
+// to the goal, or just the goal's final time without a PB to shape it
+func (t timerModel) goalSplits() []time.Duration {
+	if t.goal == 0 {
+		return nil
+	}
+	pb := t.record.pbFinal()
+	if pb == 0 {
+		return []time.Duration{t.goal}
+	}
+	splits := make([]time.Duration, len(t.record.PB))
+	for i, s := range t.record.PB {
+		splits[i] = time.Duration(float64(s) * float64(t.goal) / float64(pb))
+	}
+	return splits
+}
+
+// onPace reports whether the running time is ahead of the goal's split for
+// the segment being run, and false when there's nothing to compare with
+func (t timerModel) onPace() (ahead, ok bool) {
+	splits := t.goalSplits()
+	if len(splits) == 0 || !t.running && !t.finished {
+		return false, false
+	}
+	i := min(len(t.splits), len(splits)-1)
+	if t.finished {
+		i = len(splits) - 1
+	}
+	return t.elapsed() <= splits[i], true
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// The API allows about 100 requests a minute per IP. Without rate
+	// limit headers the budget is counted from our own requests.
+	siteRateLimit  = 100
+	siteRateWindow = time.Minute
+
+	// Failures in a row before the site counts as unreachable
+	offlineAfter = 3
+
+	slowLatency = 2 * time.Second
+)
+
+var (
+	healthOKStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
+	healthSlowStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
+	healthDownStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
+)
+
+// apiHealth keeps track of how the site has been answering
+type apiHealth struct {
+	mu        sync.Mutex
+	sent      []time.Time // within the last siteRateWindow
+	latency   time.Duration
+	remaining int // from the response headers, -1 when they're missing
+	limit     int
+	failures  int // in a row
+	limited   bool
+	requests  int
+}
+
+// healthSnapshot is apiHealth at a point in time, for rendering
+type healthSnapshot struct {
+	Requests  int
+	Latency   time.Duration
+	Remaining int
+	Limit     int
+	Offline   bool
+	Limited   bool
+}
+
+// record notes how a request went. resp is nil when it never got an answer.
+func (h *apiHealth) record(resp *http.Response, err error, latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.requests++
+	h.sent = append(h.sent, now)
+	for len(h.sent) > 0 && now.Sub(h.sent[0]) > siteRateWindow {
+		h.sent = h.sent[1:]
+	}
+
+	if err != nil || resp == nil {
+		h.failures++
+		return
+	}
+	h.latency = latency
+	h.limited = resp.StatusCode == http.StatusTooManyRequests
+	if resp.StatusCode >= 500 {
+		h.failures++
+	} else {
+		h.failures = 0
+	}
+
+	h.remaining, h.limit = -1, 0
+	if v, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		h.remaining = v
+		h.limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	}
+}
+
+func (h *apiHealth) snapshot() healthSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := healthSnapshot{
+		Requests:  h.requests,
+		Latency:   h.latency,
+		Remaining: h.remaining,
+		Limit:     h.limit,
+		Offline:   h.failures >= offlineAfter,
+		Limited:   h.limited,
+	}
+	if s.Remaining < 0 || s.Limit == 0 {
+		recent := 0
+		for _, t := range h.sent {
+			if time.Since(t) <= siteRateWindow {
+				recent++
+			}
+		}
+		s.Remaining, s.Limit = max(siteRateLimit-recent, 0), siteRateLimit
+	}
+	return s
+}
+
+// Health reports latency, rate budget and reachability of the API
+func (c *Client) Health() healthSnapshot {
+	return c.health.snapshot()
+}
+
+// render is the status bar segment, e.g. "● 240ms 87/100"
+func (s healthSnapshot) render() string {
+	switch {
+	case s.Requests == 0:
+		return ""
+	case s.Offline:
+		return healthDownStyle.Render("● offline")
+	case s.Limited:
+		return healthDownStyle.Render(fmt.Sprintf("● rate limited 0/%d", s.Limit))
+	}
+	style := healthOKStyle
+	if s.Latency >= slowLatency || s.Remaining < s.Limit/5 {
+		style = healthSlowStyle
+	}
+	return style.Render(fmt.Sprintf("● %s %d/%d", s.Latency.Round(time.Millisecond), s.Remaining, s.Limit))
+}
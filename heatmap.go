@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const heatmapWeeks = 26
+
+// Empty day first, then increasing activity
+var heatmapLevels = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#2D333B")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#0E4429")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#006D32")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#26A641")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#39D353")),
+}
+
+// dailyCounts buckets history entries by local calendar day, counting
+// the ones keep accepts
+func dailyCounts(entries []HistoryEntry, keep func(Notification) bool) map[string]int {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if keep == nil || keep(e.Notification) {
+			counts[e.Time().Local().Format(time.DateOnly)]++
+		}
+	}
+	return counts
+}
+
+// heatmapLevel scales a day's count against the busiest day shown
+func heatmapLevel(count, top int) int {
+	if count == 0 || top == 0 {
+		return 0
+	}
+	return 1 + (count-1)*(len(heatmapLevels)-2)/max(top-1, 1)
+}
+
+// renderHeatmap draws weeks columns of days, Monday at the top, ending
+// with the week containing end, like a GitHub contribution graph.
+func renderHeatmap(counts map[string]int, end time.Time, weeks int) string {
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	// Back up to the Monday starting the first column
+	offset := (int(end.Weekday()) + 6) % 7
+	start := end.AddDate(0, 0, -offset-7*(weeks-1))
+
+	top, total := 0, 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		n := counts[d.Format(time.DateOnly)]
+		top = max(top, n)
+		total += n
+	}
+
+	var b strings.Builder
+
+	// Month labels over the column where each month starts
+	labels := []rune(strings.Repeat(" ", weeks*2))
+	for w := range weeks {
+		first := start.AddDate(0, 0, 7*w)
+		if w == 0 || first.Month() != first.AddDate(0, 0, -7).Month() {
+			month := first.Format("Jan")
+			if w*2+len(month) <= len(labels) {
+				copy(labels[w*2:], []rune(month))
+			}
+		}
+	}
+	b.WriteString("    " + strings.TrimRight(string(labels), " ") + "\n")
+
+	dayNames := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	for day := range 7 {
+		b.WriteString(fmt.Sprintf("%-4s", dayNames[day]))
+		for w := range weeks {
+			d := start.AddDate(0, 0, 7*w+day)
+			if d.After(end) {
+				break
+			}
+			level := heatmapLevel(counts[d.Format(time.DateOnly)], top)
+			b.WriteString(heatmapLevels[level].Render("■") + " ")
+		}
+		b.WriteString("\n")
+	}
+
+	legend := "    less "
+	for _, s := range heatmapLevels {
+		legend += s.Render("■") + " "
+	}
+	b.WriteString(legend + "more")
+	b.WriteString(urlStyle.Render(fmt.Sprintf("   %d in %d weeks, busiest day %d", total, weeks, top)))
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ? shows every key the screen takes, one per line, and what the badges on
+// its rows mean
+
+// helpOverlay is the screen's keys shown over it until the next key
+type helpOverlay struct {
+	keys   []string
+	legend []string
+}
+
+func (m model) newHelpOverlay() *helpOverlay {
+	o := &helpOverlay{keys: strings.Split(m.screenHelp(), " • ")}
+	switch m.screen {
+	case screenBoards:
+		if m.boards.level == boardsBoard {
+			o.legend = m.boards.legend()
+		}
+	case screenQueue:
+		o.legend = m.queue.legend()
+	}
+	return o
+}
+
+func (o *helpOverlay) view(width, height int) string {
+	sections := []string{titleStyle.Render("Keys"), ""}
+
+	// Long lists of keys wrap into columns
+	rows := max(height-len(o.legend)-8, 4)
+	var cols []string
+	for i := 0; i < len(o.keys); i += rows {
+		col := strings.Join(o.keys[i:min(i+rows, len(o.keys))], "\n")
+		cols = append(cols, lipgloss.NewStyle().PaddingRight(4).Render(col))
+	}
+	sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, cols...))
+
+	if len(o.legend) > 0 {
+		sections = append(sections, "", titleStyle.Render("Platforms"), "", strings.Join(o.legend, "\n"))
+	}
+	sections = append(sections, "", urlStyle.Render("any key closes"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	histogramBuckets  = 20
+	histogramBarWidth = 40
+)
+
+// Bucket sizes to pick from, so the edges land on times people talk about
+var histogramSteps = []time.Duration{
+	time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 15 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 5 * time.Hour,
+}
+
+// Shares of the board shown as "what it takes" lines
+var histogramPercentiles = []int{1, 5, 10, 25, 50}
+
+type distributionMsg struct {
+	times []time.Duration // sorted, fastest first
+	err   error
+}
+
+// GetBoardTimes fetches every page of a board and returns the ranked run
+// times, fastest first
+func (c *Client) GetBoardTimes(params LeaderboardParams) ([]time.Duration, error) {
+	first, err := c.GetLeaderboard(params, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu    sync.Mutex
+		times []time.Duration
+	)
+	collect := func(board *Leaderboard) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, r := range board.Runs {
+			if !r.Obsolete && r.Place > 0 {
+				times = append(times, r.Duration())
+			}
+		}
+	}
+	collect(first)
+
+	var jobs []Job
+	host := hostOf(c.baseURL)
+	for page := 2; page <= first.Pagination.Pages; page++ {
+		jobs = append(jobs, Job{Name: fmt.Sprintf("page %d", page), Host: host, Run: func() error {
+			board, err := c.GetLeaderboard(params, page)
+			if err != nil {
+				return err
+			}
+			collect(board)
+			return nil
+		}})
+	}
+	err = NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs)
+
+	slices.Sort(times)
+	return times, err
+}
+
+func (b boardsModel) loadDistributionCmd() tea.Cmd {
+	client, params := b.client, b.params
+	return func() tea.Msg {
+		times, err := client.GetBoardTimes(params)
+		return distributionMsg{times: times, err: err}
+	}
+}
+
+type histogramBucket struct {
+	From  time.Duration
+	Count int
+}
+
+// bucketTimes splits sorted times into equal buckets starting at the
+// fastest. The slowest 5% would stretch the scale, so times past the 95th
+// percentile are counted separately.
+func bucketTimes(times []time.Duration, maxBuckets int) ([]histogramBucket, time.Duration, int) {
+	if len(times) == 0 {
+		return nil, 0, 0
+	}
+	lo, hi := times[0], times[(len(times)-1)*95/100]
+
+	step := histogramSteps[len(histogramSteps)-1]
+	for _, s := range histogramSteps {
+		if int((hi-lo.Truncate(s))/s)+1 <= maxBuckets {
+			step = s
+			break
+		}
+	}
+	start := lo.Truncate(step)
+
+	buckets := make([]histogramBucket, int((hi-start)/step)+1)
+	for i := range buckets {
+		buckets[i].From = start + time.Duration(i)*step
+	}
+	slower := 0
+	for _, t := range times {
+		i := int((t - start) / step)
+		if i >= len(buckets) {
+			slower++
+			continue
+		}
+		buckets[i].Count++
+	}
+	return buckets, step, slower
+}
+
+func (b boardsModel) distributionView() string {
+	times := b.distribution
+	if len(times) == 0 {
+		return "No runs on this board yet"
+	}
+
+	buckets, _, slower := bucketTimes(times, histogramBuckets)
+	top := slower
+	for _, bk := range buckets {
+		top = max(top, bk.Count)
+	}
+
+	var out strings.Builder
+	out.WriteString(urlStyle.Render(fmt.Sprintf("%d runs", len(times))))
+	out.WriteString("\n\n")
+	bar := func(label string, count int) {
+		width := count * histogramBarWidth / max(top, 1)
+		if count > 0 {
+			width = max(width, 1)
+		}
+		out.WriteString(fmt.Sprintf("%12s  %s %d\n", label, progressFullStyle.Render(strings.Repeat("█", width)), count))
+	}
+	for _, bk := range buckets {
+		bar(b.times.render(bk.From, false), bk.Count)
+	}
+	if slower > 0 {
+		bar("slower", slower)
+	}
+
+	out.WriteString("\n" + titleStyle.Render("What it takes") + "\n")
+	for _, p := range histogramPercentiles {
+		rank := max(len(times)*p/100, 1)
+		out.WriteString(fmt.Sprintf("  top %2d%%  %12s  (rank %d)\n", p, b.times.render(times[rank-1], b.millis()), rank))
+	}
+	return out.String()
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Every notification the app has fetched, one JSON object per line, so
+// history outlives the site's short notification list.
+const historyFile = "history.jsonl"
+
+type HistoryEntry struct {
+	Notification Notification `json:"notification"`
+	Seen         time.Time    `json:"seen"` // when the app first fetched it
+}
+
+// Time is when the notification happened, or when we first saw it if the
+// API didn't say
+func (e HistoryEntry) Time() time.Time {
+	if e.Notification.Date != 0 {
+		return time.Unix(e.Notification.Date, 0)
+	}
+	return e.Seen
+}
+
+func historyPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyFile), nil
+}
+
+// loadHistory reads the archive in the order notifications were first
+// seen. Lines that fail to parse are skipped.
+func loadHistory() ([]HistoryEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			logOnce(fmt.Sprintf("history.line.%d", line), "history: skipping line %d: %v", line, err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("reading history: %w", err)
+	}
+	return entries, nil
+}
+
+// archiveNotifications appends the notifications not archived yet
+func archiveNotifications(notifications []Notification) error {
+	entries, err := loadHistory()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		known[e.Notification.ID] = true
+	}
+
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	now := time.Now()
+	for _, n := range notifications {
+		if n.ID == "" || known[n.ID] {
+			continue
+		}
+		known[n.ID] = true
+		if err := enc.Encode(HistoryEntry{Notification: n, Seen: now}); err != nil {
+			return fmt.Errorf("writing history: %w", err)
+		}
+	}
+	return nil
+}
+
+// filterHistory keeps entries from the start of from's day to the end of
+// to's day. Zero times leave that end open.
+func filterHistory(entries []HistoryEntry, from, to time.Time) []HistoryEntry {
+	var kept []HistoryEntry
+	for _, e := range entries {
+		t := e.Time()
+		if !from.IsZero() && t.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !t.Before(to.AddDate(0, 0, 1)) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+func writeHistoryCSV(w io.Writer, entries []HistoryEntry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "date", "type", "title", "url", "read"})
+	for _, e := range entries {
+		n := e.Notification
+		cw.Write([]string{
+			n.ID,
+			e.Time().Format(time.RFC3339),
+			n.Type,
+			n.Title,
+			"https://www.speedrun.com" + n.Path,
+			strconv.FormatBool(n.Read),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeHistoryJSON(w io.Writer, entries []HistoryEntry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if entries == nil {
+		entries = []HistoryEntry{}
+	}
+	return enc.Encode(entries)
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "json", "json or csv")
+	fromFlag := fs.String("from", "", "first day to include, YYYY-MM-DD")
+	toFlag := fs.String("to", "", "last day to include, YYYY-MM-DD")
+	out := fs.String("o", "", "write to this file instead of stdout")
+	fs.Parse(args)
+
+	var from, to time.Time
+	var err error
+	if *fromFlag != "" {
+		if from, err = time.ParseInLocation(time.DateOnly, *fromFlag, time.Local); err != nil {
+			return fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if *toFlag != "" {
+		if to, err = time.ParseInLocation(time.DateOnly, *toFlag, time.Local); err != nil {
+			return fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+
+	var write func(io.Writer, []HistoryEntry) error
+	switch *format {
+	case "json":
+		write = writeHistoryJSON
+	case "csv":
+		write = writeHistoryCSV
+	default:
+		return fmt.Errorf("unknown format %q, use json or csv", *format)
+	}
+
+	entries, err := loadHistory()
+	if err != nil {
+		return err
+	}
+	entries = filterHistory(entries, from, to)
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := write(w, entries); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d notifications to %s\n", len(entries), *out)
+	}
+	return nil
+}
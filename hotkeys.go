@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The timer's global hotkeys work while another window has focus, like a
+// game's. They're read from the keyboard devices themselves, which only
+// Linux allows without a helper.
+
+type timerAction int
+
+const (
+	timerSplit timerAction = iota // starts the timer when it's stopped
+	timerFinish
+	timerUndo
+	timerReset
+)
+
+// TimerHotkeys names the keys for the timer's global hotkeys. The keypad
+// defaults match LiveSplit's.
+type TimerHotkeys struct {
+	Off    bool   `json:"off,omitempty"`
+	Split  string `json:"split,omitempty"`
+	Finish string `json:"finish,omitempty"`
+	Undo   string `json:"undo,omitempty"`
+	Reset  string `json:"reset,omitempty"`
+}
+
+// Linux input key codes by the names the config uses. Keys the timer
+// screen takes itself (space, enter and the keypad's enter, + and -) are
+// left out, or a press would work the timer twice while the terminal has
+// focus.
+var hotkeyCodes = map[string]uint16{
+	"kp0": 82, "kp1": 79, "kp2": 80, "kp3": 81, "kp4": 75,
+	"kp5": 76, "kp6": 77, "kp7": 71, "kp8": 72, "kp9": 73, "kpdot": 83,
+	"f1": 59, "f2": 60, "f3": 61, "f4": 62, "f5": 63, "f6": 64,
+	"f7": 65, "f8": 66, "f9": 67, "f10": 68, "f11": 87, "f12": 88,
+	"home": 102, "end": 107, "pageup": 104, "pagedown": 109,
+	"insert": 110, "delete": 111, "pause": 119, "scrolllock": 70,
+}
+
+// keys maps each hotkey's code to its action, with the defaults filled in
+func (h TimerHotkeys) keys() (map[uint16]timerAction, error) {
+	names := []struct {
+		name, fallback string
+		action         timerAction
+	}{
+		{h.Split, "kp1", timerSplit},
+		{h.Finish, "kp2", timerFinish},
+		{h.Undo, "kp8", timerUndo},
+		{h.Reset, "kp3", timerReset},
+	}
+	keys := make(map[uint16]timerAction, len(names))
+	for _, n := range names {
+		name := strings.ToLower(n.name)
+		if name == "" {
+			name = n.fallback
+		}
+		code, ok := hotkeyCodes[name]
+		if !ok {
+			known := make([]string, 0, len(hotkeyCodes))
+			for k := range hotkeyCodes {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("timer_hotkeys: unknown key %q, use one of %s", n.name, strings.Join(known, ", "))
+		}
+		if _, taken := keys[code]; taken {
+			return nil, fmt.Errorf("timer_hotkeys: %s is used twice", name)
+		}
+		keys[code] = n.action
+	}
+	return keys, nil
+}
+
+type timerHotkeyMsg struct {
+	action timerAction
+}
+
+// hotkeyListener reads the hotkeys from the time the timer is first shown
+// until the app quits
+type hotkeyListener struct {
+	keys    map[uint16]timerAction
+	once    sync.Once
+	presses <-chan timerAction
+	err     error
+}
+
+func newHotkeyListener(cfg TimerHotkeys) *hotkeyListener {
+	if cfg.Off {
+		return nil
+	}
+	keys, err := cfg.keys()
+	return &hotkeyListener{keys: keys, err: err}
+}
+
+// start opens the keyboards the first time it's called, returning the
+// command that waits for the first press
+func (h *hotkeyListener) start() tea.Cmd {
+	if h == nil || h.err != nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	h.once.Do(func() {
+		h.presses, h.err = listenHotkeys(h.keys)
+		if h.err == nil {
+			cmd = h.wait()
+		}
+	})
+	return cmd
+}
+
+// wait delivers the next press
+func (h *hotkeyListener) wait() tea.Cmd {
+	return func() tea.Msg {
+		return timerHotkeyMsg{action: <-h.presses}
+	}
+}
+
+// status says whether the hotkeys are on, for the timer screen
+func (h *hotkeyListener) status() string {
+	switch {
+	case h == nil:
+		return ""
+	case h.err != nil:
+		return fmt.Sprintf("Global hotkeys off: %v", h.err)
+	case h.presses != nil:
+		return "Global hotkeys on"
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+//go:build linux
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	evKey      = 1 // key events
+	keyPressed = 1 // not released (0) or repeating (2)
+)
+
+// An input_event: the time as a timeval, then type, code and value
+var inputEventSize = strconv.IntSize/8*2 + 2 + 2 + 4
+
+// keyboards lists the event devices the kernel says are keyboards
+func keyboards() ([]string, error) {
+	raw, err := os.ReadFile("/proc/bus/input/devices")
+	if err != nil {
+		return nil, fmt.Errorf("listing input devices: %w", err)
+	}
+	var devices []string
+	for _, block := range strings.Split(string(raw), "\n\n") {
+		for _, line := range strings.Split(block, "\n") {
+			handlers, ok := strings.CutPrefix(line, "H: Handlers=")
+			if !ok || !strings.Contains(" "+handlers+" ", " kbd ") {
+				continue
+			}
+			for _, h := range strings.Fields(handlers) {
+				if strings.HasPrefix(h, "event") {
+					devices = append(devices, "/dev/input/"+h)
+				}
+			}
+		}
+	}
+	return devices, nil
+}
+
+// listenHotkeys reads every keyboard it may, sending the action for each
+// hotkey pressed
+func listenHotkeys(keys map[uint16]timerAction) (<-chan timerAction, error) {
+	devices, err := keyboards()
+	if err != nil {
+		return nil, err
+	}
+
+	presses := make(chan timerAction, 8)
+	opened := 0
+	var denied bool
+	for _, path := range devices {
+		f, err := os.Open(path)
+		if err != nil {
+			denied = denied || errors.Is(err, fs.ErrPermission)
+			continue
+		}
+		opened++
+		go readHotkeys(f, keys, presses)
+	}
+
+	switch {
+	case opened > 0:
+		return presses, nil
+	case denied:
+		return nil, errors.New("no access to /dev/input, add yourself to the input group")
+	}
+	return nil, errors.New("no keyboard found")
+}
+
+func readHotkeys(f *os.File, keys map[uint16]timerAction, presses chan<- timerAction) {
+	defer f.Close()
+	event := make([]byte, inputEventSize)
+	tail := inputEventSize - 8
+	for {
+		if _, err := io.ReadFull(f, event); err != nil {
+			// Unplugged keyboards end up here
+			log.Printf("hotkeys: %s: %v", f.Name(), err)
+			return
+		}
+		typ := binary.NativeEndian.Uint16(event[tail:])
+		code := binary.NativeEndian.Uint16(event[tail+2:])
+		value := int32(binary.NativeEndian.Uint32(event[tail+4:]))
+		if typ != evKey || value != keyPressed {
+			continue
+		}
+		if action, ok := keys[code]; ok {
+			// A press the app hasn't caught up with yet is dropped
+			// rather than blocking the keyboard
+			select {
+			case presses <- action:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+func listenHotkeys(keys map[uint16]timerAction) (<-chan timerAction, error) {
+	return nil, errors.New("they only work on Linux")
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Notifications are numbered from 1 in list order, folded days included,
+// so a number stays put when a day is folded. Typing a number and enter
+// jumps to it, like in mutt.
+
+var indexStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#A0A0A0"))
+
+// numberedRows is every notification row as if no day were folded, so
+// the nth one is notification n
+func (m model) numberedRows(visible []Notification) []listRow {
+	unfolded := m
+	unfolded.collapsed = nil
+	var rows []listRow
+	for _, r := range unfolded.notificationRows(visible) {
+		if !r.header {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// notificationNumbers numbers the notifications by ID
+func (m model) notificationNumbers(visible []Notification) map[string]int {
+	numbers := make(map[string]int, len(visible))
+	for i, r := range m.numberedRows(visible) {
+		numbers[r.n.ID] = i + 1
+	}
+	return numbers
+}
+
+// typeJumpDigit adds a digit to the number being typed. Once no longer
+// number could match, it jumps straight away.
+func (m model) typeJumpDigit(digit string, visible []Notification) model {
+	if m.jump == "" && digit == "0" {
+		return m
+	}
+	m.jump += digit
+	if n, _ := strconv.Atoi(m.jump); n*10 > len(visible) {
+		return m.jumpTo(visible)
+	}
+	return m
+}
+
+// jumpTo moves the cursor to the typed number, unfolding its day if needed
+func (m model) jumpTo(visible []Notification) model {
+	n, _ := strconv.Atoi(m.jump)
+	m.jump = ""
+	numbered := m.numberedRows(visible)
+	if n < 1 || n > len(numbered) {
+		m.status = fmt.Sprintf("No notification %d", n)
+		return m
+	}
+	target := numbered[n-1]
+	if m.collapsed[sectionNames[target.section]] {
+		m = m.toggleSection(target.section, visible)
+	}
+
+	rows := m.notificationRows(visible)
+	for i, r := range rows {
+		if !r.header && r.n.ID == target.n.ID {
+			m.selected = len(m.pins) + i
+		}
+	}
+	m.viewport.SetContent(m.renderContent())
+	m.viewport.SetYOffset(m.lineOf(m.selected, rows))
+	return m
+}
+
+// lineOf is the line position i of the list starts on, for scrolling it
+// into view
+func (m model) lineOf(i int, rows []listRow) int {
+	line := 0
+	if len(m.pins) > 0 {
+		line++ // the "Pinned" header
+		for j, p := range m.pins {
+			if j == i {
+				return line
+			}
+			line += lipgloss.Height(unselectedItemStyle.Render(renderPin(p)))
+		}
+		line++ // the gap after the pins
+	}
+	var numbers map[string]int
+	if m.indexColumn {
+		numbers = m.notificationNumbers(m.inbox.apply(m.notifications))
+	}
+	for j, r := range rows {
+		if len(m.pins)+j == i {
+			break
+		}
+		line += lipgloss.Height(m.renderRow(len(m.pins)+j, r, numbers))
+	}
+	return line
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var keywordStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FF79C6")).
+	Bold(true)
+
+// keywordHighlighter marks the user's keywords (their name, games they
+// care about) wherever they appear, ignoring case.
+type keywordHighlighter struct {
+	re *regexp.Regexp
+}
+
+// newKeywordHighlighter returns nil when there are no keywords, which
+// highlight treats as "leave text alone".
+func newKeywordHighlighter(keywords []string) *keywordHighlighter {
+	var quoted []string
+	for _, k := range keywords {
+		if k = strings.TrimSpace(k); k != "" {
+			quoted = append(quoted, regexp.QuoteMeta(k))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	// Longest first so "sm64 16 star" wins over "sm64"
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return &keywordHighlighter{re: regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))}
+}
+
+func (h *keywordHighlighter) highlight(text string) string {
+	if h == nil {
+		return text
+	}
+	return h.re.ReplaceAllStringFunc(text, func(match string) string {
+		return keywordStyle.Render(match)
+	})
+}
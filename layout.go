@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// notificationFields is what a notification_template can use. The strings
+// are styled the way the default layout shows them.
+type notificationFields struct {
+	Status  string // ✓ or !
+	Date    string // 2006-01-02 15:04:05
+	Title   string // with keywords highlighted
+	Path    string // speedrun.com/...
+	Type    string
+	Account string // set when there are other accounts
+	Read    bool
+	Time    time.Time // for other date formats: {{.Time.Format "Jan 2"}}
+}
+
+// parseNotificationTemplate parses a notification_template, trying it on a
+// sample so unknown fields fail at startup rather than in the list
+func parseNotificationTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("notification").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("notification_template: %w", err)
+	}
+	sample := notificationFields{Status: "!", Date: "2006-01-02 15:04:05", Title: "title", Path: "speedrun.com/", Time: time.Now()}
+	if err := tmpl.Execute(&strings.Builder{}, sample); err != nil {
+		return nil, fmt.Errorf("notification_template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (m model) notificationFields(n Notification) notificationFields {
+	status := unreadDotStyle.String()
+	if n.Read {
+		status = readDotStyle.String()
+	}
+	t := time.Unix(n.Date, 0)
+	return notificationFields{
+		Status:  status,
+		Date:    t.Format("2006-01-02 15:04:05"),
+		Title:   m.keywords.highlight(n.Title),
+		Path:    urlStyle.Render("speedrun.com" + n.Path),
+		Type:    n.Type,
+		Account: n.Account,
+		Read:    n.Read,
+		Time:    t,
+	}
+}
+
+// renderTemplated lays out a notification with the configured template
+func (m model) renderTemplated(n Notification) string {
+	var b strings.Builder
+	if err := m.layout.Execute(&b, m.notificationFields(n)); err != nil {
+		return fmt.Sprintf("notification_template: %v", err)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Run struct {
+	ID            string   `json:"id"`
+	GameID        string   `json:"gameId"`
+	CategoryID    string   `json:"categoryId"`
+	LevelID       string   `json:"levelId"`
+	Time          float64  `json:"time"` // seconds
+	TimeWithLoads float64  `json:"timeWithLoads"`
+	IGT           float64  `json:"igt"`
+	PlayerIDs     []string `json:"playerIds"`
+	ValueIDs      []string `json:"valueIds"`
+	PlatformID    string   `json:"platformId"`
+	Emulator      bool     `json:"emulator"`
+	Video         string   `json:"video"`
+	Comment       string   `json:"comment"`
+	Place         int      `json:"place"`
+	Obsolete      bool     `json:"obsolete"`
+	Verified      int      `json:"verified"`
+	Date          int64    `json:"date"`
+	DateSubmitted int64    `json:"dateSubmitted"`
+	DateVerified  int64    `json:"dateVerified"`
+}
+
+// Duration is the time the run is ranked by. Boards ranked by IGT or load
+// removed time leave the real time field empty.
+func (r Run) Duration() time.Duration {
+	secs := r.Time
+	if secs == 0 {
+		secs = r.TimeWithLoads
+	}
+	if secs == 0 {
+		secs = r.IGT
+	}
+	return time.Duration(secs * float64(time.Second)).Round(time.Millisecond)
+}
+
+type Player struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	AreaID   string   `json:"areaId"` // country, then region, e.g. "us/ca"
+	Pronouns pronouns `json:"pronouns"`
+	// 0 is banned, 3 a site moderator and 4 and up staff
+	PowerLevel  *int `json:"powerLevel"`
+	IsSupporter bool `json:"isSupporter"`
+}
+
+// Runner describes the player for lists; mods are the game's moderators
+func (p Player) Runner(mods map[string]string) Runner {
+	r := Runner{
+		Name:      p.Name,
+		Country:   strings.SplitN(p.AreaID, "/", 2)[0],
+		Pronouns:  string(p.Pronouns),
+		Supporter: p.IsSupporter,
+	}
+	switch {
+	case p.PowerLevel != nil && *p.PowerLevel == 0:
+		r.Role = roleBanned
+	case p.PowerLevel != nil && *p.PowerLevel >= 4:
+		r.Role = roleAdmin
+	case p.PowerLevel != nil && *p.PowerLevel == 3, mods[p.ID] != "":
+		r.Role = roleModerator
+	}
+	return r
+}
+
+type ValueFilter struct {
+	VariableID string   `json:"variableId"`
+	ValueIDs   []string `json:"valueIds"`
+}
+
+// LeaderboardParams selects one board: a category, an optional level and
+// the subcategory values that split it.
+type LeaderboardParams struct {
+	GameID     string        `json:"gameId"`
+	CategoryID string        `json:"categoryId"`
+	LevelID    string        `json:"levelId,omitempty"`
+	Values     []ValueFilter `json:"values"`
+	Verified   int           `json:"verified"`
+}
+
+type Leaderboard struct {
+	Runs       []Run      `json:"runList"`
+	Players    []Player   `json:"playerList"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// PlayerNames joins the names of a run's players, falling back to the ID
+// for guests and users missing from the response.
+func (lb *Leaderboard) PlayerNames(r Run) string {
+	return playerNames(lb.Players, r)
+}
+
+// Runners describes a run's players, with guests by ID alone
+func (lb *Leaderboard) Runners(r Run, mods map[string]string) []Runner {
+	runners := make([]Runner, 0, len(r.PlayerIDs))
+	for _, id := range r.PlayerIDs {
+		runner := Runner{Name: id}
+		for _, p := range lb.Players {
+			if p.ID == id {
+				runner = p.Runner(mods)
+				break
+			}
+		}
+		runners = append(runners, runner)
+	}
+	return runners
+}
+
+func playerNames(players []Player, r Run) string {
+	names := ""
+	for i, id := range r.PlayerIDs {
+		name := id
+		for _, p := range players {
+			if p.ID == id {
+				name = p.Name
+				break
+			}
+		}
+		if i > 0 {
+			names += ", "
+		}
+		names += name
+	}
+	return names
+}
+
+// GetLeaderboard fetches one page of verified runs for a board
+func (c *Client) GetLeaderboard(params LeaderboardParams, page int) (*Leaderboard, error) {
+	if params.Values == nil {
+		params.Values = []ValueFilter{}
+	}
+	params.Verified = 1
+
+	body := struct {
+		Params LeaderboardParams `json:"params"`
+		Page   int               `json:"page"`
+	}{
+		Params: params,
+		Page:   page,
+	}
+
+	var result Leaderboard
+	if err := c.post("GetGameLeaderboard2", body, &result); err != nil {
+		return nil, fmt.Errorf("fetching leaderboard: %w", err)
+	}
+
+	return &result, nil
+}
+
+// UserLeaderboard is a user's personal bests along with the games,
+// categories and variables needed to describe them.
+type UserLeaderboard struct {
+	Games      []Game          `json:"games"`
+	Categories []Category      `json:"categories"`
+	Levels     []Level         `json:"levels"`
+	Variables  []Variable      `json:"variables"`
+	Values     []VariableValue `json:"values"`
+	Runs       []Run           `json:"runs"`
+}
+
+func (c *Client) GetUserLeaderboard(userID string) (*UserLeaderboard, error) {
+	body := struct {
+		UserID string `json:"userId"`
+	}{
+		UserID: userID,
+	}
+
+	var result UserLeaderboard
+	if err := c.post("GetUserLeaderboard", body, &result); err != nil {
+		return nil, fmt.Errorf("fetching personal bests: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (ul *UserLeaderboard) game(id string) Game {
+	for _, g := range ul.Games {
+		if g.ID == id {
+			return g
+		}
+	}
+	return Game{ID: id, Name: id}
+}
+
+func (ul *UserLeaderboard) category(id string) Category {
+	for _, c := range ul.Categories {
+		if c.ID == id {
+			return c
+		}
+	}
+	return Category{ID: id, Name: id}
+}
+
+// BoardParams rebuilds the leaderboard a run is ranked on, keeping only the
+// values of subcategory variables since those are what split the board.
+func (ul *UserLeaderboard) BoardParams(r Run) LeaderboardParams {
+	params := LeaderboardParams{
+		GameID:     r.GameID,
+		CategoryID: r.CategoryID,
+		LevelID:    r.LevelID,
+	}
+
+	for _, valueID := range r.ValueIDs {
+		for _, val := range ul.Values {
+			if val.ID != valueID {
+				continue
+			}
+			for _, v := range ul.Variables {
+				if v.ID == val.VariableID && v.IsSubcategory {
+					params.Values = append(params.Values, ValueFilter{
+						VariableID: v.ID,
+						ValueIDs:   []string{val.ID},
+					})
+				}
+			}
+		}
+	}
+
+	return params
+}
+
+// BoardName describes a run's board, e.g. "Super Mario 64 - 120 Star"
+func (ul *UserLeaderboard) BoardName(r Run) string {
+	name := ul.game(r.GameID).Name + " - " + ul.category(r.CategoryID).Name
+	if r.LevelID != "" {
+		for _, l := range ul.Levels {
+			if l.ID == r.LevelID {
+				name += " (" + l.Name + ")"
+			}
+		}
+	}
+
+	for _, f := range ul.BoardParams(r).Values {
+		for _, val := range ul.Values {
+			if val.ID == f.ValueIDs[0] {
+				name += " / " + val.Name
+			}
+		}
+	}
+	return name
+}
+
+// RunURL links to a run's page on the site
+func (ul *UserLeaderboard) RunURL(r Run) string {
+	return fmt.Sprintf("https://www.speedrun.com/%s/runs/%s", ul.game(r.GameID).URL, r.ID)
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type linkKind int
+
+const (
+	linkUnsupported linkKind = iota
+	linkRun
+	linkUser
+	linkThread
+	linkGame
+)
+
+// link is a speedrun.com page the app can show itself
+type link struct {
+	Kind linkKind
+	Game string // abbreviation, when the path has one
+	ID   string // run or thread ID, or user name
+}
+
+// Top level paths that aren't games
+var sitePaths = map[string]bool{
+	"users": true, "user": true, "run": true, "forums": true, "notifications": true,
+	"settings": true, "inbox": true, "games": true, "news": true, "series": true,
+	"support": true, "knowledgebase": true, "about": true,
+}
+
+// resolveLink maps a site path such as "/sm64/runs/abc123" or a full URL
+// to the screen that shows it
+func resolveLink(raw string) link {
+	path := raw
+	if u, err := url.Parse(raw); err == nil {
+		path = u.Path
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		return link{}
+	}
+
+	switch {
+	case (parts[0] == "users" || parts[0] == "user") && len(parts) >= 2:
+		return link{Kind: linkUser, ID: parts[1]}
+	case parts[0] == "run" && len(parts) == 2:
+		return link{Kind: linkRun, ID: parts[1]}
+	case parts[0] == "forums" && len(parts) >= 3:
+		// Site forums: /forums/<forum>/<thread>
+		return link{Kind: linkThread, ID: parts[2]}
+	case sitePaths[parts[0]]:
+		return link{}
+	}
+
+	game := parts[0]
+	switch {
+	case len(parts) == 1, len(parts) >= 2 && parts[1] == "leaderboards":
+		return link{Kind: linkGame, Game: game}
+	case len(parts) >= 3 && parts[1] == "runs":
+		return link{Kind: linkRun, Game: game, ID: parts[2]}
+	case len(parts) >= 3 && (parts[1] == "forums" || parts[1] == "thread"):
+		return link{Kind: linkThread, Game: game, ID: parts[len(parts)-1]}
+	}
+	return link{}
+}
+
+type detailMsg struct {
+	title string
+	body  string
+	video string
+	// What the page offers to report, e.g. the run or each comment
+	reports []reportTarget
+	err     error
+}
+
+// detailModel shows a run, profile or forum thread in the app instead of
+// the browser
+type detailModel struct {
+	link    link
+	url     string
+	title   string
+	body    string
+	video   string
+	reports []reportTarget
+	loading bool
+	err     error
+
+	// The discussion under a run
+	comments *commentThread
+}
+
+func newDetailModel(l link, pageURL string) *detailModel {
+	d := &detailModel{link: l, url: pageURL, loading: true}
+	if l.Kind == linkRun {
+		d.comments = newCommentThread(l.ID)
+	}
+	return d
+}
+
+func (d *detailModel) loadCmd(client *Client, games *GameCache, times TimeFormat, flags string, goals []Goal) tea.Cmd {
+	l, pageURL := d.link, d.url
+	load := func() tea.Msg {
+		switch l.Kind {
+		case linkRun:
+			return loadRunDetail(client, games, l, times, flags)
+		case linkUser:
+			return loadUserDetail(client, l, times, flags, goals)
+		case linkThread:
+			return loadThreadDetail(client, l, pageURL)
+		}
+		return detailMsg{err: fmt.Errorf("nothing to show for this link")}
+	}
+	if d.comments != nil {
+		return tea.Batch(load, d.comments.loadCmd(client, 1))
+	}
+	return load
+}
+
+// capturing reports whether the reply box has the keys
+func (d *detailModel) capturing() bool {
+	return d.comments != nil && d.comments.replying
+}
+
+// reportTargets is what ! offers: the page's own targets and the comments
+// on screen
+func (d *detailModel) reportTargets() []reportTarget {
+	targets := slices.Clip(d.reports)
+	if d.comments != nil && d.comments.page != nil {
+		for _, c := range d.comments.page.Comments {
+			targets = append(targets, commentReportTarget(c.ID, commentAuthor(d.comments.page.Users, c), c.Text))
+		}
+	}
+	return targets
+}
+
+func (d *detailModel) update(msg detailMsg) {
+	d.loading = false
+	d.title, d.body, d.video, d.reports, d.err = msg.title, msg.body, msg.video, msg.reports, msg.err
+}
+
+func (d *detailModel) view() string {
+	switch {
+	case d.loading:
+		return "Loading..."
+	case d.err != nil:
+		return fmt.Sprintf("Error: %v\n\n%s", d.err, urlStyle.Render("o opens it in the browser instead"))
+	}
+	if d.comments != nil {
+		return d.body + "\n" + d.comments.view()
+	}
+	return d.body
+}
+
+func (d *detailModel) help() string {
+	if d.capturing() {
+		return d.comments.help()
+	}
+	hints := "o open in browser • esc back"
+	if d.comments != nil {
+		hints = d.comments.help() + " • " + hints
+	}
+	if d.link.Kind == linkUser {
+		hints = "f follow/unfollow • " + hints
+	}
+	if d.link.Kind == linkThread {
+		hints = "s subscribe/unsubscribe • " + hints
+	}
+	if len(d.reportTargets()) > 0 {
+		hints = "! report • " + hints
+	}
+	if d.video != "" {
+		hints = "v play video • " + hints
+	}
+	return hints
+}
+
+// getRun fetches a single run from the v1 API
+func (c *Client) getRun(id string) (v1Run, error) {
+	var result struct {
+		Data v1Run `json:"data"`
+	}
+	if err := c.getV1("/runs/"+url.PathEscape(id)+"?embed=players", &result); err != nil {
+		return v1Run{}, fmt.Errorf("fetching run: %w", err)
+	}
+	return result.Data, nil
+}
+
+func loadRunDetail(client *Client, games *GameCache, l link, times TimeFormat, flags string) detailMsg {
+	raw, err := client.getRun(l.ID)
+	if err != nil {
+		return detailMsg{err: err}
+	}
+	run := raw.queueRun()
+	if err := archiveTexts([]textEntry{runCommentEntry(run)}); err != nil {
+		log.Printf("texts: %v", err)
+	}
+
+	// Names come from the game's cached metadata when the link says which
+	// game it is
+	gameName, category, millis := run.GameID, run.CategoryID, false
+	if games != nil && l.Game != "" {
+		if data, err := games.Get(l.Game); err == nil {
+			gameName, millis = data.Game.Name, data.Game.Milliseconds
+			for _, c := range data.Categories {
+				if c.ID == run.CategoryID {
+					category = c.Name
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s by %s\n\n", times.render(run.Time, millis), renderRunners(run.Runners, flags, 0)))
+	status := raw.Status.Status
+	if raw.Status.Reason != "" {
+		status += ": " + raw.Status.Reason
+	}
+	b.WriteString(fmt.Sprintf("Status     %s\n", status))
+	if !run.Submitted.IsZero() {
+		b.WriteString(fmt.Sprintf("Submitted  %s\n", run.Submitted.Local().Format("2006-01-02 15:04")))
+	}
+	if verified, err := time.Parse(time.RFC3339, raw.Status.VerifyDate); err == nil {
+		b.WriteString(fmt.Sprintf("Verified   %s\n", verified.Local().Format("2006-01-02 15:04")))
+	}
+	if run.Video != "" {
+		b.WriteString(fmt.Sprintf("Video      %s\n", run.Video))
+	}
+	if run.Comment != "" {
+		b.WriteString("\n" + run.Comment + "\n")
+	}
+	if id := splitsIOID(raw.splitsLink(), run.Comment); id != "" {
+		b.WriteString(runSplits(client, raw, id))
+	}
+	return detailMsg{
+		title:   gameName + " › " + category,
+		body:    b.String(),
+		video:   run.Video,
+		reports: []reportTarget{runReportTarget(raw.ID, strings.Join(run.Players, ", "))},
+	}
+}
+
+type v1User struct {
+	ID    string `json:"id"`
+	Names struct {
+		International string `json:"international"`
+	} `json:"names"`
+	Pronouns  string       `json:"pronouns"`
+	Role      string       `json:"role"`
+	Signup    string       `json:"signup"`
+	Weblink   string       `json:"weblink"`
+	NameStyle *v1NameStyle `json:"name-style"`
+	Animated  bool         `json:"supporterAnimation"` // a supporter's animated name
+	Location  *struct {
+		Country struct {
+			Code  string `json:"code"`
+			Names struct {
+				International string `json:"international"`
+			} `json:"names"`
+		} `json:"country"`
+	} `json:"location"`
+}
+
+func (c *Client) getUser(name string) (v1User, error) {
+	var result struct {
+		Data v1User `json:"data"`
+	}
+	if err := c.getV1("/users/"+url.PathEscape(name), &result); err != nil {
+		return v1User{}, fmt.Errorf("fetching user: %w", err)
+	}
+	return result.Data, nil
+}
+
+func (u v1User) runner() Runner {
+	r := Runner{
+		Name:     u.Names.International,
+		Pronouns: u.Pronouns,
+		Role:     v1Role(u.Role),
+		Colors:   u.NameStyle.colors(),
+	}
+	r.Supporter = r.Colors.gradient() || u.Animated
+	if u.Location != nil {
+		r.Country = strings.SplitN(u.Location.Country.Code, "/", 2)[0]
+	}
+	return r
+}
+
+// profilePBs caps how many personal bests the profile lists
+const profilePBs = 20
+
+func loadUserDetail(client *Client, l link, times TimeFormat, flags string, goals []Goal) detailMsg {
+	user, err := client.getUser(l.ID)
+	if err != nil {
+		return detailMsg{err: err}
+	}
+	runner := user.runner()
+
+	var b strings.Builder
+	b.WriteString(runner.render(flags) + "\n")
+	if runner.Supporter {
+		b.WriteString(supporterStyle.Render(supporterMark+" Supporter") + "\n")
+	}
+	if user.Location != nil {
+		b.WriteString(urlStyle.Render(user.Location.Country.Names.International) + "\n")
+	}
+	if signup, err := time.Parse(time.RFC3339, user.Signup); err == nil {
+		b.WriteString(urlStyle.Render("Joined "+signup.Local().Format("January 2006")) + "\n")
+	}
+
+	pbs, err := client.GetUserLeaderboard(user.ID)
+	if err != nil {
+		return detailMsg{title: runner.Name, body: b.String(), reports: []reportTarget{userReportTarget(user.ID, runner.Name)}, err: err}
+	}
+	b.WriteString("\n" + titleStyle.Render("Personal bests") + "\n")
+	for i, r := range pbs.Runs {
+		if i == profilePBs {
+			b.WriteString(urlStyle.Render(fmt.Sprintf("and %d more", len(pbs.Runs)-profilePBs)) + "\n")
+			break
+		}
+		game, category := pbs.game(r.GameID), pbs.category(r.CategoryID).Name
+		line := fmt.Sprintf("%4d  %s › %s  %s", r.Place, game.Name, category, times.render(r.Duration(), game.Milliseconds))
+		// WR pace goals would need every board's record, so only set
+		// times show here
+		if goal, ok := findGoal(goals, game.URL, category); ok && r.LevelID == "" {
+			if target, ok := goal.fixed(); ok {
+				line += "  " + urlStyle.Render(goal.label()+" ") + renderDelta(r.Duration()-target)
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(pbs.Runs) == 0 {
+		b.WriteString(urlStyle.Render("No runs yet") + "\n")
+	}
+	return detailMsg{title: runner.Name, body: b.String(), reports: []reportTarget{userReportTarget(user.ID, runner.Name)}}
+}
+
+// Thread is a forum thread with its first page of comments
+type Thread struct {
+	Thread struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"thread"`
+	Comments []Comment `json:"commentList"`
+	Users    []Player  `json:"userList"`
+}
+
+func (c *Client) GetThread(id string) (*Thread, error) {
+	body := struct {
+		ID string `json:"id"`
+	}{
+		ID: id,
+	}
+
+	var result Thread
+	if err := c.post("GetThread", body, &result); err != nil {
+		return nil, fmt.Errorf("fetching thread: %w", err)
+	}
+	return &result, nil
+}
+
+func loadThreadDetail(client *Client, l link, pageURL string) detailMsg {
+	thread, err := client.GetThread(l.ID)
+	if err != nil {
+		return detailMsg{err: err}
+	}
+
+	var (
+		b       strings.Builder
+		reports []reportTarget
+		texts   []textEntry
+	)
+	for _, c := range thread.Comments {
+		author := commentAuthor(thread.Users, c)
+		writeComment(&b, author, c)
+		reports = append(reports, commentReportTarget(c.ID, author, c.Text))
+		texts = append(texts, textEntry{
+			Kind:  "comment",
+			ID:    c.ID,
+			Title: author + " in " + thread.Thread.Name,
+			Text:  c.Text,
+			URL:   pageURL,
+			Time:  time.Unix(c.Date, 0),
+		})
+	}
+	// Kept for the search tab
+	if err := archiveTexts(texts); err != nil {
+		log.Printf("texts: %v", err)
+	}
+	if len(thread.Comments) == 0 {
+		b.WriteString(urlStyle.Render("No comments"))
+	}
+	return detailMsg{title: thread.Thread.Name, body: b.String(), reports: reports}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultLiveSplitAddr = "localhost:16834"
+
+// LiveSplitClient talks to LiveSplit's built in TCP server (Control >
+// Start TCP Server), which accepts one text command per line.
+type LiveSplitClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func DialLiveSplit(addr string) (*LiveSplitClient, error) {
+	conn, err := net.DialTimeout("tcp", addr, 3*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to LiveSplit at %s (is the server started?): %w", addr, err)
+	}
+	return &LiveSplitClient{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+func (l *LiveSplitClient) Close() error {
+	return l.conn.Close()
+}
+
+// query sends a get* command and returns its single line response
+func (l *LiveSplitClient) query(cmd string) (string, error) {
+	l.conn.SetDeadline(time.Now().Add(3 * time.Second))
+	if _, err := fmt.Fprintf(l.conn, "%s\r\n", cmd); err != nil {
+		return "", fmt.Errorf("sending %s: %w", cmd, err)
+	}
+	line, err := l.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("reading %s response: %w", cmd, err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Phase is one of NotRunning, Running, Paused or Ended
+func (l *LiveSplitClient) Phase() (string, error) {
+	return l.query("getcurrenttimerphase")
+}
+
+func (l *LiveSplitClient) SplitIndex() (int, error) {
+	resp, err := l.query("getsplitindex")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(resp)
+}
+
+func (l *LiveSplitClient) CurrentTime() (time.Duration, error) {
+	resp, err := l.query("getcurrenttime")
+	if err != nil {
+		return 0, err
+	}
+	return parseRunTime(resp)
+}
+
+// LiveSplitSegment is one split as observed while the run was in progress
+type LiveSplitSegment struct {
+	Name  string
+	Split time.Duration // cumulative time at the split
+}
+
+func (s LiveSplitSegment) Duration(prev time.Duration) time.Duration {
+	return s.Split - prev
+}
+
+type LiveSplitRun struct {
+	Final    time.Duration
+	Segments []LiveSplitSegment
+}
+
+// WatchRun polls the timer until the run ends, recording each split as it
+// happens. LiveSplit's server has no command to list completed splits, so
+// segments are only captured for splits made while connected.
+func (l *LiveSplitClient) WatchRun(interval time.Duration) (*LiveSplitRun, error) {
+	run := &LiveSplitRun{}
+	last := -1
+
+	for {
+		phase, err := l.Phase()
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := l.SplitIndex()
+		if err != nil {
+			return nil, err
+		}
+
+		// Undo or reset moves the index back, so drop what we recorded
+		if phase == "NotRunning" || index < last {
+			run.Segments = nil
+			last = -1
+		}
+
+		if last >= 0 && index > last {
+			name, err := l.query("getprevioussplitname")
+			if err != nil {
+				return nil, err
+			}
+			split, err := l.query("getlastsplittime")
+			if err != nil {
+				return nil, err
+			}
+			if d, err := parseRunTime(split); err == nil {
+				run.Segments = append(run.Segments, LiveSplitSegment{Name: name, Split: d})
+			}
+		}
+		if phase == "Running" || phase == "Paused" || phase == "Ended" {
+			last = index
+		}
+
+		if phase == "Ended" {
+			run.Final, err = l.CurrentTime()
+			if err != nil {
+				return nil, err
+			}
+			return run, nil
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+func (r *LiveSplitRun) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Final time: %s\n", formatRunTime(r.Final))
+
+	if len(r.Segments) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\nSegments:\n")
+	var prev time.Duration
+	for i, seg := range r.Segments {
+		fmt.Fprintf(&b, "%3d  %-30s %12s %12s\n", i+1, seg.Name,
+			formatRunTime(seg.Duration(prev)), formatRunTime(seg.Split))
+		prev = seg.Split
+	}
+	return b.String()
+}
+
+// comment lists the splits for a submission's comment, empty when none
+// were seen
+func (r *LiveSplitRun) comment() string {
+	lines := make([]string, len(r.Segments))
+	for i, seg := range r.Segments {
+		lines[i] = fmt.Sprintf("%s %s", seg.Name, formatRunTime(seg.Split))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runLiveSplit waits for the current LiveSplit run to finish and prints its
+// final time and splits, ready to paste into a submission. With -obs it
+// waits for OBS's recording to stop instead.
+func runLiveSplit(args []string, cfg Config) error {
+	fs := flag.NewFlagSet("livesplit", flag.ExitOnError)
+	addr := fs.String("addr", defaultLiveSplitAddr, "LiveSplit server address")
+	fromOBS := fs.Bool("obs", false, "time the run by OBS's recording, as set in the config's obs")
+	fs.Parse(args)
+
+	if *fromOBS {
+		obs, err := DialOBS(cfg.OBS)
+		if err != nil {
+			return err
+		}
+		defer obs.Close()
+		fmt.Printf("Connected to OBS at %s, waiting for the recording to stop...\n", cfg.OBS.addr())
+		run, err := obs.WatchRecording(cfg.OBS.TimerSource, 250*time.Millisecond)
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Print(run)
+		return nil
+	}
+
+	ls, err := DialLiveSplit(*addr)
+	if err != nil {
+		return err
+	}
+	defer ls.Close()
+
+	fmt.Printf("Connected to LiveSplit at %s, waiting for the run to finish...\n", *addr)
+	run, err := ls.WatchRun(250 * time.Millisecond)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Print(run)
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// The daemon's log is rotated once it reaches daemonLogSize, keeping
+// daemonLogKeep old files as daemon.log.1, daemon.log.2, ...
+const (
+	daemonLogSize = 1 << 20
+	daemonLogKeep = 3
+)
+
+func logDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating cache dir: %w", err)
+	}
+	dir := filepath.Join(base, appName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating log dir: %w", err)
+	}
+	return dir, nil
+}
+
+// setupLogging sends the standard logger to a file in the cache dir, since
+// anything written to stderr would corrupt the alt screen. Returns the path.
+func setupLogging() (string, error) {
+	log.SetOutput(io.Discard)
+
+	dir, err := logDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "speedrunner.log")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("opening log file: %w", err)
+	}
+
+	log.SetOutput(f)
+	log.SetFlags(log.LstdFlags)
+	return path, nil
+}
+
+// setupDaemonLogging sends the standard logger to daemon.log, rotated so
+// a daemon left running for months doesn't fill the disk
+func setupDaemonLogging() (string, error) {
+	dir, err := logDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "daemon.log")
+	w := &rotatingFile{path: path, max: daemonLogSize, keep: daemonLogKeep}
+	if err := w.open(); err != nil {
+		return "", err
+	}
+	log.SetOutput(w)
+	log.SetFlags(log.LstdFlags)
+	return path, nil
+}
+
+// rotatingFile appends to path, moving it aside once it would grow past
+// max bytes
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	max  int64
+	keep int
+	f    *os.File
+	size int64
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.max {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts path.N to path.N+1, dropping the oldest, and starts a
+// new file
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	for i := r.keep - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("rotating log file: %w", err)
+	}
+	return r.open()
+}
+
+var loggedOnce sync.Map
+
+// logOnce logs a message the first time key is seen, so a schema change
+// doesn't write the same warning for every item on every refresh.
+func logOnce(key, format string, args ...any) {
+	if _, seen := loggedOnce.LoadOrStore(key, struct{}{}); seen {
+		return
+	}
+	log.Printf(format, args...)
+}
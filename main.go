@@ -6,11 +6,16 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -18,7 +23,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-const baseURL = "https://www.speedrun.com/api/v2"
+const defaultBaseURL = "https://www.speedrun.com/api/v2"
 
 var (
 	// Base app style
@@ -63,6 +68,29 @@ var (
 			SetString("!").
 			Foreground(lipgloss.Color("#FFD700")) // Matching gold
 
+	unknownDotStyle = lipgloss.NewStyle().
+			SetString("?").
+			Foreground(lipgloss.Color("#A0A0A0")) // Neutral gray
+
+	// Screen tabs
+	activeTabStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
+			Bold(true).
+			Underline(true).
+			MarginLeft(2)
+
+	inactiveTabStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#666666")).
+				MarginLeft(2)
+
+	// PB alert banner
+	alertBannerStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FF5555")). // Soft red
+				Border(lipgloss.NormalBorder()).
+				BorderLeft(true).
+				BorderLeftForeground(lipgloss.Color("#FF5555")).
+				Padding(0, 1)
+
 	// URL style
 	urlStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#5F89F4")). // Subtle blue
@@ -75,6 +103,11 @@ var (
 			BorderTop(true).
 			BorderTopForeground(lipgloss.Color("#333333")).
 			Padding(0, 1)
+
+	// Shown while -dry-run keeps writes from being sent
+	dryRunStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500")).
+			Bold(true)
 )
 
 // API types
@@ -84,6 +117,14 @@ type Notification struct {
 	Path  string `json:"path"`
 	Read  bool   `json:"read"`
 	Date  int64  `json:"date"`
+	Type  string `json:"type,omitempty"`
+
+	// Which of the configured accounts it came from, set by the app when
+	// there are several
+	Account string `json:"account,omitempty"`
+
+	// Fields the API sent that we don't know about yet
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
 }
 
 type Pagination struct {
@@ -100,74 +141,297 @@ type NotificationResponse struct {
 }
 
 type RequestBody struct {
-	U int `json:"u"`
-	I int `json:"i"`
+	U     int `json:"u"`
+	I     int `json:"i"`
+	Page  int `json:"page"`
+	Limit int `json:"limit,omitempty"` // the site's default when zero
 }
 
 // Client for API calls
 type Client struct {
 	httpClient *http.Client
+	baseURL    string
 	sessionID  string
+
+	// CSRF token for write endpoints, fetched lazily (see session.go)
+	csrfMu    sync.Mutex
+	csrfToken string
+
+	// Latency, rate budget and failures, for the status bar
+	health apiHealth
+
+	// With -dry-run, writes are logged and counted but never sent
+	dryRun  bool
+	skipped atomic.Int64
+
+	// user_agent from the config
+	userAgent string
+
+	// Around every request, outermost first (see middleware.go)
+	middleware []Middleware
 }
 
-func NewClient(sessionID string) *Client {
-	return &Client{
+// NewClient creates an API client. An empty baseURL means the public site.
+func NewClient(baseURL, sessionID string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
 		sessionID: sessionID,
+		userAgent: defaultUserAgent(),
+	}
+	c.Use(recordHealth(&c.health))
+	return c
+}
+
+// newConfiguredClient is NewClient with the config's API base and request
+// headers
+func newConfiguredClient(cfg Config, sessionID string) *Client {
+	c := NewClient(cfg.APIBase, sessionID)
+	if cfg.UserAgent != "" {
+		c.userAgent = cfg.UserAgent
+	}
+	if len(cfg.Headers) > 0 {
+		c.Use(withHeaders(cfg.Headers))
 	}
+	if cfg.APILog {
+		c.Use(logRequests)
+	}
+	return c
+}
+
+// defaultUserAgent says what's asking, so the site can tell the app apart
+// from a browser
+func defaultUserAgent() string {
+	return appName + "/" + version + " (+https://github.com/marcusziade/speedrunner-tui)"
 }
 
-func (c *Client) GetNotifications() (*NotificationResponse, error) {
+func (c *Client) GetNotifications(page, perPage int) (*NotificationResponse, error) {
 	body := RequestBody{
-		U: 1,
-		I: 1,
+		U:     1,
+		I:     1,
+		Page:  page,
+		Limit: perPage,
+	}
+
+	var result NotificationResponse
+	if err := c.post("GetNotifications", body, &result); err != nil {
+		return nil, err
 	}
+
+	return &result, nil
+}
+
+// GetNotificationPages loads up to maxPages pages of notifications into one
+// response. Pagination says how far it got.
+func (c *Client) GetNotificationPages(perPage, maxPages int) (*NotificationResponse, error) {
+	result, err := c.GetNotifications(1, perPage)
+	if err != nil {
+		return nil, err
+	}
+	for page := 2; page <= min(result.Pagination.Pages, maxPages); page++ {
+		next, err := c.GetNotifications(page, perPage)
+		if err != nil {
+			// The pages that did load are still worth showing
+			log.Printf("notifications: page %d: %v", page, err)
+			break
+		}
+		result.Notifications = append(result.Notifications, next.Notifications...)
+		result.Pagination.Page = page
+	}
+	return result, nil
+}
+
+// APIError is returned for any non-200 response
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// post sends a JSON body to a v2 endpoint and decodes the JSON response into out
+func (c *Client) post(endpoint string, body any, out any) error {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling request body: %w", err)
+		return fmt.Errorf("marshaling request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", baseURL+"/GetNotifications", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequest("POST", c.baseURL+"/"+endpoint, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("creating request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, out)
+}
 
+// getV1 fetches a path from the documented v1 REST API, which still has a
+// few things v2 doesn't expose, like profile links.
+func (c *Client) getV1(path string, out any) error {
+	req, err := http.NewRequest("GET", c.v1URL()+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	return c.do(req, out)
+}
+
+// v1URL derives the v1 base from the configured v2 base so -api-base
+// overrides apply to both.
+func (c *Client) v1URL() string {
+	if base, ok := strings.CutSuffix(c.baseURL, "/v2"); ok {
+		return base + "/v1"
+	}
+	return c.baseURL
+}
+
+func (c *Client) do(req *http.Request, out any) error {
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Origin", "https://www.speedrun.com")
 	req.Header.Set("Referer", "https://www.speedrun.com/notifications")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", c.userAgent)
 
-	req.AddCookie(&http.Cookie{
-		Name:  "PHPSESSID",
-		Value: c.sessionID,
-	})
+	if c.sessionID != "" {
+		req.AddCookie(&http.Cookie{
+			Name:  "PHPSESSID",
+			Value: c.sessionID,
+		})
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("sending request: %w", err)
+		return fmt.Errorf("sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var result NotificationResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	// Writes often have nothing useful to say back
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
 	}
 
-	return &result, nil
+	return nil
+}
+
+type screen int
+
+const (
+	screenNotifications screen = iota
+	screenWeek
+	screenRecords
+	screenRunners
+	screenBoards
+	screenQueue
+	screenSubmissions
+	screenStats
+	screenSearch
+	screenActivity
+	screenRaces
+	screenThreads
+	screenPlugins
+	screenTimer
+	screenCount
+)
+
+var screenNames = [...]string{
+	screenNotifications: "Notifications",
+	screenWeek:          "Week",
+	screenRecords:       "Records",
+	screenRunners:       "Runners",
+	screenBoards:        "Boards",
+	screenQueue:         "Queue",
+	screenSubmissions:   "Submissions",
+	screenStats:         "Stats",
+	screenSearch:        "Search",
+	screenActivity:      "Activity",
+	screenRaces:         "Races",
+	screenThreads:       "Threads",
+	screenPlugins:       "Plugins",
+	screenTimer:         "Timer",
 }
 
 // Model for the TUI
 type model struct {
+	client        *Client
+	accounts      map[string]*Client // by badge name, when there are other accounts
+	alerts        *alertRouter
+	ruleWork      ruleWork
+	screen        screen
+	timer         timerModel
+	summary       summaryModel
+	records       recordsModel
+	following     followModel
+	stats         statsModel
+	boards        boardsModel
+	queue         queueModel
+	submissions   submissionsModel
+	search        searchModel
+	activity      activityModel
+	threads       threadsModel
+	plugins       []plugin
+	pluginTab     pluginsModel
+	races         racesModel
+	pbAlerts      []PBAlert
+	requestAlerts []GameRequestAlert
+	status        string
+	videoPlayer   []string
+	chat          *chatPane // Twitch chat beside the board
+	autoChat      bool      // twitch.chat: open chat with every stream
+	times         TimeFormat
+	flags         string
+	gameThemes    bool
+	autoMarkRead  bool         // opening a notification marks it read
+	popup         bool         // the compact -popup layout
+	detail        *detailModel // a run, profile or thread opened from a link
+	report        *reportDialog
+	confirm       *confirmDialog
+	skipConfirm   bool
+	undo          []undoEntry        // newest last
+	layout        *template.Template // notification_template, if set
+	daemon        *daemonStatus      // nil when no daemon is running
+	checkUpdates  bool
+	newVersion    string     // a newer release, once the check finds one
+	retries       retryQueue // failed writes waiting to go through
+	rules         *ruleSet
+	perPage       int           // notifications_per_page, for reloads
+	pages         int           // notification_pages, for reloads
+	pollEvery     time.Duration // how often the inbox reloads
+	refreshed     time.Time     // when the inbox last loaded
+	refreshTried  time.Time     // when the last reload finished, even if it failed
+	refreshErr    error         // of the last reload, if it failed
+	backoff       time.Duration // between reloads while they keep failing
+	refreshing    bool
+	unfocused     bool              // the terminal said it lost focus
+	cancelled     bool              // quit with ctrl+c rather than q
+	qr            *qrOverlay        // a link shown as a QR code, until a key
+	help          *helpOverlay      // the screen's keys, until a key
+	known         map[string]string // the account of every notification loaded, muted ones too
 	notifications []Notification
+	highlighted   map[string]bool // notification IDs, by rules
+	keywords      *keywordHighlighter
+	inbox         inboxFilter
+	pins          []Pin
+	collapsed     map[string]bool // day sections, by name
+	marked        map[string]bool // notification IDs, to open together
+	indexColumn   bool            // number the notifications
+	jump          string          // digits typed so far to jump to a notification
+	bindings      bindings
+	replaying     bool // running a binding's keys
+	muted         int
 	viewport      viewport.Model
 	selected      int
 	unreadCount   int
@@ -177,11 +441,45 @@ type model struct {
 	height        int
 }
 
-func initialModel(client *Client) model {
-	result, err := client.GetNotifications()
+func initialModel(client *Client, alerts *alertRouter, rules *ruleSet, cfg Config) model {
+	result, err := client.GetNotificationPages(cfg.NotificationsPerPage, cfg.NotificationPages)
 	if err != nil {
 		return model{err: err}
 	}
+	if err := archiveNotifications(result.Notifications); err != nil {
+		log.Printf("history: %v", err)
+	}
+	layout, err := parseNotificationTemplate(cfg.NotificationTemplate)
+	if err != nil {
+		return model{err: err}
+	}
+	keys, err := parseBindings(cfg.Bindings, len(cfg.RejectionTemplates))
+	if err != nil {
+		return model{err: err}
+	}
+	var (
+		accounts map[string]*Client
+		status   string
+	)
+	if len(cfg.Accounts) > 0 {
+		var failed []string
+		accounts, failed = combinedInbox(client, result, cfg)
+		if len(failed) > 0 {
+			status = fmt.Sprintf("Couldn't load notifications for %s", strings.Join(failed, ", "))
+		}
+	}
+	notifications, highlighted, muted, work := applyRules(rules, result.Notifications)
+	pins, err := loadPins()
+	if err != nil {
+		log.Printf("pins: %v", err)
+	}
+	unread := result.UnreadCount - len(work.markRead)
+	known := make(map[string]string, len(result.Notifications))
+	for _, n := range result.Notifications {
+		known[n.ID] = n.Account
+	}
+	// Already checked by cfg.validate
+	pollEvery, _ := cfg.pollInterval()
 
 	v := viewport.New(78, 20)
 	v.Style = lipgloss.NewStyle().
@@ -189,115 +487,1446 @@ func initialModel(client *Client) model {
 		BorderForeground(lipgloss.Color("#3B82F6"))
 
 	return model{
-		notifications: result.Notifications,
+		client:        client,
+		accounts:      accounts,
+		status:        status,
+		alerts:        alerts,
+		ruleWork:      work,
+		notifications: notifications,
+		highlighted:   highlighted,
+		keywords:      newKeywordHighlighter(cfg.Keywords),
+		inbox:         newInboxFilter(cfg.Views),
+		pins:          pins,
+		collapsed:     loadCollapsed(),
+		muted:         muted,
 		viewport:      v,
-		unreadCount:   result.UnreadCount,
+		unreadCount:   max(unread, 0),
 		pagination:    result.Pagination,
 		selected:      0,
+		timer:         newTimerModel(cfg.timerName(), newTimerOptions(cfg, client)),
+		summary:       newSummaryModel(client, cfg),
+		records:       newRecordsModel(client, cfg),
+		following:     newFollowModel(client, cfg),
+		stats:         newStatsModel(client, cfg),
+		boards:        newBoardsModel(client, cfg),
+		queue:         newQueueModel(client, cfg, rules.scripts),
+		submissions:   newSubmissionsModel(client, cfg),
+		search:        newSearchModel(client),
+		races:         newRacesModel(cfg.FollowedGames),
+		threads:       newThreadsModel(client),
+		videoPlayer:   cfg.VideoPlayer,
+		autoChat:      cfg.Twitch.Chat,
+		times:         cfg.TimeFormat,
+		flags:         cfg.CountryFlags,
+		gameThemes:    cfg.GameThemes,
+		autoMarkRead:  cfg.AutoMarkRead,
+		indexColumn:   cfg.IndexColumn,
+		skipConfirm:   cfg.SkipConfirmations,
+		checkUpdates:  !cfg.SkipUpdateCheck && version != "dev",
+		layout:        layout,
+		bindings:      keys,
+		retries:       loadRetryQueue(),
+		rules:         rules,
+		perPage:       cfg.NotificationsPerPage,
+		pages:         cfg.NotificationPages,
+		pollEvery:     pollEvery,
+		refreshed:     time.Now(),
+		refreshTried:  time.Now(),
+		known:         known,
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	if m.client == nil {
+		return nil
+	}
+	cmds := append([]tea.Cmd{checkPBsCmd(m.client), checkGameRequestsCmd(m.client), daemonStatusCmd(0), loadPluginsCmd(), refreshTickCmd()}, m.ruleWorkCmds()...)
+	// Whatever was still waiting when the app last quit
+	if len(m.retries.actions) > 0 {
+		cmds = append(cmds, retryTickCmd(0))
+	}
+	if m.checkUpdates {
+		cmds = append(cmds, updateCheckCmd())
+	}
+	if m.following.loading {
+		cmds = append(cmds, m.following.loadCmd())
+	}
+	if m.threads.loading {
+		cmds = append(cmds, m.threads.loadCmd())
+	}
+	// Started with submit -video
+	if s := m.submissions; s.wizard != nil {
+		if s.loading {
+			cmds = append(cmds, s.loadGameCmd(s.wizard.draft.Game))
+		}
+		cmds = append(cmds, loadPendingCmd(s.client, s.games))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m, cmd, handled := m.handleRetry(msg); handled {
+		return m, cmd
+	}
+
+	// Background work reports back here whichever screen is showing
 	switch msg := msg.(type) {
+	case timerTickMsg:
+		m.timer, cmd = m.timer.update(msg)
+		return m, cmd
+
+	case timerGoalMsg:
+		m.timer, cmd = m.timer.update(msg)
+		return m, cmd
+
+	case timerHotkeyMsg:
+		// Hotkeys only work the timer while it's on screen or running
+		if m.screen != screenTimer && !m.timer.running {
+			return m, m.timer.opts.hotkeys.wait()
+		}
+		m.timer, cmd = m.timer.update(msg)
+		return m, cmd
+
+	case auditMsg:
+		m.activity, cmd = m.activity.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case pluginsMsg:
+		m.plugins = msg.plugins
+		m.pluginTab = m.pluginTab.setPlugins(msg.plugins)
+		if len(msg.failed) > 0 {
+			m.status = fmt.Sprintf("Plugins that didn't load: %s (see the log)", strings.Join(msg.failed, ", "))
+		}
+		m.viewport.SetContent(m.renderContent())
+		return m, nil
+
+	case pluginScreenMsg:
+		m.pluginTab, cmd = m.pluginTab.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case pluginResultMsg:
+		m, cmd = m.pluginResult(msg)
+		return m, cmd
+
+	case searchIndexMsg, siteSearchTickMsg, siteSearchMsg:
+		m.search, cmd = m.search.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case racesMsg, racesTickMsg, raceMsg:
+		m.races, cmd = m.races.update(msg, m.screen == screenRaces)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case threadsMsg, threadSubscribedMsg:
+		m.threads, cmd = m.threads.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case chatMsg:
+		if msg.pane != m.chat {
+			return m, nil
+		}
+		return m, m.chat.add(msg)
+
+	case gameDataMsg, boardMsg, liveRunnersMsg, gameInfoMsg, boardPagesMsg, myRankMsg:
+		m.boards, cmd = m.boards.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m.followBoardJump(), cmd
+
+	case runnerFeedMsg:
+		m.following, cmd = m.following.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		alerts := make([]Alert, len(msg.fresh))
+		for i, e := range msg.fresh {
+			alerts[i] = e.Alert()
+		}
+		return m, tea.Batch(cmd, sendAlertsCmd(m.alerts, alerts), scheduleFollowCheck())
+
+	case followCheckMsg:
+		m.following, cmd = m.following.check()
+		return m, cmd
+
+	case recordsMsg:
+		m.records, cmd = m.records.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case personalStatsMsg:
+		m.stats, cmd = m.stats.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case summaryMsg:
+		m.summary, cmd = m.summary.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case submitGameMsg, submitSuggestionsMsg, submitResultMsg, videoCheckMsg, liveSplitRunMsg, pendingMsg, pendingEditMsg, withdrawRunMsg, deleteDraftMsg:
+		m.submissions, cmd = m.submissions.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case verifyResultMsg:
+		// A write that may go through later is queued, and the queue tab
+		// keeps the run out of the list meanwhile
+		var queueCmd tea.Cmd
+		if retryable(msg.err) {
+			queueCmd = m.retries.add(verifyAction(msg.run), msg.err)
+			msg.queued = true
+		}
+		m.queue, cmd = m.queue.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, tea.Batch(cmd, queueCmd)
+
+	case rejectResultMsg:
+		var queueCmd tea.Cmd
+		if retryable(msg.err) {
+			queueCmd = m.retries.add(rejectAction(msg.run, msg.reason), msg.err)
+			msg.queued = true
+		}
+		m.queue, cmd = m.queue.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, tea.Batch(cmd, queueCmd)
+
+	case confirmMsg:
+		m, cmd = m.openConfirm(msg)
+		return m, cmd
+
+	case markUnreadMsg:
+		if msg.err != nil {
+			m.setRead(msg.ids, true)
+			m.status = fmt.Sprintf("Couldn't mark unread: %v", msg.err)
+			m.viewport.SetContent(m.renderContent())
+		}
+		return m, nil
+
+	case markAllReadMsg:
+		m, cmd = m.markRead(msg.ids)
+		m.status = fmt.Sprintf("Marked %d notifications read", len(msg.ids))
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case queueMsg, modStatsMsg, verifyAllMsg, rejectConfirmedMsg, claimsMsg, bookmarkPositionMsg, bookmarkedMsg, retimedMsg:
+		m.queue, cmd = m.queue.update(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case pbAlertsMsg:
+		if msg.err != nil {
+			log.Printf("pb watch: %v", msg.err)
+		}
+		m.pbAlerts = append(m.pbAlerts, msg.alerts...)
+		alerts := make([]Alert, len(msg.alerts))
+		for i, a := range msg.alerts {
+			alerts[i] = a.Alert()
+		}
+		return m, tea.Batch(sendAlertsCmd(m.alerts, alerts), schedulePBCheck())
+
+	case pbCheckMsg:
+		return m, checkPBsCmd(m.client)
+
+	case gameRequestsMsg:
+		if msg.err != nil {
+			log.Printf("game requests: %v", msg.err)
+		}
+		if msg.requests != nil {
+			m.submissions.requests = msg.requests
+		}
+		m.requestAlerts = append(m.requestAlerts, msg.alerts...)
+		alerts := make([]Alert, len(msg.alerts))
+		for i, a := range msg.alerts {
+			alerts[i] = a.Alert()
+		}
+		return m, tea.Batch(sendAlertsCmd(m.alerts, alerts), scheduleGameRequestCheck())
+
+	case gameRequestCheckMsg:
+		return m, checkGameRequestsCmd(m.client)
+
+	case detailMsg:
+		if m.detail != nil {
+			m.detail.update(msg)
+			m.viewport.SetContent(m.renderContent())
+		}
+		return m, nil
+
+	case runCommentsMsg:
+		if t := m.openComments(); t != nil {
+			cmd = t.update(msg, m.client)
+			m.viewport.SetContent(m.renderContent())
+		}
+		return m, cmd
+
+	case commentPostedMsg:
+		var queueCmd tea.Cmd
+		if retryable(msg.err) {
+			queueCmd = m.retries.add(commentAction(msg.runID, msg.text), msg.err)
+			msg.queued = true
+		}
+		if t := m.openComments(); t != nil && t.runID == msg.runID {
+			cmd = t.update(msg, m.client)
+			m.viewport.SetContent(m.renderContent())
+		} else if msg.queued {
+			cmd = statusCmd("Couldn't post the comment yet, will retry: %v", msg.err)
+		}
+		return m, tea.Batch(cmd, queueCmd)
+
+	case markReadMsg:
+		switch {
+		case msg.err == nil:
+			return m, nil
+		case retryable(msg.err):
+			var cmds []tea.Cmd
+			for account, ids := range m.byAccount(msg.ids) {
+				cmds = append(cmds, m.retries.add(markReadAction(account, ids), msg.err))
+			}
+			return m, tea.Batch(cmds...)
+		}
+		m = m.markUnread(msg.ids)
+		m.status = fmt.Sprintf("Couldn't mark read: %v", msg.err)
+		m.viewport.SetContent(m.renderContent())
+		return m, nil
+
+	case bindingMsg:
+		m, cmd = m.confirmedBinding(msg)
+		return m, cmd
+
+	case reportResultMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Report failed: %v", msg.err)
+		} else {
+			m.status = "Reported " + msg.target.Label
+		}
+		return m, nil
+
+	case statusMsg:
+		m.status = string(msg)
+		return m, nil
+
+	case updateMsg:
+		m.newVersion = string(msg)
+		return m, nil
+
+	case daemonStatusMsg:
+		m.daemon = nil
+		if msg.err == nil {
+			m.daemon = &msg.status
+		}
+		return m, daemonStatusCmd(daemonCheckInterval)
+
+	case refreshTickMsg:
+		if !m.refreshDue(time.Time(msg)) {
+			return m, refreshTickCmd()
+		}
+		m.refreshing = true
+		return m, tea.Batch(refreshTickCmd(), m.reloadInboxCmd())
+
+	case tea.FocusMsg:
+		m, cmd = m.catchUp()
+		return m, cmd
+
+	case tea.ResumeMsg:
+		// The terminal may have been resized in the meantime
+		m, cmd = m.catchUp()
+		return m, tea.Batch(cmd, tea.WindowSize())
+
+	case tea.BlurMsg:
+		m.unfocused = true
+		return m, nil
+
+	case inboxMsg:
+		m, cmd = m.reloadedInbox(msg)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = msg.Width - 4
+		m.viewport.Height = msg.Height - 8
+		m.boards.width = m.boardsWidth()
+		if m.popup {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - popupHeight
+		}
+
 	case tea.KeyMsg:
+		m.status = ""
 		switch msg.String() {
-		case "q", "ctrl+c":
+		case "ctrl+c":
+			// A half typed submission is kept as a draft
+			m.submissions.persist()
+			m.cancelled = true
 			return m, tea.Quit
+		case "ctrl+z":
+			// In case the suspended app never comes back
+			m.submissions.persist()
+			return m, tea.Suspend
+		}
+		// Dialogs sit over whichever screen opened them
+		if m.confirm != nil {
+			cmd = m.confirm.update(msg)
+			m.confirm = nil
+			return m, cmd
+		}
+		if m.report != nil {
+			m.report, cmd = m.report.update(msg, m.client)
+			return m, cmd
+		}
+		if m.qr != nil {
+			m.qr = nil
+			return m, nil
+		}
+		if m.help != nil {
+			m.help = nil
+			return m, nil
+		}
+		if msg.String() == "?" && !m.typing() && !m.popup {
+			m.help = m.newHelpOverlay()
+			return m, nil
+		}
+		if msg.String() == "Q" && !m.typing() {
+			if url, ok := m.qrURL(); ok {
+				qr, err := newQROverlay(url)
+				if err != nil {
+					return m, statusCmd("%v", err)
+				}
+				m.qr = qr
+				return m, nil
+			}
+		}
+		if m.popup {
+			return m.updatePopup(msg)
+		}
+		if steps, ok := m.bindings[m.screen][msg.String()]; ok && m.bindingsActive() {
+			m, cmd = m.startBinding(msg.String(), steps)
+			return m, cmd
+		}
+		if p, a, ok := m.pluginActionFor(m.screen, msg.String()); ok && m.bindingsActive() {
+			m, cmd = m.runPluginAction(p, a)
+			return m, cmd
+		}
+		switch msg.String() {
+		case "z":
+			if !m.typing() {
+				m, cmd = m.undoLast()
+				return m, cmd
+			}
+		case "tab":
+			return m.switchScreen((m.screen + 1) % screenCount)
+		case "shift+tab":
+			return m.switchScreen((m.screen + screenCount - 1) % screenCount)
+		}
+	}
+
+	if m.report != nil && m.report.editing {
+		// Cursor blinks for the reason box
+		m.report.input, cmd = m.report.input.Update(msg)
+		return m, cmd
+	}
+
+	switch m.screen {
+	case screenTimer:
+		return m.updateTimer(msg)
+	case screenRaces:
+		return m.updateRaces(msg)
+	case screenWeek:
+		return m.updateSummary(msg)
+	case screenRecords:
+		return m.updateRecords(msg)
+	case screenRunners:
+		return m.updateRunners(msg)
+	case screenStats:
+		return m.updateStats(msg)
+	case screenBoards:
+		return m.updateBoards(msg)
+	case screenQueue:
+		return m.updateQueue(msg)
+	case screenSubmissions:
+		return m.updateSubmissions(msg)
+	case screenSearch:
+		return m.updateSearch(msg)
+	case screenActivity:
+		return m.updateActivity(msg)
+	case screenThreads:
+		return m.updateThreads(msg)
+	case screenPlugins:
+		return m.updatePlugins(msg)
+	}
+
+	if m.detail != nil {
+		return m.updateDetail(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		var handled bool
+		if m.inbox, cmd, handled = m.inbox.update(msg, m.notifications); handled {
+			m.selected = 0
+			m.viewport.SetContent(m.renderContent())
+			m.viewport.GotoTop()
+			return m, cmd
+		}
+	} else if m.inbox.capturing() {
+		// Cursor blinks for the search box
+		m.inbox.input, cmd = m.inbox.input.Update(msg)
+		return m, cmd
+	}
+
+	visible := m.inbox.apply(m.notifications)
+	rows := m.notificationRows(visible)
+	row, onRow := m.selectedRow(rows)
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		// A number being typed takes the keys until it's done
+		if key := msg.String(); len(key) == 1 && key >= "0" && key <= "9" {
+			m = m.typeJumpDigit(key, visible)
+			m.viewport.SetContent(m.renderContent())
+			return m, nil
+		} else if m.jump != "" {
+			switch key {
+			case "enter":
+				m = m.jumpTo(visible)
+			case "backspace":
+				m.jump = m.jump[:len(m.jump)-1]
+			default:
+				m.jump = ""
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "x":
+			m.pbAlerts, m.requestAlerts = nil, nil
+		case "T":
+			return m.switchScreen(screenTimer)
 		case "up", "k":
 			if m.selected > 0 {
 				m.selected--
 			}
 		case "down", "j":
-			if m.selected < len(m.notifications)-1 {
+			if m.selected < len(m.pins)+len(rows)-1 {
 				m.selected++
 			}
 		case "enter":
-			if m.selected >= 0 && m.selected < len(m.notifications) {
-				notification := m.notifications[m.selected]
-				url := "https://www.speedrun.com" + notification.Path
-				openBrowser(url)
+			if marked := m.markedNotifications(); len(marked) > 0 {
+				m, cmd = m.openMarked(marked)
+				m.viewport.SetContent(m.renderContent())
+				return m, cmd
+			}
+			// Pins come first, then the notifications under their headers
+			if m.selected < len(m.pins) {
+				return m.openLink(m.pins[m.selected].URL)
+			} else if onRow && row.header {
+				m = m.toggleSection(row.section, visible)
+			} else if onRow {
+				var readCmd tea.Cmd
+				if m.autoMarkRead && !row.n.Read {
+					m, readCmd = m.markRead([]string{row.n.ID})
+				}
+				next, openCmd := m.openLink("https://www.speedrun.com" + row.n.Path)
+				return next, tea.Batch(readCmd, openCmd)
+			}
+		case " ":
+			if onRow {
+				m = m.toggleSection(row.section, visible)
+			}
+		case "esc":
+			m.marked = nil
+		case "m":
+			if onRow && !row.header {
+				m = m.toggleMark(row.n)
+				if m.selected < len(m.pins)+len(rows)-1 {
+					m.selected++
+				}
 			}
+		case "y", "Y":
+			asJSON := msg.String() == "Y"
+			if m.selected < len(m.pins) {
+				p := m.pins[m.selected]
+				return m, yankCmd("pin", fmt.Sprintf("**%s**\n%s", p.Title, p.URL), p, asJSON)
+			} else if onRow && !row.header {
+				return m, yankCmd("notification", notificationMarkdown(row.n), row.n, asJSON)
+			}
+		case "M":
+			m.autoMarkRead = !m.autoMarkRead
+			if m.autoMarkRead {
+				m.status = "Opening a notification marks it read"
+			} else {
+				m.status = "Opening a notification leaves it unread"
+			}
+		case "R":
+			var ids []string
+			for _, n := range visible {
+				if !n.Read {
+					ids = append(ids, n.ID)
+				}
+			}
+			if len(ids) > 0 {
+				return m, confirmCmd(markAllReadMsg{ids: ids}, "Mark all %d unread notifications shown read?", len(ids))
+			}
+		case "r":
+			if onRow && !row.header && !row.n.Read {
+				var cmd tea.Cmd
+				m, cmd = m.markRead([]string{row.n.ID})
+				m.viewport.SetContent(m.renderContent())
+				return m, cmd
+			}
+		case "b":
+			if m.selected < len(m.pins) {
+				m = m.togglePin(m.pins[m.selected])
+			} else if onRow && !row.header {
+				m = m.togglePin(notificationPin(row.n))
+			}
+			m.selected = min(m.selected, max(len(m.pins)+len(m.notificationRows(visible))-1, 0))
 		}
+	}
 
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.viewport.Width = msg.Width - 4
-		m.viewport.Height = msg.Height - 8
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// typing reports whether the screen has a text box taking keys
+func (m model) typing() bool {
+	switch m.screen {
+	case screenNotifications:
+		if m.detail != nil {
+			return m.detail.capturing()
+		}
+		return m.inbox.capturing()
+	case screenQueue:
+		return m.queue.capturing()
+	case screenRunners:
+		return m.following.capturing()
+	case screenSubmissions:
+		return m.submissions.capturing()
+	case screenSearch:
+		return true
+	case screenTimer:
+		return m.timer.naming
+	}
+	return false
+}
+
+// openLink shows a speedrun.com page in the app when there's a screen for
+// it, and in the browser otherwise
+func (m model) openLink(pageURL string) (tea.Model, tea.Cmd) {
+	if !strings.Contains(pageURL, "speedrun.com") {
+		openBrowser(pageURL)
+		return m, nil
+	}
+
+	l := resolveLink(pageURL)
+	switch l.Kind {
+	case linkUnsupported:
+		openBrowser(pageURL)
+		return m, nil
+	case linkGame:
+		var cmd tea.Cmd
+		m.boards, cmd = m.boards.openGame(l.Game)
+		model, switchCmd := m.switchScreen(screenBoards)
+		return model, tea.Batch(cmd, switchCmd)
 	}
 
+	if l.Kind == linkThread {
+		m.threads = m.threads.markRead(l.ID)
+	}
+	m.detail = newDetailModel(l, pageURL)
 	m.viewport.SetContent(m.renderContent())
+	m.viewport.GotoTop()
+	return m, m.detail.loadCmd(m.client, m.boards.games, m.times, m.flags, m.boards.goals)
+}
+
+func (m model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.detail.capturing() {
+		cmd := m.detail.comments.update(msg, m.client)
+		m.viewport.SetContent(m.renderContent())
+		return m, cmd
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			m.detail = nil
+			m.viewport.SetContent(m.renderContent())
+			return m, nil
+		case "o":
+			openBrowser(m.detail.url)
+		case "v":
+			if m.detail.video != "" {
+				return m, playVideoCmd(m.videoPlayer, m.detail.video)
+			}
+		case "!":
+			if targets := m.detail.reportTargets(); len(targets) > 0 {
+				m.report = newReportDialog(targets)
+			}
+			return m, nil
+		case "f":
+			if m.detail.link.Kind == linkUser {
+				var cmd tea.Cmd
+				m.following, cmd = m.following.toggle(m.detail.link.ID)
+				return m, cmd
+			}
+		case "s":
+			if m.detail.link.Kind == linkThread {
+				subscribed, known := m.threads.subscribed(m.detail.link.ID)
+				if !known {
+					return m, statusCmd("Your thread subscriptions haven't loaded yet")
+				}
+				return m, subscribeThreadCmd(m.client, m.detail.link.ID, !subscribed)
+			}
+		case "c", "[", "]":
+			if m.detail.comments != nil {
+				cmd := m.detail.comments.update(msg, m.client)
+				m.viewport.SetContent(m.renderContent())
+				return m, cmd
+			}
+		}
+	}
+
+	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
 }
 
+func (m model) switchScreen(s screen) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.screen = s
+	switch s {
+	case screenTimer:
+		cmd = m.timer.activate()
+	case screenRaces:
+		m.races, cmd = m.races.activate()
+	case screenQueue:
+		m.queue, cmd = m.queue.activate()
+	case screenSubmissions:
+		m.submissions, cmd = m.submissions.activate()
+	case screenSearch:
+		m.search, cmd = m.search.activate()
+	case screenActivity:
+		m.activity, cmd = m.activity.activate()
+	case screenThreads:
+		m.threads, cmd = m.threads.activate()
+	case screenPlugins:
+		m.pluginTab, cmd = m.pluginTab.activate()
+	case screenWeek:
+		m.summary, cmd = m.summary.activate()
+	case screenRecords:
+		m.records, cmd = m.records.activate()
+	case screenRunners:
+		m.following, cmd = m.following.activate()
+	case screenStats:
+		m.stats, cmd = m.stats.activate()
+	}
+	m.viewport.SetContent(m.renderContent())
+	m.viewport.GotoTop()
+	return m, cmd
+}
+
+func (m model) updateTimer(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && !m.timer.naming {
+		switch msg.String() {
+		case "q":
+			if !m.timer.running {
+				return m, tea.Quit
+			}
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		case "s":
+			// Submit the finished run, the wizard starting with its time
+			if d, ok := m.timer.Final(); ok {
+				next, cmd := m.switchScreen(screenSubmissions)
+				m = next.(model)
+				var offer tea.Cmd
+				m.submissions, offer = m.submissions.offer(Draft{ID: fmt.Sprint(time.Now().UnixNano()), Time: formatRunTime(d)})
+				m.viewport.SetContent(m.renderContent())
+				return m, tea.Batch(cmd, offer)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.timer, cmd = m.timer.update(msg)
+	return m, cmd
+}
+
+func (m model) updateBoards(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			if m.boards.level == boardsGames {
+				return m.switchScreen(screenNotifications)
+			}
+		case "c":
+			if m.chat != nil {
+				return m.setChat(nil), nil
+			}
+			if stream, ok := m.boards.selectedStream(); ok {
+				pane, cmd := openChat(stream.UserLogin)
+				return m.setChat(pane), cmd
+			}
+		case "w":
+			// The board opens the stream itself below
+			if stream, ok := m.boards.selectedStream(); ok && m.autoChat &&
+				(m.chat == nil || m.chat.channel != strings.ToLower(stream.UserLogin)) {
+				pane, cmd := openChat(stream.UserLogin)
+				m = m.setChat(pane)
+				var boardCmd tea.Cmd
+				m.boards, boardCmd = m.boards.update(msg)
+				return m, tea.Batch(cmd, boardCmd)
+			}
+		}
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if r, ok := m.boards.selectedRun(); ok {
+			switch msg.String() {
+			case "v":
+				if r.Video == "" {
+					return m, statusCmd("This run has no video")
+				}
+				return m, playVideoCmd(m.videoPlayer, r.Video)
+			case "b":
+				title := fmt.Sprintf("%s: %s by %s", m.boards.title(), m.boards.formatTime(r), m.boards.board.PlayerNames(r))
+				return m.togglePin(runPin(r.ID, title, m.boards.runURL(r))), nil
+			case "!":
+				m.report = newReportDialog([]reportTarget{runReportTarget(r.ID, m.boards.board.PlayerNames(r))})
+				return m, nil
+			case "y", "Y":
+				y := m.boards.yanked(r)
+				return m, yankCmd("run", y.markdown(), y, msg.String() == "Y")
+			}
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.boards, cmd = m.boards.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m.followBoardJump(), tea.Batch(cmd, vpCmd)
+}
+
+// setChat swaps the chat pane, hanging up the old one, and fits the board
+// to the room that's left
+func (m model) setChat(pane *chatPane) model {
+	if m.chat != nil {
+		m.chat.close()
+	}
+	m.chat = pane
+	m.boards.width = m.boardsWidth()
+	m.viewport.SetContent(m.renderContent())
+	return m
+}
+
+// boardsWidth is the room the board has, less the chat pane when it's open
+func (m model) boardsWidth() int {
+	if m.chat != nil {
+		return max(m.viewport.Width-chatWidth, 0)
+	}
+	return m.viewport.Width
+}
+
+// followBoardJump scrolls the board to a selection that moved further than
+// a key press would, putting it mid-screen
+func (m model) followBoardJump() model {
+	if m.boards.jumped && m.screen == screenBoards {
+		m.boards.jumped = false
+		m.viewport.SetYOffset(m.boards.headerLines() + m.boards.selected - m.viewport.Height/2)
+	}
+	return m
+}
+
+func (m model) updateQueue(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// An open prompt gets the next key, whatever it is
+	if msg, ok := msg.(tea.KeyMsg); ok && !m.queue.capturing() {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		case "v":
+			if r, ok := m.queue.selectedRun(); ok {
+				if r.Video == "" {
+					return m, statusCmd("This run has no video")
+				}
+				return m, playVideoCmd(m.videoPlayer, r.Video)
+			}
+		case "b":
+			if r, ok := m.queue.selectedRun(); ok {
+				title := fmt.Sprintf("%s • %s: %s by %s", m.queue.gameName(r.GameID), m.queue.categories[r.CategoryID],
+					m.queue.formatTime(r), strings.Join(r.Players, ", "))
+				return m.togglePin(runPin(r.ID, title, r.Weblink)), nil
+			}
+		case "!":
+			if r, ok := m.queue.selectedRun(); ok {
+				m.report = newReportDialog([]reportTarget{runReportTarget(r.ID, strings.Join(r.Players, ", "))})
+				return m, nil
+			}
+		case "m":
+			// The run with its comments, for talking it over with the
+			// runner or the other moderators
+			if r, ok := m.queue.selectedRun(); ok {
+				next, _ := m.switchScreen(screenNotifications)
+				return next.(model).openLink(r.Weblink)
+			}
+		case "y", "Y":
+			if r, ok := m.queue.selectedRun(); ok {
+				y := m.queue.yanked(r)
+				return m, yankCmd("run", y.markdown(), y, msg.String() == "Y")
+			}
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.queue, cmd = m.queue.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updateSubmissions(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && !m.submissions.capturing() {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.submissions, cmd = m.submissions.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	// Typing into the wizard shouldn't scroll
+	if _, ok := msg.(tea.KeyMsg); !ok || !m.submissions.capturing() {
+		m.viewport, vpCmd = m.viewport.Update(msg)
+	}
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+// updateSearch takes every key but esc and enter for the query box
+func (m model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			if m.search.input.Value() != "" {
+				m.search.input.SetValue("")
+				m.search.refresh()
+				m.viewport.SetContent(m.renderContent())
+				return m, nil
+			}
+			return m.switchScreen(screenNotifications)
+		case "enter":
+			doc, ok := m.search.selectedDoc()
+			if !ok {
+				return m, nil
+			}
+			next, _ := m.switchScreen(screenNotifications)
+			return next.(model).openLink(doc.URL)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	return m, cmd
+}
+
+func (m model) updateActivity(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.activity, cmd = m.activity.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updateThreads(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		case "enter":
+			if th, ok := m.threads.selectedThread(); ok {
+				next, _ := m.switchScreen(screenNotifications)
+				return next.(model).openLink(th.url())
+			}
+			return m, nil
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.threads, cmd = m.threads.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updatePlugins(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			var closed bool
+			if m.pluginTab, closed = m.pluginTab.back(); !closed {
+				return m.switchScreen(screenNotifications)
+			}
+			m.viewport.SetContent(m.renderContent())
+			return m, nil
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.pluginTab, cmd = m.pluginTab.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updateSummary(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.summary, cmd = m.summary.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updateRecords(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		case "enter":
+			if wr, ok := m.records.selectedRecord(); ok {
+				next, _ := m.switchScreen(screenNotifications)
+				return next.(model).openLink(wr.URL)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.records, cmd = m.records.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updateRunners(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && !m.following.capturing() {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		case "enter":
+			if e, ok := m.following.selectedEvent(); ok {
+				next, _ := m.switchScreen(screenNotifications)
+				return next.(model).openLink(e.URL)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.following, cmd = m.following.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updateStats(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m.switchScreen(screenNotifications)
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.stats, cmd = m.stats.update(msg)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
+func (m model) updateRaces(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			if m.races.watch == nil {
+				return m.switchScreen(screenNotifications)
+			}
+		}
+	}
+
+	var cmd, vpCmd tea.Cmd
+	m.races, cmd = m.races.update(msg, true)
+	m.viewport.SetContent(m.renderContent())
+	m.viewport, vpCmd = m.viewport.Update(msg)
+	return m, tea.Batch(cmd, vpCmd)
+}
+
 func (m model) renderContent() string {
+	m.syncTheme()
+	switch m.screen {
+	case screenRaces:
+		return m.races.view()
+	case screenWeek:
+		return m.summary.view()
+	case screenRecords:
+		return m.records.view()
+	case screenRunners:
+		return m.following.view()
+	case screenStats:
+		return m.stats.view()
+	case screenBoards:
+		return m.boards.view()
+	case screenQueue:
+		return m.queue.view()
+	case screenSubmissions:
+		return m.submissions.view()
+	case screenSearch:
+		return m.search.view()
+	case screenActivity:
+		return m.activity.view()
+	case screenThreads:
+		return m.threads.view()
+	case screenPlugins:
+		return m.pluginTab.view()
+	}
+	if m.detail != nil {
+		return m.detail.view()
+	}
+	if m.popup {
+		return m.renderPopupList()
+	}
+
 	var b strings.Builder
 
-	for i, n := range m.notifications {
-		item := m.renderNotification(n)
-		style := unselectedItemStyle
-		if i == m.selected {
-			style = selectedItemStyle
+	// Pins stay on top whatever the filter
+	if len(m.pins) > 0 {
+		b.WriteString(pinnedHeaderStyle.Render("Pinned"))
+		b.WriteString("\n")
+		for i, p := range m.pins {
+			style := unselectedItemStyle
+			if i == m.selected {
+				style = selectedItemStyle
+			}
+			b.WriteString(style.Render(renderPin(p)))
+			b.WriteString("\n")
 		}
-		b.WriteString(style.Render(item))
+		b.WriteString("\n")
+	}
+
+	visible := m.inbox.apply(m.notifications)
+	var numbers map[string]int
+	if m.indexColumn {
+		numbers = m.notificationNumbers(visible)
+	}
+	for i, r := range m.notificationRows(visible) {
+		b.WriteString(m.renderRow(len(m.pins)+i, r, numbers))
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-func (m model) renderNotification(n Notification) string {
-	var b strings.Builder
+// renderRow renders a day header or a notification at position i of the
+// list, numbered when numbers is set
+func (m model) renderRow(i int, r listRow, numbers map[string]int) string {
+	style := unselectedItemStyle
+	if i == m.selected {
+		style = selectedItemStyle
+	}
+	if r.header {
+		return style.Render(r.renderHeader(m.collapsed[sectionNames[r.section]]))
+	}
 
-	// Status and timestamp on one line
-	readStatus := unreadDotStyle.String()
-	if n.Read {
-		readStatus = readDotStyle.String()
+	item := m.renderNotification(r.n)
+	if m.marked[r.n.ID] {
+		item = markedStyle.String() + " " + item
+	}
+	if numbers != nil {
+		width := len(strconv.Itoa(len(numbers)))
+		item = lipgloss.JoinHorizontal(lipgloss.Top, indexStyle.Render(fmt.Sprintf("%*d ", width, numbers[r.n.ID])), item)
 	}
-	date := time.Unix(n.Date, 0).Format("2006-01-02 15:04:05")
-	b.WriteString(fmt.Sprintf("[%s] %s\n", readStatus, date))
+	if i != m.selected && m.highlighted[r.n.ID] {
+		style = highlightedItemStyle
+	}
+	return style.Render(item)
+}
 
-	// Title with proper wrapping
-	b.WriteString(n.Title)
-	b.WriteString("\n")
+func (m model) renderNotification(n Notification) string {
+	if !n.Known() {
+		return renderFallbackNotification(n, m.keywords)
+	}
 
-	// URL slightly dimmed
-	b.WriteString(urlStyle.Render(fmt.Sprintf("speedrun.com%s", n.Path)))
+	if m.layout != nil {
+		return m.renderTemplated(n)
+	}
 
-	return b.String()
+	// Status and timestamp on one line, then the title and the URL
+	f := m.notificationFields(n)
+	return fmt.Sprintf("[%s] %s%s\n%s\n%s", f.Status, renderAccountBadge(n.Account), f.Date, f.Title, f.Path)
 }
 
 func (m model) View() string {
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v", m.err)
 	}
+	m.syncTheme()
+	if m.qr != nil {
+		return m.qr.view(m.width, m.height)
+	}
+	if m.help != nil {
+		return m.help.view(m.width, m.height)
+	}
+	if m.popup {
+		return m.popupView()
+	}
+
+	hints := m.screenHelp()
+	switch m.screen {
+	case screenTimer:
+		return m.renderScreen("TIMER", "", m.timer.view(), hints)
+	case screenWeek:
+		return m.renderScreen("THIS WEEK", "", m.viewport.View(), hints)
+	case screenRecords:
+		return m.renderScreen("WORLD RECORDS", "", m.viewport.View(), hints)
+	case screenRunners:
+		return m.renderScreen("FOLLOWED RUNNERS", "", m.viewport.View(), hints)
+	case screenStats:
+		return m.renderScreen("MY STATS", "", m.viewport.View(), hints)
+	case screenBoards:
+		body := m.viewport.View()
+		if m.chat != nil && !m.popup {
+			vp := m.viewport
+			vp.Width = m.boardsWidth()
+			body = lipgloss.JoinHorizontal(lipgloss.Top, vp.View(), m.chat.view(vp.Height))
+		}
+		return m.renderScreen("LEADERBOARDS", m.boards.title(), body, hints)
+	case screenQueue:
+		return m.renderScreen("VERIFICATION QUEUE", m.queue.title(), m.viewport.View(), hints)
+	case screenSubmissions:
+		return m.renderScreen("SUBMISSIONS", "", m.viewport.View(), hints)
+	case screenSearch:
+		return m.renderScreen("SEARCH", "", m.viewport.View(), hints)
+	case screenActivity:
+		return m.renderScreen("ACTIVITY", "", m.viewport.View(), hints)
+	case screenThreads:
+		return m.renderScreen("FORUM THREADS", m.threads.title(), m.viewport.View(), hints)
+	case screenPlugins:
+		return m.renderScreen("PLUGINS", m.pluginTab.title(), m.viewport.View(), hints)
+	case screenRaces:
+		return m.renderScreen("RACETIME.GG RACES", "", m.viewport.View(), hints)
+	}
+	if m.detail != nil {
+		return m.renderScreen("SPEEDRUN.COM", m.detail.title, m.viewport.View(), hints)
+	}
 
 	// Header with unread count
 	header := titleStyle.Render("SPEEDRUN.COM NOTIFICATIONS")
-	unreadCount := unreadCountStyle.Render(fmt.Sprintf("%d unread", m.unreadCount))
-	header = lipgloss.JoinHorizontal(lipgloss.Center, header, unreadCount)
+	counts := fmt.Sprintf("%d unread", m.unreadCount)
+	if m.muted > 0 {
+		counts += fmt.Sprintf(" • %d muted", m.muted)
+	}
+	unreadCount := unreadCountStyle.Render(counts)
+	header = lipgloss.JoinHorizontal(lipgloss.Center, header, unreadCount, m.renderTabs())
 
 	// Status bar with simplified navigation hints
-	statusBar := statusBarStyle.Render(
-		fmt.Sprintf("Page %d/%d • j/k or ↑/↓ to navigate • enter open • q quit",
-			m.pagination.Page, m.pagination.Pages))
+	switch {
+	case m.confirm != nil:
+		hints = m.confirm.help()
+	case m.inbox.capturing():
+		hints = m.inbox.help()
+	case m.jump != "":
+		hints = fmt.Sprintf("go to %s • enter jump • esc cancel", m.jump)
+	case len(m.markedNotifications()) > 0:
+		hints = fmt.Sprintf("%d marked • enter open them all • m unmark • esc clear marks", len(m.markedNotifications()))
+	}
+	statusBar := m.renderStatusBar(hints)
+
+	// PB rank and game request alerts sit above the list until dismissed
+	viewport := m.viewport
+	sections := []string{header}
+	if len(m.pbAlerts)+len(m.requestAlerts) > 0 {
+		var lines []string
+		for _, a := range m.pbAlerts {
+			lines = append(lines, a.String())
+		}
+		for _, a := range m.requestAlerts {
+			lines = append(lines, a.String())
+		}
+		banner := alertBannerStyle.Render(strings.Join(lines, "\n") + "\n(x to dismiss)")
+		viewport.Height -= lipgloss.Height(banner)
+		sections = append(sections, banner)
+	}
+	if m.confirm != nil {
+		banner := m.confirm.view()
+		viewport.Height -= lipgloss.Height(banner)
+		sections = append(sections, banner)
+	}
+	if bar := m.inbox.view(len(m.inbox.apply(m.notifications)), len(m.notifications)); bar != "" {
+		viewport.Height -= lipgloss.Height(bar)
+		sections = append(sections, bar)
+	}
+	sections = append(sections, viewport.View(), statusBar)
 
 	return appStyle.Render(
 		lipgloss.JoinVertical(
 			lipgloss.Left,
-			header,
-			m.viewport.View(),
-			statusBar,
+			sections...,
 		))
 }
 
+// screenHelp is the key hints for the screen showing, as the status bar
+// gives them
+func (m model) screenHelp() string {
+	const tail = " • ? help • tab switch view • q quit"
+	switch m.screen {
+	case screenTimer:
+		if _, ok := m.timer.Final(); ok {
+			return m.timer.help() + " • s submit • tab switch view • esc back"
+		}
+		return m.timer.help() + " • tab switch view • esc back"
+	case screenWeek:
+		return m.summary.help() + tail
+	case screenRecords:
+		return m.records.help() + tail
+	case screenRunners:
+		return m.following.help() + tail
+	case screenStats:
+		return m.stats.help() + tail
+	case screenBoards:
+		return m.boards.help() + tail
+	case screenQueue:
+		return m.queue.help() + tail
+	case screenSubmissions:
+		if m.submissions.capturing() {
+			return m.submissions.help()
+		}
+		return m.submissions.help() + tail
+	case screenSearch:
+		return m.search.help() + " • tab switch view"
+	case screenActivity:
+		return m.activity.help() + tail
+	case screenThreads:
+		return m.threads.help() + tail
+	case screenPlugins:
+		return m.pluginTab.help() + tail
+	case screenRaces:
+		return m.races.help() + tail
+	}
+	if m.detail != nil {
+		return m.detail.help() + " • q quit"
+	}
+	pages := fmt.Sprintf("Page %d/%d", m.pagination.Page, m.pagination.Pages)
+	if m.pagination.Page > 1 {
+		pages = fmt.Sprintf("Pages 1-%d/%d", m.pagination.Page, m.pagination.Pages)
+	}
+	return fmt.Sprintf("%s • j/k navigate • enter open • m mark • space fold day • r/R mark read/all read • M auto mark read • b pin • y/Y copy markdown/json • z undo • %s • T timer • ? help • tab switch view • q quit",
+		pages, m.inbox.help())
+}
+
+// renderScreen lays out a tab: title and tabs, body, then the status bar
+func (m model) renderScreen(title, subtitle, body, hints string) string {
+	switch {
+	case m.confirm != nil:
+		body, hints = m.confirm.view(), m.confirm.help()
+	case m.report != nil:
+		body, hints = m.report.view(), m.report.help()
+	}
+	header := titleStyle.Render(title)
+	if subtitle != "" {
+		header = lipgloss.JoinHorizontal(lipgloss.Center, header, unreadCountStyle.Render(subtitle))
+	}
+	return appStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.JoinHorizontal(lipgloss.Center, header, m.renderTabs()),
+			body,
+			m.renderStatusBar(hints),
+		))
+}
+
+// renderStatusBar shows the key hints, or the latest status message
+// until the next key press
+func (m model) renderStatusBar(hints string) string {
+	text := hints
+	if m.status != "" {
+		text = m.status
+	}
+	if refreshed := m.renderRefreshed(); refreshed != "" {
+		text = refreshed + " • " + text
+	}
+	// Queued writes stay in view until they go through
+	if pending := m.retries.status(); pending != "" {
+		text = pending + " • " + text
+	}
+	if m.client != nil {
+		if health := m.client.Health().render(); health != "" {
+			text = health + " • " + text
+		}
+		if daemon := renderDaemon(m.daemon); daemon != "" {
+			text = daemon + " • " + text
+		}
+		if on, skipped := m.client.DryRun(); on {
+			text = dryRunStyle.Render(fmt.Sprintf("DRY RUN, %d not sent", skipped)) + " • " + text
+		}
+	}
+	if m.newVersion != "" {
+		text += " • " + m.newVersion + " available, run speedrunner update"
+	}
+	return statusBarStyle.Render(text)
+}
+
+// renderTabs shows every screen with the current one highlighted
+func (m model) renderTabs() string {
+	tabs := make([]string, len(screenNames))
+	for i, name := range screenNames {
+		style := inactiveTabStyle
+		if screen(i) == m.screen {
+			style = activeTabStyle
+		}
+		tabs[i] = style.Render(name)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Center, tabs...)
+}
+
 func openBrowser(url string) error {
 	var err error
 	switch runtime.GOOS {
@@ -313,24 +1942,248 @@ func openBrowser(url string) error {
 	return err
 }
 
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage:\n")
+	fmt.Fprintf(out, "  speedrunner -session <cookie>            browse notifications\n")
+	fmt.Fprintf(out, "  speedrunner refresh-cache [game...]      re-download cached game metadata\n")
+	fmt.Fprintf(out, "  speedrunner livesplit [-addr host:port]  capture a finished run from LiveSplit\n")
+	fmt.Fprintf(out, "  speedrunner livesplit -obs               time a run by OBS's recording\n")
+	fmt.Fprintf(out, "  speedrunner timer [-name name]           run the split timer on its own\n")
+	fmt.Fprintf(out, "  speedrunner check-video [-time t] <url>  check a run video before submitting\n")
+	fmt.Fprintf(out, "  speedrunner submit -video <url>          submit a run, guessing the details from its video\n")
+	fmt.Fprintf(out, "  speedrunner retime [-fps 60] <start> <end>\n")
+	fmt.Fprintf(out, "                                           time a run from its first and last frame\n")
+	fmt.Fprintf(out, "  speedrunner export [-format csv] [-from d] [-to d] [-o file]\n")
+	fmt.Fprintf(out, "                                           export archived notifications\n")
+	fmt.Fprintf(out, "  speedrunner config init|edit|validate|path\n")
+	fmt.Fprintf(out, "                                           set up, edit or check the config\n")
+	fmt.Fprintf(out, "  speedrunner update [-force]              install the latest release\n")
+	fmt.Fprintf(out, "  speedrunner bench [-n 10] [-game abbr]   time the site's endpoints\n")
+	fmt.Fprintf(out, "  speedrunner pick notification|game|run [-id] [-video]\n")
+	fmt.Fprintf(out, "                                           pick one by typing and print its URL\n")
+	fmt.Fprintf(out, "  speedrunner completion bash|zsh|fish|powershell\n")
+	fmt.Fprintf(out, "                                           print a shell completion script\n")
+	fmt.Fprintf(out, "  speedrunner daemon run|install|uninstall watch notifications in the background\n")
+	fmt.Fprintf(out, "  speedrunner daemon healthcheck           show how the daemon is doing\n\n")
+	fmt.Fprintf(out, "Flags:\n")
+	flag.PrintDefaults()
+}
+
 func main() {
+	defer exitOnCrash()
+
 	sessionID := flag.String("session", "", "Speedrun.com PHPSESSID cookie value")
+	apiBase := flag.String("api-base", "", "Base URL of the v2 API (default "+defaultBaseURL+")")
+	perPage := flag.Int("per-page", 0, "Notifications per page, up to 100 (default: the site's)")
+	pages := flag.Int("pages", 0, "Pages of notifications to load at startup (default 1)")
+	dryRun := flag.Bool("dry-run", false, "Log verifications, rejections, mark reads and other writes instead of sending them")
+	popup := flag.Bool("popup", false, "Compact layout for a small popup, like tmux display-popup; opening a notification quits")
+	printOnExit := flag.String("print-on-exit", "", "Print the item selected when quitting with q, as a \"url\" or \"json\"")
+	flag.Usage = usage
 	flag.Parse()
 
+	if _, err := setupLogging(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: logging disabled: %v\n", err)
+	}
+
+	// Before the config is loaded, so a broken one can be fixed
+	switch flag.Arg(0) {
+	case "config":
+		if err := runConfig(flag.Args()[1:], *apiBase, *sessionID); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "completion":
+		if err := runCompletion(flag.Args()[1:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "__complete":
+		runComplete(flag.Args()[1:])
+		return
+	}
+
+	// First launch: walk through the setup before anything reads the config
+	if flag.Arg(0) == "" && !configExists() {
+		if _, err := runOnboarding(*apiBase, *sessionID); err != nil {
+			fmt.Printf("Error running setup: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if *apiBase != "" {
+		cfg.APIBase = *apiBase
+	}
+	if *sessionID == "" {
+		*sessionID = cfg.Session
+	}
+	if err := validateAPIBase(cfg.APIBase); err != nil {
+		fmt.Printf("Invalid API base: %v\n", err)
+		os.Exit(1)
+	}
+	if *perPage != 0 {
+		cfg.NotificationsPerPage = *perPage
+	}
+	if *pages != 0 {
+		cfg.NotificationPages = *pages
+	}
+	if err := cfg.validate(); err != nil {
+		fmt.Printf("Invalid config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := applyTLS(cfg.TLS); err != nil {
+		fmt.Printf("Invalid config: %v\n", err)
+		os.Exit(1)
+	}
+	crashLog.sendReports(cfg.CrashReports)
+
+	switch flag.Arg(0) {
+	case "refresh-cache":
+		if err := runRefreshCache(newConfiguredClient(cfg, *sessionID), flag.Args()[1:]); err != nil {
+			fmt.Printf("Error refreshing cache: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "timer":
+		if err := runTimer(flag.Args()[1:], cfg); err != nil {
+			fmt.Printf("Error running timer: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "check-video":
+		if err := runCheckVideo(flag.Args()[1:], cfg); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "retime":
+		if err := runRetime(flag.Args()[1:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "livesplit":
+		if err := runLiveSplit(flag.Args()[1:], cfg); err != nil {
+			fmt.Printf("Error reading LiveSplit: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "daemon":
+		if err := runDaemon(flag.Args()[1:], cfg, *sessionID); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "update":
+		if err := runUpdate(flag.Args()[1:]); err != nil {
+			fmt.Printf("Error updating: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "pick":
+		if err := runPick(flag.Args()[1:], cfg, *sessionID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "bench":
+		if err := runBench(flag.Args()[1:], cfg, *sessionID); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "export":
+		if err := runExport(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting history: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *sessionID == "" {
 		fmt.Println("Please provide your PHPSESSID using the -session flag")
 		os.Exit(1)
 	}
+	if *printOnExit != "" && !printFormats[*printOnExit] {
+		fmt.Printf("-print-on-exit must be url or json, got %q\n", *printOnExit)
+		os.Exit(1)
+	}
+
+	router, err := buildRouter(cfg.Routes, cfg.Sinks)
+	if err != nil {
+		fmt.Printf("Error in sink config: %v\n", err)
+		os.Exit(1)
+	}
 
-	client := NewClient(*sessionID)
-	p := tea.NewProgram(
-		initialModel(client),
+	rules, err := compileRules(cfg.Rules, cfg.Sinks)
+	if err != nil {
+		fmt.Printf("Error in rules config: %v\n", err)
+		os.Exit(1)
+	}
+
+	client := newConfiguredClient(cfg, *sessionID)
+	client.dryRun = *dryRun
+	m := initialModel(client, router, rules, cfg)
+	m.popup = *popup
+	if flag.Arg(0) == "submit" {
+		d, err := runSubmitVideo(flag.Args()[1:], cfg, client)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		// Straight into the wizard, with the guesses filled in
+		m.screen = screenSubmissions
+		m.submissions, _ = m.submissions.start(d)
+		m.submissions.persist()
+		m.submissions.pendingLoading = true
+	}
+	opts := []tea.ProgramOption{
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
-	)
+		// Reloads slow down while the terminal isn't focused
+		tea.WithReportFocus(),
+	}
+	if *printOnExit != "" {
+		// stdout is for the item, so the app draws on the terminal
+		tty, err := openTerminal()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer tty.Close()
+		opts = append(opts, tea.WithOutput(tty), tea.WithInputTTY())
+	}
+	p := tea.NewProgram(crashGuard{m}, opts...)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	// Bubble Tea has put the terminal back by now
+	if path := crashLog.reportPath(); path != "" {
+		fmt.Printf("speedrunner crashed, sorry. A report was written to %s\n", path)
+		fmt.Println("It has no session or notification text, so it's safe to attach to an issue.")
+		reportSent()
+		os.Exit(2)
+	}
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
+
+	if *printOnExit != "" {
+		printed, err := final.(crashGuard).m.printExitItem(os.Stdout, *printOnExit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		// Nothing picked, for scripts to tell apart from a pick
+		if !printed {
+			os.Exit(1)
+		}
+	}
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Notifications marked with m are opened together: enter sends every
+// marked one to the browser instead of opening the selected one.
+
+// Browsers drop or reorder tabs opened all at once
+const openAllDelay = 300 * time.Millisecond
+
+var markedStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#00BFFF")).
+	SetString("◆")
+
+// toggleMark marks the notification, or unmarks it
+func (m model) toggleMark(n Notification) model {
+	if m.marked == nil {
+		m.marked = map[string]bool{}
+	}
+	if m.marked[n.ID] {
+		delete(m.marked, n.ID)
+	} else {
+		m.marked[n.ID] = true
+	}
+	return m
+}
+
+// markedNotifications is every marked notification still in the list, in
+// list order
+func (m model) markedNotifications() []Notification {
+	var marked []Notification
+	for _, n := range m.inbox.apply(m.notifications) {
+		if m.marked[n.ID] {
+			marked = append(marked, n)
+		}
+	}
+	return marked
+}
+
+// openMarked opens every marked notification in the browser and clears
+// the marks, marking them read too with auto mark read on
+func (m model) openMarked(marked []Notification) (model, tea.Cmd) {
+	urls := make([]string, len(marked))
+	var unread []string
+	for i, n := range marked {
+		urls[i] = "https://www.speedrun.com" + n.Path
+		if !n.Read {
+			unread = append(unread, n.ID)
+		}
+	}
+	m.marked = nil
+
+	var readCmd tea.Cmd
+	if m.autoMarkRead && len(unread) > 0 {
+		m, readCmd = m.markRead(unread)
+	}
+	return m, tea.Batch(readCmd, openAllCmd(urls))
+}
+
+// openAllCmd opens the URLs one after another with a pause in between
+func openAllCmd(urls []string) tea.Cmd {
+	return func() tea.Msg {
+		failed := 0
+		for i, u := range urls {
+			if i > 0 {
+				time.Sleep(openAllDelay)
+			}
+			if err := openBrowser(u); err != nil {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return statusMsg(fmt.Sprintf("Couldn't open %d of %d in the browser", failed, len(urls)))
+		}
+		return statusMsg(fmt.Sprintf("Opened %d notifications in the browser", len(urls)))
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// Middleware wraps the transport every request of a Client goes through,
+// for logging, metrics, caching or extra headers. It sees requests with
+// the client's headers and session cookie already set.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripFunc lets a function be a transport, for writing middleware
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Use adds middleware to the client. Middleware added first is outermost:
+// it sees a request first and its response last.
+func (c *Client) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware, mw...)
+	// The default transport is looked up per request, so TLS settings
+	// applied after the client was made still count
+	var t http.RoundTripper = RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		t = c.middleware[i](t)
+	}
+	c.httpClient.Transport = t
+}
+
+// recordHealth times every request for the status bar's health indicator
+func recordHealth(h *apiHealth) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			h.record(resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+// withHeaders sets headers on every request, over the client's own
+func withHeaders(headers map[string]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			// A transport mustn't change the request it's given
+			req = req.Clone(req.Context())
+			for name, value := range headers {
+				req.Header.Set(name, value)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// logRequests logs each request's method, path, status and time, for
+// api_log in the config
+func logRequests(next http.RoundTripper) http.RoundTripper {
+	return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			log.Printf("api: %s %s: %v", req.Method, req.URL.Path, err)
+		} else {
+			log.Printf("api: %s %s: %d in %s", req.Method, req.URL.Path, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+		}
+		return resp, err
+	})
+}
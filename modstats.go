@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	modStatsWeeks = 8
+
+	// Stats are expensive to compute (every run verified in the window) and
+	// move slowly, so recompute at most this often unless asked to
+	modStatsTTL = time.Hour
+
+	week = 7 * 24 * time.Hour
+)
+
+// modWeekly is one examiner's verification count per week, oldest first
+type modWeekly struct {
+	Name  string `json:"name"`
+	Weeks []int  `json:"weeks"`
+}
+
+func (m modWeekly) total() int {
+	n := 0
+	for _, w := range m.Weeks {
+		n += w
+	}
+	return n
+}
+
+// modStats summarises moderation of one game over the last modStatsWeeks
+type modStats struct {
+	Game      Game          `json:"game"`
+	Computed  time.Time     `json:"computed"`
+	Mods      []modWeekly   `json:"mods"`
+	Verified  int           `json:"verified"`
+	Rejected  int           `json:"rejected"`
+	AvgVerify time.Duration `json:"avg_verify"`
+	// Queue length at the end of each week, oldest first, with the
+	// current length last
+	Queue []int `json:"queue"`
+}
+
+func (s modStats) RejectionRate() float64 {
+	if s.Verified+s.Rejected == 0 {
+		return 0
+	}
+	return float64(s.Rejected) / float64(s.Verified+s.Rejected)
+}
+
+// GetRejectedSince returns a game's rejected runs submitted after since.
+// v1 doesn't say when a run was rejected, so submission date stands in.
+func (c *Client) GetRejectedSince(gameID string, since time.Time) ([]v1Run, error) {
+	q := url.Values{
+		"game":      {gameID},
+		"status":    {"rejected"},
+		"orderby":   {"submitted"},
+		"direction": {"desc"},
+	}
+	runs, err := c.listRuns(q, func(r v1Run) bool {
+		submitted, err := time.Parse(time.RFC3339, r.Submitted)
+		return err != nil || !submitted.Before(since)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching rejected runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetModerators maps a game's moderators' user IDs to their names
+func (c *Client) GetModerators(gameID string) (map[string]string, error) {
+	var result struct {
+		Data struct {
+			Moderators struct {
+				Data []struct {
+					ID    string `json:"id"`
+					Names struct {
+						International string `json:"international"`
+					} `json:"names"`
+				} `json:"data"`
+			} `json:"moderators"`
+		} `json:"data"`
+	}
+	if err := c.getV1("/games/"+url.PathEscape(gameID)+"?embed=moderators", &result); err != nil {
+		return nil, fmt.Errorf("fetching moderators: %w", err)
+	}
+
+	names := make(map[string]string)
+	for _, u := range result.Data.Moderators.Data {
+		names[u.ID] = u.Names.International
+	}
+	return names, nil
+}
+
+// computeModStats works the numbers out from raw run data. Rejected runs
+// have no rejection date, so they count towards the rejection rate but
+// not the queue history.
+func computeModStats(game Game, verified, rejected []v1Run, pending []QueueRun, names map[string]string, now time.Time) modStats {
+	stats := modStats{
+		Game:     game,
+		Computed: now,
+		Verified: len(verified),
+		Rejected: len(rejected),
+		Queue:    make([]int, modStatsWeeks+1),
+	}
+
+	// weekIndex puts t in a column, 0 being the oldest week
+	weekIndex := func(t time.Time) int {
+		return modStatsWeeks - 1 - int(now.Sub(t)/week)
+	}
+
+	perMod := make(map[string][]int)
+	var waited time.Duration
+	var timed int
+	for _, r := range verified {
+		verifiedAt, err := time.Parse(time.RFC3339, r.Status.VerifyDate)
+		if err != nil {
+			continue
+		}
+		if i := weekIndex(verifiedAt); i >= 0 && i < modStatsWeeks {
+			if perMod[r.Status.Examiner] == nil {
+				perMod[r.Status.Examiner] = make([]int, modStatsWeeks)
+			}
+			perMod[r.Status.Examiner][i]++
+		}
+
+		submitted, err := time.Parse(time.RFC3339, r.Submitted)
+		if err != nil {
+			continue
+		}
+		waited += verifiedAt.Sub(submitted)
+		timed++
+
+		// In the queue from submission until verification
+		for i := range stats.Queue {
+			at := now.Add(-time.Duration(modStatsWeeks-i) * week)
+			if !submitted.After(at) && verifiedAt.After(at) {
+				stats.Queue[i]++
+			}
+		}
+	}
+	if timed > 0 {
+		stats.AvgVerify = waited / time.Duration(timed)
+	}
+
+	for _, r := range pending {
+		for i := range stats.Queue {
+			at := now.Add(-time.Duration(modStatsWeeks-i) * week)
+			if !r.Submitted.After(at) {
+				stats.Queue[i]++
+			}
+		}
+	}
+
+	for id, weeks := range perMod {
+		name := names[id]
+		if name == "" {
+			name = id
+		}
+		stats.Mods = append(stats.Mods, modWeekly{Name: name, Weeks: weeks})
+	}
+	sort.Slice(stats.Mods, func(i, j int) bool {
+		return stats.Mods[i].total() > stats.Mods[j].total()
+	})
+	return stats
+}
+
+type modStatsMsg struct {
+	stats []modStats
+	err   error
+}
+
+// loadModStats computes stats for every moderated game, reusing results
+// cached in the last modStatsTTL unless refresh is set.
+func loadModStats(client *Client, cache *GameCache, configured []string, refresh bool) modStatsMsg {
+	games, err := moderatedGames(client, cache, configured)
+	if err != nil {
+		return modStatsMsg{err: err}
+	}
+	disk, err := openCache("modstats")
+	if err != nil {
+		return modStatsMsg{err: err}
+	}
+
+	now := time.Now()
+	since := now.Add(-modStatsWeeks * week)
+	host := hostOf(client.baseURL)
+
+	// Raw run data per game; each job fills in one field
+	type input struct {
+		cached   bool
+		failed   bool
+		verified []v1Run
+		rejected []v1Run
+		pending  []QueueRun
+		names    map[string]string
+	}
+	inputs := make([]input, len(games))
+	results := make([]modStats, len(games))
+	var mu sync.Mutex
+
+	var jobs []Job
+	for i, g := range games {
+		if !refresh && disk.load(g.ID, modStatsTTL, &results[i]) {
+			inputs[i].cached = true
+			continue
+		}
+
+		in := &inputs[i]
+		job := func(name string, run func() error) Job {
+			return Job{Name: g.Name + " " + name, Host: host, Run: func() error {
+				err := run()
+				if err != nil {
+					mu.Lock()
+					in.failed = true
+					mu.Unlock()
+				}
+				return err
+			}}
+		}
+		jobs = append(jobs,
+			job("verified runs", func() (err error) {
+				in.verified, err = client.GetVerifiedSince(g.ID, since)
+				return err
+			}),
+			job("rejected runs", func() (err error) {
+				in.rejected, err = client.GetRejectedSince(g.ID, since)
+				return err
+			}),
+			job("pending runs", func() (err error) {
+				in.pending, err = client.GetPendingRuns(g.ID)
+				return err
+			}),
+			job("moderators", func() (err error) {
+				in.names, err = client.GetModerators(g.ID)
+				return err
+			}),
+		)
+	}
+	err = NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs)
+
+	for i, in := range inputs {
+		if in.cached || in.failed {
+			continue
+		}
+		results[i] = computeModStats(games[i], in.verified, in.rejected, in.pending, in.names, now)
+		disk.store(games[i].ID, results[i])
+	}
+
+	var stats []modStats
+	for _, s := range results {
+		if s.Game.ID != "" {
+			stats = append(stats, s)
+		}
+	}
+	return modStatsMsg{stats: stats, err: err}
+}
+
+func (q queueModel) loadStatsCmd(refresh bool) tea.Cmd {
+	client, cache, configured := q.client, q.games, q.configured
+	return func() tea.Msg {
+		return loadModStats(client, cache, configured, refresh)
+	}
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a row of block characters scaled to the max
+func sparkline(values []int) string {
+	top := 0
+	for _, v := range values {
+		top = max(top, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if top > 0 {
+			i = v * (len(sparkBlocks) - 1) / top
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// formatWait is a coarse duration for verification times: 3d 4h, 5h 12m
+func formatWait(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd %dh", int(d.Hours()/24), int(d.Hours())%24)
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func (q queueModel) statsView() string {
+	if q.statsLoading {
+		return "Computing stats..."
+	}
+
+	var b strings.Builder
+	if q.statsErr != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n\n", q.statsErr))
+	}
+
+	for _, s := range q.modStats {
+		b.WriteString(titleStyle.Render(s.Game.Name))
+		b.WriteString(urlStyle.Render(fmt.Sprintf("  last %d weeks, as of %s", modStatsWeeks, s.Computed.Local().Format("15:04"))))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("Average time to verify  %s\n", formatWait(s.AvgVerify)))
+		b.WriteString(fmt.Sprintf("Rejection rate          %.0f%% (%d of %d)\n",
+			s.RejectionRate()*100, s.Rejected, s.Verified+s.Rejected))
+		b.WriteString(fmt.Sprintf("Queue length            %s  now %d\n", sparkline(s.Queue), s.Queue[len(s.Queue)-1]))
+
+		b.WriteString("\nVerifications per week\n")
+		b.WriteString(fmt.Sprintf("%-20s", ""))
+		for i := range modStatsWeeks {
+			start := s.Computed.Add(-time.Duration(modStatsWeeks-i) * week)
+			b.WriteString(fmt.Sprintf("%6s", start.Format("01/02")))
+		}
+		b.WriteString("   total\n")
+		for _, mod := range s.Mods {
+			b.WriteString(fmt.Sprintf("%-20s", truncate(mod.Name, 19)))
+			for _, n := range mod.Weeks {
+				b.WriteString(fmt.Sprintf("%6d", n))
+			}
+			b.WriteString(fmt.Sprintf("%8d\n", mod.total()))
+		}
+		if len(s.Mods) == 0 {
+			b.WriteString("No verifications\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
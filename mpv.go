@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// mpv's JSON IPC lets the app ask a video it started where it's up to.
+// Players other than mpv play videos the same as before, without it.
+
+// mpvSocket is where mpv listens, one per app so two instances don't share
+var mpvSocket = filepath.Join(os.TempDir(), fmt.Sprintf("speedrunner-mpv-%d.sock", os.Getpid()))
+
+// withMPVIPC adds the IPC socket to an mpv command line
+func withMPVIPC(player []string) []string {
+	if !strings.HasPrefix(filepath.Base(player[0]), "mpv") {
+		return player
+	}
+	for _, arg := range player[1:] {
+		if strings.HasPrefix(arg, "--input-ipc-server") {
+			return player
+		}
+	}
+	return append(append([]string{}, player...), "--input-ipc-server="+mpvSocket)
+}
+
+// mpvCommand runs one IPC command against the playing video, returning its
+// data
+func mpvCommand(args ...any) (json.RawMessage, error) {
+	conn, err := net.DialTimeout("unix", mpvSocket, 2*time.Second)
+	if err != nil {
+		return nil, errors.New("no video is playing in mpv, start one with v")
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	request, err := json.Marshal(map[string]any{"command": args, "request_id": 1})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(request, '\n')); err != nil {
+		return nil, fmt.Errorf("talking to mpv: %w", err)
+	}
+
+	// Events arrive on the same socket, so skip to the reply
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var reply struct {
+			RequestID int             `json:"request_id"`
+			Error     string          `json:"error"`
+			Data      json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil || reply.RequestID != 1 {
+			continue
+		}
+		if reply.Error != "success" {
+			return nil, fmt.Errorf("mpv: %s", reply.Error)
+		}
+		return reply.Data, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("talking to mpv: %w", err)
+	}
+	return nil, errors.New("mpv closed without answering")
+}
+
+// mpvPosition is how far into the video mpv is
+func mpvPosition() (time.Duration, error) {
+	data, err := mpvCommand("get_property", "time-pos")
+	if err != nil {
+		return 0, err
+	}
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return 0, fmt.Errorf("decoding mpv position: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// mpvFPS is the playing video's frame rate
+func mpvFPS() (float64, error) {
+	data, err := mpvCommand("get_property", "container-fps")
+	if err != nil {
+		return 0, err
+	}
+	var fps float64
+	if err := json.Unmarshal(data, &fps); err != nil || fps <= 0 {
+		return 0, errors.New("mpv doesn't know the video's frame rate")
+	}
+	return fps, nil
+}
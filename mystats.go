@@ -0,0 +1,367 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// Months of history the dashboard charts
+	statsMonths = 12
+
+	statsBarWidth = 30
+
+	// Boards listed under biggest improvements
+	statsTopBoards = 5
+)
+
+// GetUserRuns returns every run a user has submitted, whatever became of
+// it, oldest first
+func (c *Client) GetUserRuns(userID string) ([]v1Run, error) {
+	q := url.Values{
+		"user":      {userID},
+		"orderby":   {"submitted"},
+		"direction": {"asc"},
+	}
+	runs, err := c.listRuns(q, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching your runs: %w", err)
+	}
+	return runs, nil
+}
+
+// statsMonth is one row of the dashboard
+type statsMonth struct {
+	Month     time.Time
+	Submitted int
+	PBs       int
+}
+
+// boardProgress is how far a runner has come on one board
+type boardProgress struct {
+	Game     string // abbreviation, from the run's link
+	Category string // ID
+	First    time.Duration
+	Best     time.Duration
+	PBs      int // improvements after the first run
+}
+
+// personalStats is the dashboard, worked out from a user's runs
+type personalStats struct {
+	Submitted, Verified, Rejected, Pending int
+
+	MedianWait, AverageWait, LongestWait time.Duration
+
+	Months []statsMonth // oldest first, ending with the current month
+
+	// Consecutive weeks with a submission, the current run ending this
+	// week or last
+	Streak, LongestStreak int
+
+	Boards []boardProgress // most time saved first
+}
+
+// weekStart is the Monday starting t's week
+func weekStart(t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+}
+
+// runBoard identifies the board a run is on, variables included
+func runBoard(r v1Run) string {
+	keys := make([]string, 0, len(r.Values))
+	for k, v := range r.Values {
+		keys = append(keys, k+"="+v)
+	}
+	slices.Sort(keys)
+	return r.Game + "/" + r.Category + "/" + r.Level + "/" + strings.Join(keys, ",")
+}
+
+func computePersonalStats(runs []v1Run, now time.Time) personalStats {
+	var s personalStats
+	s.Submitted = len(runs)
+
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthIndex := make(map[time.Time]int, statsMonths)
+	for i := range statsMonths {
+		month := thisMonth.AddDate(0, i-statsMonths+1, 0)
+		monthIndex[month] = i
+		s.Months = append(s.Months, statsMonth{Month: month})
+	}
+	month := func(t time.Time) (int, bool) {
+		t = t.In(now.Location())
+		i, ok := monthIndex[time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, now.Location())]
+		return i, ok
+	}
+
+	var (
+		waits    []time.Duration
+		weeks    = make(map[time.Time]bool)
+		verified []v1Run
+	)
+	for _, r := range runs {
+		submitted, err := time.Parse(time.RFC3339, r.Submitted)
+		if err == nil {
+			weeks[weekStart(submitted.In(now.Location()))] = true
+			if i, ok := month(submitted); ok {
+				s.Months[i].Submitted++
+			}
+		}
+		switch r.Status.Status {
+		case "verified":
+			s.Verified++
+			verified = append(verified, r)
+			if at, err2 := time.Parse(time.RFC3339, r.Status.VerifyDate); err == nil && err2 == nil && at.After(submitted) {
+				waits = append(waits, at.Sub(submitted))
+			}
+		case "rejected":
+			s.Rejected++
+		case "new":
+			s.Pending++
+		}
+	}
+
+	if len(waits) > 0 {
+		slices.Sort(waits)
+		var total time.Duration
+		for _, w := range waits {
+			total += w
+		}
+		s.MedianWait = waits[len(waits)/2]
+		s.AverageWait = total / time.Duration(len(waits))
+		s.LongestWait = waits[len(waits)-1]
+	}
+
+	// Streaks run back from this week, or from last week when nothing has
+	// been submitted yet this week
+	week := weekStart(now)
+	if !weeks[week] {
+		week = week.AddDate(0, 0, -7)
+	}
+	for weeks[week] {
+		s.Streak++
+		week = week.AddDate(0, 0, -7)
+	}
+	starts := make([]time.Time, 0, len(weeks))
+	for w := range weeks {
+		starts = append(starts, w)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	for i, current := 0, 0; i < len(starts); i++ {
+		if i > 0 && starts[i].Sub(starts[i-1]) <= 8*24*time.Hour {
+			current++
+		} else {
+			current = 1
+		}
+		s.LongestStreak = max(s.LongestStreak, current)
+	}
+
+	// PBs are verified runs faster than the runner's best on the board
+	// at the time they were played
+	sort.SliceStable(verified, func(i, j int) bool { return verified[i].Date < verified[j].Date })
+	boards := make(map[string]*boardProgress)
+	var order []string
+	for _, r := range verified {
+		t := r.queueRun().Time
+		if t <= 0 {
+			continue
+		}
+		key := runBoard(r)
+		b, ok := boards[key]
+		if !ok {
+			boards[key] = &boardProgress{Game: resolveLink(r.Weblink).Game, Category: r.Category, First: t, Best: t}
+			order = append(order, key)
+			continue
+		}
+		if t >= b.Best {
+			continue
+		}
+		b.Best = t
+		b.PBs++
+		if played, err := time.ParseInLocation(time.DateOnly, r.Date, now.Location()); err == nil {
+			if i, ok := month(played); ok {
+				s.Months[i].PBs++
+			}
+		}
+	}
+	for _, key := range order {
+		if b := boards[key]; b.PBs > 0 {
+			s.Boards = append(s.Boards, *b)
+		}
+	}
+	sort.SliceStable(s.Boards, func(i, j int) bool {
+		return s.Boards[i].First-s.Boards[i].Best > s.Boards[j].First-s.Boards[j].Best
+	})
+	return s
+}
+
+type personalStatsMsg struct {
+	stats personalStats
+	names map[string]string // "game/category" -> "Game Category"
+	milli map[string]bool   // game abbreviation -> times in milliseconds
+	err   error
+}
+
+func loadPersonalStatsCmd(client *Client, games *GameCache) tea.Cmd {
+	return func() tea.Msg {
+		session, err := client.GetSession()
+		if err != nil {
+			return personalStatsMsg{err: err}
+		}
+		if !session.SignedIn || session.User == nil {
+			return personalStatsMsg{err: errNoSession}
+		}
+		runs, err := client.GetUserRuns(session.User.ID)
+		if err != nil {
+			return personalStatsMsg{err: err}
+		}
+
+		msg := personalStatsMsg{
+			stats: computePersonalStats(runs, time.Now()),
+			names: make(map[string]string),
+			milli: make(map[string]bool),
+		}
+		// Names only for the boards that get listed
+		for _, b := range msg.stats.Boards[:min(len(msg.stats.Boards), statsTopBoards)] {
+			if games == nil || b.Game == "" {
+				continue
+			}
+			data, err := games.Get(b.Game)
+			if err != nil {
+				continue
+			}
+			msg.milli[b.Game] = data.Game.Milliseconds
+			for _, c := range data.Categories {
+				if c.ID == b.Category {
+					msg.names[b.Game+"/"+b.Category] = data.Game.Name + " " + c.Name
+				}
+			}
+		}
+		return msg
+	}
+}
+
+// statsModel is the Stats tab: a dashboard of your own running
+type statsModel struct {
+	client *Client
+	games  *GameCache
+	times  TimeFormat
+
+	stats   personalStats
+	names   map[string]string
+	milli   map[string]bool
+	loaded  bool
+	loading bool
+	err     error
+}
+
+func newStatsModel(client *Client, cfg Config) statsModel {
+	s := statsModel{client: client, times: cfg.TimeFormat}
+	// Without the cache boards show by abbreviation
+	s.games, _ = NewGameCache(client)
+	return s
+}
+
+func (s statsModel) activate() (statsModel, tea.Cmd) {
+	if s.loaded || s.loading {
+		return s, nil
+	}
+	s.loading = true
+	return s, loadPersonalStatsCmd(s.client, s.games)
+}
+
+func (s statsModel) update(msg tea.Msg) (statsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case personalStatsMsg:
+		s.loading = false
+		s.loaded = true
+		s.stats, s.names, s.milli, s.err = msg.stats, msg.names, msg.milli, msg.err
+	case tea.KeyMsg:
+		if msg.String() == "r" && !s.loading {
+			s.loading = true
+			return s, loadPersonalStatsCmd(s.client, s.games)
+		}
+	}
+	return s, nil
+}
+
+func (s statsModel) view() string {
+	if s.loading {
+		return "Going through your runs..."
+	}
+	if s.err != nil {
+		return fmt.Sprintf("Error: %v", s.err)
+	}
+	if !s.loaded {
+		return ""
+	}
+	st := s.stats
+	if st.Submitted == 0 {
+		return "No runs submitted yet"
+	}
+
+	var b strings.Builder
+	weeks := func(n int) string {
+		if n == 1 {
+			return "1 week"
+		}
+		return fmt.Sprintf("%d weeks", n)
+	}
+	fmt.Fprintf(&b, "%-14s %s %s\n", "Active streak", weeks(st.Streak), urlStyle.Render("(longest "+weeks(st.LongestStreak)+")"))
+	fmt.Fprintf(&b, "%-14s %d submitted • %d verified • %d rejected • %d pending\n", "Runs", st.Submitted, st.Verified, st.Rejected, st.Pending)
+	if st.Verified > 0 {
+		fmt.Fprintf(&b, "%-14s median %s • average %s • longest %s\n", "Verification",
+			formatWait(st.MedianWait), formatWait(st.AverageWait), formatWait(st.LongestWait))
+	}
+
+	b.WriteString("\n" + titleStyle.Render("Last 12 months") + "\n")
+	top := 1
+	for _, m := range st.Months {
+		top = max(top, m.Submitted)
+	}
+	for _, m := range st.Months {
+		width := m.Submitted * statsBarWidth / top
+		if m.Submitted > 0 {
+			width = max(width, 1)
+		}
+		pbs := ""
+		if m.PBs > 0 {
+			pbs = fmt.Sprintf("  ▲ %d PB", m.PBs)
+			if m.PBs > 1 {
+				pbs += "s"
+			}
+		}
+		fmt.Fprintf(&b, "  %s  %s%s %2d%s\n", m.Month.Format("Jan 2006"),
+			progressFullStyle.Render(strings.Repeat("█", width)), strings.Repeat(" ", statsBarWidth-width), m.Submitted, pbs)
+	}
+
+	b.WriteString("\n" + titleStyle.Render("Biggest improvements") + "\n")
+	if len(st.Boards) == 0 {
+		b.WriteString(urlStyle.Render("  no PBs beaten yet") + "\n")
+	}
+	for _, p := range st.Boards[:min(len(st.Boards), statsTopBoards)] {
+		name := s.names[p.Game+"/"+p.Category]
+		if name == "" {
+			name = p.Game
+		}
+		millis := s.milli[p.Game]
+		pbs := fmt.Sprintf("%d PBs", p.PBs)
+		if p.PBs == 1 {
+			pbs = "1 PB"
+		}
+		fmt.Fprintf(&b, "  %-40s %s → %s  %s, %s\n", truncate(name, 40),
+			s.times.render(p.First, millis), s.times.render(p.Best, millis),
+			urlStyle.Render("-"+s.times.render(p.First-p.Best, millis)), pbs)
+	}
+	return b.String()
+}
+
+func (s statsModel) help() string {
+	return "r refresh"
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notification types we have a dedicated rendering for. Anything else gets
+// the generic fallback card so a new type on the site never disappears.
+var notificationTypeLabels = map[string]string{
+	"run_verified":     "Run verified",
+	"run_rejected":     "Run rejected",
+	"run_comment":      "Run comment",
+	"forum_reply":      "Forum reply",
+	"thread_reply":     "Thread reply",
+	"new_follower":     "New follower",
+	"moderator_invite": "Moderator invite",
+	"game_request":     "Game request",
+	"record_beaten":    "Record beaten",
+	"mention":          "Mention",
+}
+
+// Known reports whether n can be rendered with the regular card. An empty
+// type is treated as known since older responses don't include one.
+func (n Notification) Known() bool {
+	if n.Type == "" {
+		return n.Title != ""
+	}
+	_, ok := notificationTypeLabels[n.Type]
+	return ok && n.Title != ""
+}
+
+// UnmarshalJSON decodes a notification field by field so that a renamed,
+// retyped or brand new field can't fail the whole response. Fields we don't
+// know about are kept in Extra and mismatches are logged.
+func (n *Notification) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*n = Notification{}
+	for key, raw := range fields {
+		var err error
+		switch key {
+		case "id":
+			n.ID, err = decodeLooseString(raw)
+		case "title":
+			n.Title, err = decodeLooseString(raw)
+		case "path":
+			n.Path, err = decodeLooseString(raw)
+		case "type":
+			n.Type, err = decodeLooseString(raw)
+		case "read":
+			n.Read, err = decodeLooseBool(raw)
+		case "date":
+			n.Date, err = decodeLooseTime(raw)
+		case "account":
+			n.Account, err = decodeLooseString(raw)
+		default:
+			if n.Extra == nil {
+				n.Extra = make(map[string]json.RawMessage)
+			}
+			n.Extra[key] = raw
+			logOnce("notification.unknown."+key, "schema: unknown notification field %q: %s", key, raw)
+			continue
+		}
+		if err != nil {
+			logOnce("notification.mismatch."+key, "schema: notification field %q: %v", key, err)
+		}
+	}
+
+	if n.Type != "" {
+		if _, ok := notificationTypeLabels[n.Type]; !ok {
+			logOnce("notification.type."+n.Type, "schema: unrecognized notification type %q", n.Type)
+		}
+	}
+
+	return nil
+}
+
+func decodeLooseString(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(raw, &num); err == nil {
+		return num.String(), nil
+	}
+	if string(raw) == "null" {
+		return "", nil
+	}
+	return "", fmt.Errorf("expected string, got %s", raw)
+}
+
+func decodeLooseBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var num float64
+	if err := json.Unmarshal(raw, &num); err == nil {
+		return num != 0, nil
+	}
+	return false, fmt.Errorf("expected bool, got %s", raw)
+}
+
+// decodeLooseTime accepts unix seconds as a number or string, or RFC 3339
+func decodeLooseTime(raw json.RawMessage) (int64, error) {
+	var num float64
+	if err := json.Unmarshal(raw, &num); err == nil {
+		return int64(num), nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, fmt.Errorf("expected timestamp, got %s", raw)
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return secs, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("expected timestamp, got %s", raw)
+	}
+	return t.Unix(), nil
+}
+
+// renderFallbackNotification shows whatever we could decode from a
+// notification we don't fully understand, rather than hiding it.
+func renderFallbackNotification(n Notification, keywords *keywordHighlighter) string {
+	var b strings.Builder
+
+	kind := "unknown type"
+	if n.Type != "" {
+		kind = fmt.Sprintf("unknown type %q", n.Type)
+	}
+	date := "unknown date"
+	if n.Date != 0 {
+		date = time.Unix(n.Date, 0).Format("2006-01-02 15:04:05")
+	}
+	b.WriteString(fmt.Sprintf("[%s] %s • %s\n", unknownDotStyle.String(), date, kind))
+
+	title := n.Title
+	if title == "" {
+		title = "(untitled notification)"
+	}
+	b.WriteString(keywords.highlight(title))
+
+	if n.Path != "" {
+		b.WriteString("\n")
+		b.WriteString(urlStyle.Render(fmt.Sprintf("speedrun.com%s", n.Path)))
+	}
+
+	if len(n.Extra) > 0 {
+		keys := make([]string, 0, len(n.Extra))
+		for k := range n.Extra {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			v := string(n.Extra[k])
+			if runes := []rune(v); len(runes) > 40 {
+				v = string(runes[:37]) + "..."
+			}
+			pairs[i] = k + "=" + v
+		}
+		b.WriteString("\n")
+		b.WriteString(urlStyle.Render(strings.Join(pairs, " ")))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// OBS's websocket server (Tools > WebSocket Server Settings, built in
+// since OBS 28) takes JSON messages tagged with an op code. The run's
+// video is the recording, so the run is over when the recording stops.
+// OBS knows nothing of splits, only the final time.
+
+const defaultOBSAddr = "localhost:4455"
+
+// Op codes of the OBS websocket protocol, version 5
+const (
+	obsOpHello      = 0
+	obsOpIdentify   = 1
+	obsOpIdentified = 2
+	obsOpRequest    = 6
+	obsOpResponse   = 7
+)
+
+// OBS closes the connection with this code on a wrong password
+const obsAuthFailed = 4009
+
+// OBSConfig is where to find OBS and what to read the time from
+type OBSConfig struct {
+	Addr     string `json:"addr,omitempty"` // localhost:4455 when empty
+	Password string `json:"password,omitempty"`
+	// A text source showing the run's timer, whose last time is the
+	// final one. Without it the recording's length is.
+	TimerSource string `json:"timer_source,omitempty"`
+}
+
+func (c OBSConfig) addr() string {
+	if c.Addr == "" {
+		return defaultOBSAddr
+	}
+	return c.Addr
+}
+
+// OBSClient talks to OBS's websocket server
+type OBSClient struct {
+	conn *websocket.Conn
+	next int // last request ID
+}
+
+type obsMessage struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d"`
+}
+
+func DialOBS(c OBSConfig) (*OBSClient, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: 3 * time.Second}
+	conn, _, err := dialer.Dial("ws://"+c.addr(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to OBS at %s (is the websocket server on?): %w", c.addr(), err)
+	}
+	o := &OBSClient{conn: conn}
+	if err := o.identify(c.Password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *OBSClient) Close() error {
+	return o.conn.Close()
+}
+
+// send writes one message with the given op code
+func (o *OBSClient) send(op int, d any) error {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	o.conn.SetWriteDeadline(time.Now().Add(3 * time.Second))
+	return o.conn.WriteJSON(obsMessage{Op: op, D: raw})
+}
+
+// read waits for the next message with the given op code, passing over
+// any other
+func (o *OBSClient) read(op int, v any) error {
+	o.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	for {
+		var msg obsMessage
+		if err := o.conn.ReadJSON(&msg); err != nil {
+			return fmt.Errorf("reading from OBS: %w", err)
+		}
+		if msg.Op == op {
+			return json.Unmarshal(msg.D, v)
+		}
+	}
+}
+
+// identify answers OBS's hello, with the password when it asks for one
+func (o *OBSClient) identify(password string) error {
+	var hello struct {
+		Authentication *struct {
+			Challenge string `json:"challenge"`
+			Salt      string `json:"salt"`
+		} `json:"authentication"`
+	}
+	if err := o.read(obsOpHello, &hello); err != nil {
+		return err
+	}
+
+	identify := struct {
+		RPCVersion         int    `json:"rpcVersion"`
+		Authentication     string `json:"authentication,omitempty"`
+		EventSubscriptions int    `json:"eventSubscriptions"`
+	}{
+		RPCVersion: 1,
+	}
+	if a := hello.Authentication; a != nil {
+		if password == "" {
+			return errors.New("OBS asks for a password, set obs.password in the config")
+		}
+		secret := sha256.Sum256([]byte(password + a.Salt))
+		auth := sha256.Sum256([]byte(base64.StdEncoding.EncodeToString(secret[:]) + a.Challenge))
+		identify.Authentication = base64.StdEncoding.EncodeToString(auth[:])
+	}
+	if err := o.send(obsOpIdentify, identify); err != nil {
+		return fmt.Errorf("identifying to OBS: %w", err)
+	}
+
+	var identified struct{}
+	err := o.read(obsOpIdentified, &identified)
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && closeErr.Code == obsAuthFailed {
+		return errors.New("OBS turned down the password in obs.password")
+	}
+	return err
+}
+
+// request makes one request and decodes its response data into out
+func (o *OBSClient) request(requestType string, data, out any) error {
+	o.next++
+	id := strconv.Itoa(o.next)
+	req := struct {
+		RequestType string `json:"requestType"`
+		RequestID   string `json:"requestId"`
+		RequestData any    `json:"requestData,omitempty"`
+	}{
+		RequestType: requestType,
+		RequestID:   id,
+		RequestData: data,
+	}
+	if err := o.send(obsOpRequest, req); err != nil {
+		return fmt.Errorf("sending %s: %w", requestType, err)
+	}
+
+	var resp struct {
+		RequestID string `json:"requestId"`
+		Status    struct {
+			Result  bool   `json:"result"`
+			Code    int    `json:"code"`
+			Comment string `json:"comment"`
+		} `json:"requestStatus"`
+		Data json.RawMessage `json:"responseData"`
+	}
+	for resp.RequestID != id {
+		if err := o.read(obsOpResponse, &resp); err != nil {
+			return err
+		}
+	}
+	if !resp.Status.Result {
+		return fmt.Errorf("%s: %s (code %d)", requestType, resp.Status.Comment, resp.Status.Code)
+	}
+	if out == nil || len(resp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+// sourceText reads what a text source shows
+func (o *OBSClient) sourceText(source string) (string, error) {
+	var input struct {
+		Settings struct {
+			Text string `json:"text"`
+		} `json:"inputSettings"`
+	}
+	body := struct {
+		InputName string `json:"inputName"`
+	}{
+		InputName: source,
+	}
+	if err := o.request("GetInputSettings", body, &input); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(input.Settings.Text), nil
+}
+
+// WatchRecording waits for a recording to start, if one hasn't, and then
+// to stop. The final time is the timer source's last readable time, or
+// the recording's length without one.
+func (o *OBSClient) WatchRecording(timerSource string, interval time.Duration) (*LiveSplitRun, error) {
+	var recorded, timed time.Duration
+	started := false
+	for {
+		var status struct {
+			Active   bool    `json:"outputActive"`
+			Duration float64 `json:"outputDuration"` // milliseconds
+		}
+		if err := o.request("GetRecordStatus", nil, &status); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case status.Active:
+			started = true
+			recorded = time.Duration(status.Duration) * time.Millisecond
+			if timerSource == "" {
+				break
+			}
+			text, err := o.sourceText(timerSource)
+			if err != nil {
+				return nil, err
+			}
+			// Whatever the timer shows before it starts isn't a time
+			if d, err := parseRunTime(text); err == nil {
+				timed = d
+			}
+		case started:
+			if timed > 0 {
+				return &LiveSplitRun{Final: timed}, nil
+			}
+			return &LiveSplitRun{Final: recorded}, nil
+		}
+
+		time.Sleep(interval)
+	}
+}
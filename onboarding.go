@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Steps of the first run setup, in order
+const (
+	setupAuth = iota
+	setupSession
+	setupTheme
+	setupPoll
+	setupGames
+	setupDone
+)
+
+type setupSessionMsg struct {
+	user *SessionUser
+	err  error
+}
+
+type setupGamesMsg struct {
+	games []string
+	err   error
+}
+
+// onboarding is the guided setup shown on the first launch, when there's
+// no config file yet. Nothing is written until the last step.
+type onboarding struct {
+	apiBase string
+	step    int
+	choice  int // in the auth and theme menus
+	input   textinput.Model
+	checked string // session that failed the check, kept if enter is pressed again
+	err     error
+	busy    bool
+
+	storeSession bool
+	session      string
+	user         *SessionUser
+	gameThemes   bool
+	pollInterval string
+	games        string
+	saved        bool
+}
+
+func newOnboarding(apiBase, session string) onboarding {
+	input := textinput.New()
+	input.Width = 60
+	input.CharLimit = 200
+	return onboarding{apiBase: apiBase, session: session, pollInterval: "2m", input: input}
+}
+
+// configExists reports whether a config file has been written, by hand or
+// by the setup
+func configExists() bool {
+	path, err := configPath()
+	if err != nil {
+		return true
+	}
+	_, err = os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// runOnboarding runs the setup. It reports whether a config was written;
+// quitting early leaves things as they were.
+func runOnboarding(apiBase, session string) (bool, error) {
+	final, err := tea.NewProgram(newOnboarding(apiBase, session), tea.WithAltScreen()).Run()
+	if err != nil {
+		return false, err
+	}
+	return final.(onboarding).saved, nil
+}
+
+func (o onboarding) Init() tea.Cmd {
+	return nil
+}
+
+func checkSessionCmd(apiBase, session string) tea.Cmd {
+	return func() tea.Msg {
+		s, err := NewClient(apiBase, session).GetSession()
+		if err == nil && (!s.SignedIn || s.User == nil) {
+			err = errors.New("the site says this session isn't signed in")
+		}
+		if err != nil {
+			return setupSessionMsg{err: err}
+		}
+		return setupSessionMsg{user: s.User}
+	}
+}
+
+// userGamesCmd suggests the games the user has runs in
+func userGamesCmd(apiBase, session, userID string) tea.Cmd {
+	return func() tea.Msg {
+		ul, err := NewClient(apiBase, session).GetUserLeaderboard(userID)
+		if err != nil {
+			return setupGamesMsg{err: err}
+		}
+		var games []string
+		for _, g := range ul.Games {
+			games = append(games, g.URL)
+		}
+		return setupGamesMsg{games: games}
+	}
+}
+
+// enter moves to a step, setting up its input
+func (o onboarding) enter(step int) (onboarding, tea.Cmd) {
+	o.step, o.err, o.choice, o.busy = step, nil, 0, false
+	o.input.Blur()
+	o.input.EchoMode = textinput.EchoNormal
+	switch step {
+	case setupSession:
+		o.input.Placeholder = "PHPSESSID"
+		o.input.EchoMode = textinput.EchoPassword
+		o.input.SetValue(o.session)
+	case setupTheme:
+		if !o.gameThemes {
+			o.choice = 1
+		}
+		return o, nil
+	case setupPoll:
+		o.input.Placeholder = "e.g. 2m"
+		o.input.SetValue(o.pollInterval)
+	case setupGames:
+		o.input.Placeholder = "game abbreviations as in site URLs, e.g. sm64 celeste"
+		o.input.SetValue(o.games)
+		if o.games == "" && o.user != nil {
+			o.busy = true
+			return o, tea.Batch(o.input.Focus(), userGamesCmd(o.apiBase, o.session, o.user.ID))
+		}
+	default:
+		return o, nil
+	}
+	o.input.CursorEnd()
+	return o, o.input.Focus()
+}
+
+func (o onboarding) back() (onboarding, tea.Cmd) {
+	switch o.step {
+	case setupAuth:
+		return o, tea.Quit
+	case setupTheme:
+		if !o.storeSession {
+			return o.enter(setupAuth)
+		}
+	}
+	return o.enter(o.step - 1)
+}
+
+func (o onboarding) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case setupSessionMsg:
+		if o.step != setupSession || !o.busy {
+			return o, nil
+		}
+		o.busy = false
+		if msg.err != nil {
+			o.err, o.checked = msg.err, o.session
+			return o, nil
+		}
+		o.user = msg.user
+		return o.enter(setupTheme)
+
+	case setupGamesMsg:
+		o.busy = false
+		// A failed lookup just means typing them in
+		if msg.err == nil && o.step == setupGames && o.input.Value() == "" {
+			o.games = strings.Join(msg.games, " ")
+			o.input.SetValue(o.games)
+			o.input.CursorEnd()
+		}
+		return o, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return o, tea.Quit
+		case "esc":
+			return o.back()
+		}
+		if o.busy && o.step == setupSession {
+			return o, nil
+		}
+		switch o.step {
+		case setupAuth, setupTheme:
+			return o.updateMenu(msg)
+		case setupDone:
+			if msg.String() == "enter" {
+				if err := o.save(); err != nil {
+					o.err = err
+					return o, nil
+				}
+				o.saved = true
+				return o, tea.Quit
+			}
+			return o, nil
+		}
+		if msg.String() == "enter" {
+			return o.submit()
+		}
+		var cmd tea.Cmd
+		o.input, cmd = o.input.Update(msg)
+		return o, cmd
+	}
+	return o, nil
+}
+
+func (o onboarding) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		o.choice = 0
+	case "down", "j":
+		o.choice = 1
+	case "enter":
+		if o.step == setupAuth {
+			o.storeSession = o.choice == 0
+			if o.storeSession {
+				return o.enter(setupSession)
+			}
+			return o.enter(setupTheme)
+		}
+		o.gameThemes = o.choice == 0
+		return o.enter(setupPoll)
+	}
+	return o, nil
+}
+
+// submit checks what was typed on a text step and moves on
+func (o onboarding) submit() (tea.Model, tea.Cmd) {
+	value := strings.TrimSpace(o.input.Value())
+	switch o.step {
+	case setupSession:
+		if value == "" {
+			return o, nil
+		}
+		// A session that couldn't be checked, say offline, can still be
+		// kept by pressing enter again
+		if value == o.checked {
+			o.session = value
+			return o.enter(setupTheme)
+		}
+		o.session, o.busy, o.err = value, true, nil
+		return o, checkSessionCmd(o.apiBase, value)
+	case setupPoll:
+		if value == "" {
+			value = "2m"
+		}
+		if _, err := (Config{PollInterval: value}).pollInterval(); err != nil {
+			o.err = err
+			return o, nil
+		}
+		o.pollInterval = value
+		return o.enter(setupGames)
+	case setupGames:
+		o.games = strings.Join(strings.Fields(strings.ReplaceAll(value, ",", " ")), " ")
+		return o.enter(setupDone)
+	}
+	return o, nil
+}
+
+// fields is the config the setup writes, leaving defaults out
+func (o onboarding) fields() map[string]any {
+	fields := map[string]any{}
+	if o.storeSession && o.session != "" {
+		fields["session"] = o.session
+	}
+	if o.gameThemes {
+		fields["game_themes"] = true
+	}
+	if d, _ := time.ParseDuration(o.pollInterval); d != defaultPollInterval {
+		fields["poll_interval"] = o.pollInterval
+	}
+	if o.games != "" {
+		fields["followed_games"] = strings.Fields(o.games)
+	}
+	return fields
+}
+
+func (o onboarding) save() error {
+	if err := saveState(configFile, o.fields()); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+func (o onboarding) menu(options ...string) string {
+	var b strings.Builder
+	for i, opt := range options {
+		if i == o.choice {
+			b.WriteString(selectedItemStyle.Render("> "+opt) + "\n")
+		} else {
+			b.WriteString(unselectedItemStyle.Render("  "+opt) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (o onboarding) View() string {
+	var body, help string
+	switch o.step {
+	case setupAuth:
+		body = "Welcome! The app signs in to speedrun.com with your browser's PHPSESSID cookie.\n" +
+			urlStyle.Render("Find it in the network tab of the browser's dev tools, on the GetNotifications request.") + "\n\n" +
+			o.menu("Save the cookie in the config", "Pass it with -session each time")
+		help = "↑/↓ choose • enter next • esc quit"
+	case setupSession:
+		body = "Paste your PHPSESSID:\n\n" + o.input.View()
+		if o.busy {
+			body += "\n\nChecking..."
+		}
+		help = "enter check • esc back"
+	case setupTheme:
+		if o.user != nil {
+			body = okStyle.Render("Signed in as "+o.user.Name) + "\n\n"
+		}
+		body += "Tint the app with a game's colors while browsing its boards?\n\n" +
+			o.menu("Use game colors", "Keep the default colors")
+		help = "↑/↓ choose • enter next • esc back"
+	case setupPoll:
+		body = "How often should the background daemon check for notifications?\n\n" + o.input.View()
+		help = "enter next • esc back"
+	case setupGames:
+		body = "Games to follow on the Boards and Races tabs, space separated:\n\n" + o.input.View()
+		if o.busy {
+			body += "\n\nLooking up your games..."
+		}
+		help = "enter next • esc back"
+	case setupDone:
+		path, _ := configPath()
+		raw, _ := json.MarshalIndent(o.fields(), "", "  ")
+		body = "This will be written to " + path + ":\n\n" + string(raw)
+		help = "enter save • esc back"
+	}
+	if o.err != nil {
+		body += "\n\n" + failStyle.Render(o.err.Error())
+		if o.step == setupSession && o.checked != "" {
+			body += "\n" + urlStyle.Render("Press enter again to keep it anyway.")
+		}
+	}
+
+	header := titleStyle.Render(fmt.Sprintf("SETUP %d/%d", o.step+1, setupDone+1))
+	statusBar := statusBarStyle.Render(help + " • ctrl+c skip setup")
+	return appStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, "", body, "", statusBar))
+}
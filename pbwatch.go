@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	pbRanksFile     = "pb_ranks.json"
+	pbCheckInterval = 15 * time.Minute
+
+	// Deepest page we'll walk to find the runs that passed a PB
+	maxPasserPages = 10
+)
+
+// Passer is a run that moved ahead of one of the user's PBs
+type Passer struct {
+	Name   string
+	Place  int
+	Margin time.Duration // how much faster than the PB
+}
+
+// PBAlert reports that one of the user's PBs lost rank since the last check
+type PBAlert struct {
+	Board    string
+	URL      string
+	OldPlace int
+	NewPlace int
+	Passed   []Passer
+}
+
+func (a PBAlert) String() string {
+	s := fmt.Sprintf("▼ %s: #%d → #%d", a.Board, a.OldPlace, a.NewPlace)
+	if len(a.Passed) > 0 {
+		s += " • " + a.passers()
+	}
+	return s
+}
+
+func (a PBAlert) passers() string {
+	parts := make([]string, len(a.Passed))
+	for i, p := range a.Passed {
+		parts[i] = fmt.Sprintf("%s beat you by %s", p.Name, formatRunTime(p.Margin))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (a PBAlert) Alert() Alert {
+	body := a.Board
+	if len(a.Passed) > 0 {
+		body += "\n" + a.passers()
+	}
+	return Alert{
+		Title: fmt.Sprintf("You dropped from #%d to #%d", a.OldPlace, a.NewPlace),
+		Body:  body,
+		URL:   a.URL,
+		Game:  resolveLink(a.URL).Game,
+	}
+}
+
+// checkPBs compares the user's PB ranks against the snapshot from the last
+// check and reports every PB that dropped. The first check only records
+// a snapshot.
+func checkPBs(client *Client) ([]PBAlert, error) {
+	session, err := client.GetSession()
+	if err != nil {
+		return nil, err
+	}
+	if !session.SignedIn || session.User == nil {
+		return nil, errors.New("session is not signed in")
+	}
+
+	pbs, err := client.GetUserLeaderboard(session.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make(map[string]int)
+	if err := loadState(pbRanksFile, &previous); err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]int)
+	var alerts []PBAlert
+	var dropped []Run
+	for _, r := range pbs.Runs {
+		if r.Obsolete || r.Place == 0 {
+			continue
+		}
+		current[r.ID] = r.Place
+		if old, ok := previous[r.ID]; ok && r.Place > old {
+			dropped = append(dropped, r)
+			alerts = append(alerts, PBAlert{
+				Board:    pbs.BoardName(r),
+				URL:      pbs.RunURL(r),
+				OldPlace: old,
+				NewPlace: r.Place,
+			})
+		}
+	}
+
+	// Look up who passed us on each board that changed
+	jobs := make([]Job, len(dropped))
+	for i, r := range dropped {
+		jobs[i] = Job{
+			Name: alerts[i].Board,
+			Host: hostOf(client.baseURL),
+			Run: func() error {
+				passed, err := findPassers(client, pbs.BoardParams(r), r, alerts[i].OldPlace)
+				alerts[i].Passed = passed
+				return err
+			},
+		}
+	}
+	if err := NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs); err != nil {
+		// The rank change is still worth reporting without the names
+		log.Printf("pb watch: %v", err)
+	}
+
+	if err := saveState(pbRanksFile, current); err != nil {
+		return alerts, err
+	}
+	return alerts, nil
+}
+
+// findPassers returns the runs now ranked between the PB's old place and
+// its new one, i.e. the ones that pushed it down.
+func findPassers(client *Client, params LeaderboardParams, pb Run, oldPlace int) ([]Passer, error) {
+	var passers []Passer
+	for page := 1; page <= maxPasserPages; page++ {
+		lb, err := client.GetLeaderboard(params, page)
+		if err != nil {
+			return passers, err
+		}
+
+		for _, r := range lb.Runs {
+			if r.ID == pb.ID || r.Place < oldPlace || r.Place >= pb.Place {
+				continue
+			}
+			passers = append(passers, Passer{
+				Name:   lb.PlayerNames(r),
+				Place:  r.Place,
+				Margin: pb.Duration() - r.Duration(),
+			})
+		}
+
+		last := len(lb.Runs) == 0 || lb.Runs[len(lb.Runs)-1].Place >= pb.Place
+		if last || page >= lb.Pagination.Pages {
+			break
+		}
+	}
+	return passers, nil
+}
+
+type pbAlertsMsg struct {
+	alerts []PBAlert
+	err    error
+}
+
+type pbCheckMsg struct{}
+
+func checkPBsCmd(client *Client) tea.Cmd {
+	return func() tea.Msg {
+		alerts, err := checkPBs(client)
+		return pbAlertsMsg{alerts: alerts, err: err}
+	}
+}
+
+func schedulePBCheck() tea.Cmd {
+	return tea.Tick(pbCheckInterval, func(time.Time) tea.Msg {
+		return pbCheckMsg{}
+	})
+}
+
+func sendAlertsCmd(router *alertRouter, alerts []Alert) tea.Cmd {
+	if router == nil || len(alerts) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for _, a := range alerts {
+			router.send(a)
+		}
+		return nil
+	}
+}
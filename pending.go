@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingRun is one of my own runs waiting for verification
+type pendingRun struct {
+	raw      v1Run
+	run      QueueRun
+	game     string // names, when the game's metadata could be loaded
+	category string
+	millis   bool
+}
+
+// GetMyPendingRuns lists a user's runs waiting for verification, newest
+// first
+func (c *Client) GetMyPendingRuns(userID string) ([]v1Run, error) {
+	q := url.Values{
+		"user":      {userID},
+		"status":    {"new"},
+		"orderby":   {"submitted"},
+		"direction": {"desc"},
+		"embed":     {"players"},
+	}
+	runs, err := c.listRuns(q, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pending submissions: %w", err)
+	}
+	return runs, nil
+}
+
+// settings rebuilds the run form for a submitted run, so a change to one
+// field keeps the rest as they were
+func (r v1Run) settings() runSettings {
+	q := r.queueRun()
+	s := runSettings{
+		RunID:       r.ID,
+		GameID:      r.Game,
+		CategoryID:  r.Category,
+		LevelID:     r.Level,
+		PlayerNames: q.Players,
+		Time:        splitRunTime(q.Time),
+		PlatformID:  r.System.Platform,
+		Emulator:    r.System.Emulated,
+		Video:       q.Video,
+		Comment:     r.Comment,
+		Values:      []runValue{},
+	}
+	if date, err := time.ParseInLocation(time.DateOnly, r.Date, time.Local); err == nil {
+		s.Date = date.Unix()
+	}
+	for variable, value := range r.Values {
+		s.Values = append(s.Values, runValue{VariableID: variable, ValueID: value})
+	}
+	return s
+}
+
+// UpdateRun saves changed settings of a submitted run
+func (c *Client) UpdateRun(settings runSettings) error {
+	body := struct {
+		Settings runSettings `json:"settings"`
+	}{
+		Settings: settings,
+	}
+	if err := c.write("PutRunSettings", body, nil); err != nil {
+		return fmt.Errorf("updating run: %w", err)
+	}
+	return nil
+}
+
+// DeleteRun withdraws a run
+func (c *Client) DeleteRun(runID string) error {
+	body := struct {
+		RunID string `json:"runId"`
+	}{
+		RunID: runID,
+	}
+	if err := c.write("PutRunDelete", body, nil); err != nil {
+		return fmt.Errorf("withdrawing run: %w", err)
+	}
+	return nil
+}
+
+type pendingMsg struct {
+	runs []pendingRun
+	err  error
+}
+
+type pendingEditMsg struct {
+	id      string
+	deleted bool
+	err     error
+}
+
+func loadPendingCmd(client *Client, games *GameCache) tea.Cmd {
+	return func() tea.Msg {
+		session, err := client.GetSession()
+		if err != nil {
+			return pendingMsg{err: err}
+		}
+		if !session.SignedIn || session.User == nil {
+			return pendingMsg{err: errNoSession}
+		}
+		raw, err := client.GetMyPendingRuns(session.User.ID)
+		if err != nil {
+			return pendingMsg{err: err}
+		}
+
+		runs := make([]pendingRun, len(raw))
+		for i, r := range raw {
+			p := pendingRun{raw: r, run: r.queueRun(), game: r.Game, category: r.Category}
+			// The weblink names the game the way the metadata cache wants
+			if abbr := resolveLink(r.Weblink).Game; abbr != "" && games != nil {
+				if data, err := games.Get(abbr); err == nil {
+					p.game, p.millis = data.Game.Name, data.Game.Milliseconds
+					for _, c := range data.Categories {
+						if c.ID == r.Category {
+							p.category = c.Name
+						}
+					}
+				}
+			}
+			runs[i] = p
+		}
+		return pendingMsg{runs: runs}
+	}
+}
+
+func editRunCmd(client *Client, settings runSettings) tea.Cmd {
+	return func() tea.Msg {
+		return pendingEditMsg{id: settings.RunID, err: client.UpdateRun(settings)}
+	}
+}
+
+func deleteRunCmd(client *Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		return pendingEditMsg{id: id, deleted: true, err: client.DeleteRun(id)}
+	}
+}
+
+const (
+	editNone = iota
+	editComment
+	editVideo
+)
+
+// pendingEditor is the prompt for changing one run
+type pendingEditor struct {
+	run   pendingRun
+	field int
+	input textinput.Model
+}
+
+func newPendingEditor(run pendingRun, field int) *pendingEditor {
+	input := textinput.New()
+	input.CharLimit = 500
+	input.Width = 60
+	switch field {
+	case editComment:
+		input.Placeholder = "comment"
+		input.SetValue(run.raw.Comment)
+	case editVideo:
+		input.Placeholder = "video URL"
+		input.SetValue(run.run.Video)
+	}
+	input.CursorEnd()
+	return &pendingEditor{run: run, field: field, input: input}
+}
+
+// update returns the editor, or nil once it's done along with the command
+// that saves the change
+func (e *pendingEditor) update(msg tea.KeyMsg, client *Client) (*pendingEditor, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return nil, nil
+	case "enter":
+		settings := e.run.raw.settings()
+		value := strings.TrimSpace(e.input.Value())
+		if e.field == editComment {
+			settings.Comment = value
+		} else {
+			if value == "" {
+				return e, nil
+			}
+			settings.Video = value
+		}
+		return nil, editRunCmd(client, settings)
+	}
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return e, cmd
+}
+
+func (e *pendingEditor) view() string {
+	what := fmt.Sprintf("%s › %s", e.run.game, e.run.category)
+	if e.field == editComment {
+		return alertBannerStyle.Render("Comment for " + what + "\n" + e.input.View())
+	}
+	return alertBannerStyle.Render("Video for " + what + "\n" + e.input.View())
+}
+
+func (e *pendingEditor) help() string {
+	return "enter save • esc cancel"
+}
+
+// withdrawRunMsg and deleteDraftMsg are confirmed W and d presses on the
+// Submissions tab
+type withdrawRunMsg struct {
+	id string
+}
+
+type deleteDraftMsg struct {
+	id string
+}
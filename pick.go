@@ -0,0 +1,320 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// "speedrunner pick notification|game|run" shows a small list to filter
+// by typing and prints the URL of the one picked, like fzf, for shell
+// scripts and key bindings. The list draws on the terminal, so stdout
+// only ever has the pick.
+
+// pickRows is how many matches show at once
+const pickRows = 10
+
+var (
+	pickMatchStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true)
+	pickSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	pickDetailStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+)
+
+type pickItem struct {
+	title  string
+	detail string
+	id     string
+	url    string
+	video  string
+}
+
+// runPick handles "pick notification|game|run"
+func runPick(args []string, cfg Config, sessionID string) error {
+	const usage = "usage: speedrunner pick notification|game|run [-id] [-video]"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	fs := flag.NewFlagSet("pick "+args[0], flag.ExitOnError)
+	printID := fs.Bool("id", false, "print the ID instead of the URL")
+	printVideo := fs.Bool("video", false, "print a run's video instead of its URL")
+	fs.Parse(args[1:])
+
+	if sessionID == "" && args[0] != "game" {
+		return errNoSession
+	}
+	client := newConfiguredClient(cfg, sessionID)
+	var (
+		items []pickItem
+		err   error
+	)
+	switch args[0] {
+	case "notification":
+		items, err = pickNotifications(client, cfg)
+	case "game":
+		items, err = pickGames(client, cfg)
+	case "run":
+		items, err = pickRuns(client)
+	default:
+		return fmt.Errorf("unknown pick %q, %s", args[0], usage)
+	}
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no %ss to pick from", args[0])
+	}
+
+	tty, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+	final, err := tea.NewProgram(newPicker(args[0], items), tea.WithOutput(tty), tea.WithInputTTY()).Run()
+	if err != nil {
+		return fmt.Errorf("running picker: %w", err)
+	}
+	picked, ok := final.(picker).picked()
+	if !ok {
+		// Like fzf, a cancelled pick prints nothing and fails
+		os.Exit(1)
+	}
+	switch {
+	case *printID:
+		fmt.Println(picked.id)
+	case *printVideo:
+		if picked.video == "" {
+			return errors.New("that run has no video")
+		}
+		fmt.Println(picked.video)
+	default:
+		fmt.Println(picked.url)
+	}
+	return nil
+}
+
+func pickNotifications(client *Client, cfg Config) ([]pickItem, error) {
+	result, err := client.GetNotificationPages(cfg.NotificationsPerPage, cfg.NotificationPages)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]pickItem, len(result.Notifications))
+	for i, n := range result.Notifications {
+		detail := time.Unix(n.Date, 0).Format("2006-01-02")
+		if !n.Read {
+			detail += " • unread"
+		}
+		items[i] = pickItem{title: n.Title, detail: detail, id: n.ID, url: "https://www.speedrun.com" + n.Path}
+	}
+	return items, nil
+}
+
+// pickGames offers the followed and moderated games and any in the cache
+func pickGames(client *Client, cfg Config) ([]pickItem, error) {
+	cache, err := NewGameCache(client)
+	if err != nil {
+		return nil, err
+	}
+	abbrs := append(append([]string{}, cfg.FollowedGames...), cfg.ModeratedGames...)
+	if cached, err := cache.disk.keys(); err == nil {
+		abbrs = append(abbrs, cached...)
+	}
+
+	seen := map[string]bool{}
+	var items []pickItem
+	for _, abbr := range abbrs {
+		if seen[abbr] {
+			continue
+		}
+		seen[abbr] = true
+		data, err := cache.Get(abbr)
+		if err != nil {
+			logOnce("pick.game."+abbr, "pick: %s: %v", abbr, err)
+			continue
+		}
+		items = append(items, pickItem{title: data.Game.Name, detail: abbr, id: data.Game.ID, url: "https://www.speedrun.com/" + abbr})
+	}
+	return items, nil
+}
+
+// pickRuns offers the signed in user's personal bests
+func pickRuns(client *Client) ([]pickItem, error) {
+	session, err := client.GetSession()
+	if err != nil {
+		return nil, err
+	}
+	if !session.SignedIn || session.User == nil {
+		return nil, errors.New("session is not signed in")
+	}
+	pbs, err := client.GetUserLeaderboard(session.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]pickItem, 0, len(pbs.Runs))
+	for _, r := range pbs.Runs {
+		detail := formatRunTime(r.Duration())
+		if r.Place > 0 {
+			detail = fmt.Sprintf("#%d • %s", r.Place, detail)
+		}
+		items = append(items, pickItem{title: pbs.BoardName(r), detail: detail, id: r.ID, url: pbs.RunURL(r), video: r.Video})
+	}
+	return items, nil
+}
+
+// fuzzyMatch finds query's runes in text in order, ignoring case. The
+// score favors runs of matched runes and matches at the start of words.
+func fuzzyMatch(query, text string) (score int, positions []int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+	runes := []rune(text)
+	qi := 0
+	for i, r := range runes {
+		if qi == len(q) {
+			break
+		}
+		if unicode.ToLower(r) != q[qi] {
+			continue
+		}
+		score++
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 2
+		}
+		if n := len(positions); n > 0 && positions[n-1] == i-1 {
+			score += 3
+		}
+		positions = append(positions, i)
+		qi++
+	}
+	return score, positions, qi == len(q)
+}
+
+type pickMatch struct {
+	item      pickItem
+	score     int
+	positions []int
+}
+
+type picker struct {
+	kind     string
+	items    []pickItem
+	input    textinput.Model
+	matches  []pickMatch
+	selected int
+	done     bool
+	width    int
+}
+
+func newPicker(kind string, items []pickItem) picker {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "filter " + kind + "s"
+	input.Focus()
+	p := picker{kind: kind, items: items, input: input, width: 80}
+	p.filter()
+	return p
+}
+
+func (p *picker) filter() {
+	p.matches = p.matches[:0]
+	for _, item := range p.items {
+		if score, positions, ok := fuzzyMatch(p.input.Value(), item.title); ok {
+			p.matches = append(p.matches, pickMatch{item: item, score: score, positions: positions})
+		}
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool { return p.matches[i].score > p.matches[j].score })
+	p.selected = min(p.selected, max(len(p.matches)-1, 0))
+}
+
+// picked is the item chosen with enter, if any
+func (p picker) picked() (pickItem, bool) {
+	if !p.done || p.selected >= len(p.matches) {
+		return pickItem{}, false
+	}
+	return p.matches[p.selected].item, true
+}
+
+func (p picker) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (p picker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width = msg.Width
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return p, tea.Quit
+		case "enter":
+			p.done = len(p.matches) > 0
+			return p, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			if p.selected > 0 {
+				p.selected--
+			}
+			return p, nil
+		case "down", "ctrl+n", "ctrl+j":
+			if p.selected < len(p.matches)-1 {
+				p.selected++
+			}
+			return p, nil
+		}
+	}
+	var cmd tea.Cmd
+	before := p.input.Value()
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != before {
+		p.selected = 0
+		p.filter()
+	}
+	return p, cmd
+}
+
+func (p picker) View() string {
+	if p.done {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(p.input.View() + "\n")
+
+	// Scroll so the selected match stays in view
+	start := max(p.selected-pickRows+1, 0)
+	for i := start; i < min(start+pickRows, len(p.matches)); i++ {
+		m := p.matches[i]
+		title := truncate(m.item.title, max(p.width-lipgloss.Width(m.item.detail)-4, 10))
+		line := highlightRunes(title, m.positions)
+		if i == p.selected {
+			line = pickSelectedStyle.Render(title)
+		}
+		b.WriteString(line + "  " + pickDetailStyle.Render(m.item.detail) + "\n")
+	}
+	b.WriteString(pickDetailStyle.Render(fmt.Sprintf("%d/%d • enter pick • esc cancel", len(p.matches), len(p.items))))
+	return b.String()
+}
+
+// highlightRunes styles the matched runes of s
+func highlightRunes(s string, positions []int) string {
+	matched := make(map[int]bool, len(positions))
+	for _, i := range positions {
+		matched[i] = true
+	}
+	var b strings.Builder
+	for i, r := range []rune(s) {
+		if matched[i] {
+			b.WriteString(pickMatchStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
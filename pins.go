@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const pinsFile = "pins.json"
+
+var pinnedHeaderStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FFD700")).
+	Bold(true)
+
+// Pin is something bookmarked to come back to: a notification or a run.
+// It keeps its own title and link so it outlives the page it came from.
+type Pin struct {
+	Kind  string    `json:"kind"` // "notification" or "run"
+	ID    string    `json:"id"`
+	Title string    `json:"title"`
+	URL   string    `json:"url"`
+	Added time.Time `json:"added"`
+}
+
+func loadPins() ([]Pin, error) {
+	var pins []Pin
+	err := loadState(pinsFile, &pins)
+	return pins, err
+}
+
+// updatePins pins p, or unpins it if it's already pinned, and saves the
+// list. It reports whether p ended up pinned.
+func updatePins(pins []Pin, p Pin) ([]Pin, bool, error) {
+	pinned := true
+	kept := make([]Pin, 0, len(pins)+1)
+	for _, existing := range pins {
+		if existing.Kind == p.Kind && existing.ID == p.ID {
+			pinned = false
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if pinned {
+		p.Added = time.Now()
+		kept = append(kept, p)
+	}
+	if err := saveState(pinsFile, kept); err != nil {
+		return pins, !pinned, fmt.Errorf("saving pins: %w", err)
+	}
+	return kept, pinned, nil
+}
+
+func notificationPin(n Notification) Pin {
+	return Pin{Kind: "notification", ID: n.ID, Title: n.Title, URL: "https://www.speedrun.com" + n.Path}
+}
+
+func runPin(id, title, url string) Pin {
+	return Pin{Kind: "run", ID: id, Title: title, URL: url}
+}
+
+// togglePin pins or unpins p and reports the outcome in the status bar.
+// z takes it back.
+func (m model) togglePin(p Pin) model {
+	m, pinned, err := m.flipPin(p)
+	if err != nil {
+		return m
+	}
+	desc := "unpinned " + truncate(p.Title, 60)
+	if pinned {
+		desc = "pinned " + truncate(p.Title, 60)
+	}
+	return m.pushUndo(desc, func(m model) (model, tea.Cmd) {
+		m, _, _ = m.flipPin(p)
+		return m, nil
+	})
+}
+
+func (m model) flipPin(p Pin) (model, bool, error) {
+	pins, pinned, err := updatePins(m.pins, p)
+	m.pins = pins
+	switch {
+	case err != nil:
+		m.status = err.Error()
+	case pinned:
+		m.status = "Pinned " + truncate(p.Title, 60)
+	default:
+		m.status = "Unpinned " + truncate(p.Title, 60)
+	}
+	return m, pinned, err
+}
+
+func renderPin(p Pin) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[%s] %s • pinned %s\n", pinnedHeaderStyle.Render("★"), p.Kind, p.Added.Local().Format("2006-01-02")))
+	b.WriteString(p.Title)
+	b.WriteString("\n")
+	b.WriteString(urlStyle.Render(strings.TrimPrefix(p.URL, "https://www.")))
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// Longest platform badge, like "WiiU VC"
+	platformShortMax = 7
+
+	// A badge with the emulator mark, as it's laid out on a board row
+	platformBadgeWidth = platformShortMax + 4
+)
+
+var (
+	platformStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#7FB4CA"))
+
+	emulatorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500"))
+)
+
+// Short names for the site's platforms, keyed by their names in lower case
+var platformShorts = map[string]string{
+	"pc":                            "PC",
+	"nintendo 64":                   "N64",
+	"wii virtual console":           "Wii VC",
+	"wii u virtual console":         "WiiU VC",
+	"3ds virtual console":           "3DS VC",
+	"nintendo entertainment system": "NES",
+	"super nintendo":                "SNES",
+	"game boy":                      "GB",
+	"game boy color":                "GBC",
+	"game boy advance":              "GBA",
+	"game boy player":               "GBP",
+	"nintendo ds":                   "DS",
+	"nintendo 3ds":                  "3DS",
+	"new nintendo 3ds":              "N3DS",
+	"gamecube":                      "GCN",
+	"wii":                           "Wii",
+	"wii u":                         "Wii U",
+	"nintendo switch":               "Switch",
+	"playstation":                   "PS1",
+	"playstation 2":                 "PS2",
+	"playstation 3":                 "PS3",
+	"playstation 4":                 "PS4",
+	"playstation 5":                 "PS5",
+	"playstation portable":          "PSP",
+	"playstation vita":              "Vita",
+	"xbox":                          "Xbox",
+	"xbox 360":                      "X360",
+	"xbox one":                      "XB1",
+	"xbox series x":                 "XSX",
+	"xbox series s":                 "XSS",
+	"genesis":                       "GEN",
+	"sega mega drive":               "MD",
+	"dreamcast":                     "DC",
+	"android":                       "Android",
+	"iphone":                        "iOS",
+	"ipad":                          "iPadOS",
+	"mac":                           "Mac",
+	"linux":                         "Linux",
+	"web":                           "Web",
+	"arcade":                        "Arcade",
+}
+
+// platformShort is the badge text for a platform: a known short name, the
+// name itself when it's short enough, or its initials
+func platformShort(name string) string {
+	if short, ok := platformShorts[strings.ToLower(name)]; ok {
+		return short
+	}
+	if len([]rune(name)) <= platformShortMax {
+		return name
+	}
+	if words := strings.Fields(name); len(words) > 1 {
+		var initials []rune
+		for _, w := range words {
+			r := []rune(w)[0]
+			if unicode.IsDigit(r) {
+				// Keep numbers whole, as in "Sega 32X"
+				initials = append(initials, []rune(w)...)
+				continue
+			}
+			initials = append(initials, unicode.ToUpper(r))
+		}
+		return truncate(string(initials), platformShortMax)
+	}
+	return truncate(name, platformShortMax)
+}
+
+// platformBadge marks what a run was played on, with EMU after it for runs
+// on an emulator. It's empty when there's nothing to show.
+func platformBadge(name string, emulated bool) string {
+	var parts []string
+	if name != "" {
+		parts = append(parts, platformStyle.Render(platformShort(name)))
+	}
+	if emulated {
+		parts = append(parts, emulatorStyle.Render("EMU"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// padBadge pads a badge to the board's badge column; the styles' escape
+// codes would throw %-*s off
+func padBadge(badge string) string {
+	return badge + strings.Repeat(" ", max(platformBadgeWidth-lipgloss.Width(badge), 0))
+}
+
+// platformLegend spells out the badges for the given platform names, one
+// per line
+func platformLegend(names []string) []string {
+	slices.Sort(names)
+	names = slices.Compact(names)
+	lines := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		lines = append(lines, padBadge(platformStyle.Render(platformShort(name)))+" "+name)
+	}
+	return append(lines, padBadge(emulatorStyle.Render("EMU"))+" played on an emulator")
+}
+
+// legend is the badges the board can show
+func (b boardsModel) legend() []string {
+	if b.game == nil {
+		return nil
+	}
+	names := make([]string, 0, len(b.game.Platforms))
+	for _, p := range b.game.Platforms {
+		names = append(names, p.Name)
+	}
+	return platformLegend(names)
+}
+
+// legend is the badges the queue's runs can show
+func (q queueModel) legend() []string {
+	if len(q.platforms) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(q.platforms))
+	for _, name := range q.platforms {
+		names = append(names, name)
+	}
+	return platformLegend(names)
+}
+
+// platformName names one of the game's platforms, or "" for one it doesn't
+// list
+func (b boardsModel) platformName(id string) string {
+	if b.game == nil {
+		return ""
+	}
+	for _, p := range b.game.Platforms {
+		if p.ID == id {
+			return p.Name
+		}
+	}
+	return ""
+}
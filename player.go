@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mpv plays YouTube and Twitch VODs itself through its yt-dlp hook
+var defaultPlayer = []string{"mpv"}
+
+// statusMsg is a one line message for the status bar, cleared on the next
+// key press
+type statusMsg string
+
+func statusCmd(format string, args ...any) tea.Cmd {
+	return func() tea.Msg {
+		return statusMsg(fmt.Sprintf(format, args...))
+	}
+}
+
+// isLiveChannel reports whether a link is a Twitch channel rather than a
+// VOD, which players handle better through streamlink.
+func isLiveChannel(videoURL string) bool {
+	u, err := url.Parse(videoURL)
+	if err != nil || !strings.HasSuffix(u.Hostname(), "twitch.tv") {
+		return false
+	}
+	path := strings.Trim(u.Path, "/")
+	return path != "" && !strings.Contains(path, "/")
+}
+
+// playVideo starts the configured player on a video link in the
+// background. player is the command and its arguments; the URL is
+// appended.
+func playVideo(player []string, videoURL string) error {
+	if len(player) == 0 {
+		player = defaultPlayer
+	}
+
+	var cmd *exec.Cmd
+	if streamlink, err := exec.LookPath("streamlink"); err == nil && isLiveChannel(videoURL) {
+		cmd = exec.Command(streamlink, "--player", strings.Join(player, " "), videoURL, "best")
+	} else {
+		player = withMPVIPC(player)
+		path, err := exec.LookPath(player[0])
+		if err != nil {
+			return fmt.Errorf("video player %q not found, set video_player in the config", player[0])
+		}
+		cmd = exec.Command(path, append(player[1:], videoURL)...)
+	}
+
+	// Keep the player's output off the TUI
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting %s: %w", cmd.Path, err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func playVideoCmd(player []string, videoURL string) tea.Cmd {
+	return func() tea.Msg {
+		if err := playVideo(player, videoURL); err != nil {
+			return statusMsg(err.Error())
+		}
+		return statusMsg("Playing " + videoURL)
+	}
+}
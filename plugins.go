@@ -0,0 +1,460 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Plugins are executables in the plugins dir next to the config. Each call
+// runs the plugin with one JSON request on stdin and reads one JSON
+// response from stdout:
+//
+//	{"type": "describe"}                 → pluginInfo
+//	{"type": "screen"}                   → pluginScreen
+//	{"type": "action", "action": "id",
+//	 "screen": "queue", "item": {...}}   → pluginResult
+//
+// The item is the selected notification or run, as Y copies it.
+const (
+	pluginsDir     = "plugins"
+	pluginTimeout  = 10 * time.Second
+	pluginMaxReply = 1 << 20
+)
+
+// Windows has no executable bit, so plugins there go by extension
+var windowsPluginExts = map[string]bool{".exe": true, ".bat": true, ".cmd": true}
+
+// pluginAction is a key a plugin adds to a screen
+type pluginAction struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Screen string `json:"screen"` // notifications, boards or queue
+	Title  string `json:"title"`  // shown in the status bar while it runs
+}
+
+// pluginInfo is a plugin's answer to describe
+type pluginInfo struct {
+	Name    string         `json:"name"`
+	Screen  string         `json:"screen,omitempty"` // title of its screen on the Plugins tab, if it has one
+	Actions []pluginAction `json:"actions,omitempty"`
+}
+
+type plugin struct {
+	path string
+	info pluginInfo
+}
+
+// pluginScreen is what a plugin shows on the Plugins tab: text, a list of
+// items, or both
+type pluginScreen struct {
+	Text  string       `json:"text,omitempty"`
+	Items []pluginItem `json:"items,omitempty"`
+}
+
+type pluginItem struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+	URL    string `json:"url,omitempty"` // opened with enter
+}
+
+// pluginResult is a plugin's answer to an action
+type pluginResult struct {
+	Status string `json:"status,omitempty"`
+	Open   string `json:"open,omitempty"` // a URL to open in the browser
+	Error  string `json:"error,omitempty"`
+}
+
+type pluginRequest struct {
+	Type   string `json:"type"`
+	Action string `json:"action,omitempty"`
+	Screen string `json:"screen,omitempty"`
+	Item   any    `json:"item,omitempty"`
+}
+
+// callPlugin sends one request and decodes the reply. What the plugin
+// writes to stderr goes into the error.
+func callPlugin(path string, req pluginRequest, out any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stderr = &stderr
+	// Don't wait on a child the plugin left holding its output
+	cmd.WaitDelay = time.Second
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("starting plugin: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting plugin: %w", err)
+	}
+	// Read no more than the limit, so a runaway plugin can't fill memory
+	reply, readErr := io.ReadAll(io.LimitReader(stdout, pluginMaxReply+1))
+	if len(reply) > pluginMaxReply {
+		cancel()
+		cmd.Wait()
+		return errors.New("reply too large")
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("no answer within %s", pluginTimeout)
+		}
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if readErr != nil {
+		return fmt.Errorf("reading reply: %w", readErr)
+	}
+	if err := json.Unmarshal(reply, out); err != nil {
+		return fmt.Errorf("reading reply: %w", err)
+	}
+	return nil
+}
+
+// findPlugins lists the executables in the plugins dir
+func findPlugins() ([]string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Join(dir, pluginsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading plugins dir: %w", err)
+	}
+
+	var paths []string
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		switch {
+		case runtime.GOOS == "windows" && !windowsPluginExts[strings.ToLower(filepath.Ext(e.Name()))]:
+			continue
+		case runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0:
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+	return paths, nil
+}
+
+type pluginsMsg struct {
+	plugins []plugin
+	failed  []string
+}
+
+// loadPluginsCmd asks every plugin what it adds, side by side. A plugin
+// that doesn't answer is left out and logged.
+func loadPluginsCmd() tea.Cmd {
+	return func() tea.Msg {
+		paths, err := findPlugins()
+		if err != nil {
+			log.Printf("plugins: %v", err)
+			return nil
+		}
+		if len(paths) == 0 {
+			return nil
+		}
+
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			msg pluginsMsg
+		)
+		for _, path := range paths {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var info pluginInfo
+				err := callPlugin(path, pluginRequest{Type: "describe"}, &info)
+				if err == nil && info.Name == "" {
+					info.Name = filepath.Base(path)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					log.Printf("plugins: %s: %v", filepath.Base(path), err)
+					msg.failed = append(msg.failed, filepath.Base(path))
+					return
+				}
+				msg.plugins = append(msg.plugins, plugin{path: path, info: info})
+			}()
+		}
+		wg.Wait()
+		sort.Slice(msg.plugins, func(i, j int) bool { return msg.plugins[i].info.Name < msg.plugins[j].info.Name })
+		return msg
+	}
+}
+
+// pluginActionFor finds the plugin action bound to a key on a screen
+func (m model) pluginActionFor(s screen, key string) (plugin, pluginAction, bool) {
+	for _, p := range m.plugins {
+		for _, a := range p.info.Actions {
+			if a.Key == key && strings.EqualFold(a.Screen, screenNames[s]) {
+				return p, a, true
+			}
+		}
+	}
+	return plugin{}, pluginAction{}, false
+}
+
+// pluginTarget is what's selected on the screen, as Y would copy it
+func (m model) pluginTarget() (any, bool) {
+	switch m.screen {
+	case screenNotifications:
+		if m.detail != nil {
+			return nil, false
+		}
+		row, ok := m.selectedRow(m.notificationRows(m.inbox.apply(m.notifications)))
+		if !ok || row.header {
+			return nil, false
+		}
+		return row.n, true
+	case screenQueue:
+		r, ok := m.queue.selectedRun()
+		if !ok {
+			return nil, false
+		}
+		return m.queue.yanked(r), true
+	case screenBoards:
+		r, ok := m.boards.selectedRun()
+		if !ok {
+			return nil, false
+		}
+		return m.boards.yanked(r), true
+	}
+	return nil, false
+}
+
+type pluginResultMsg struct {
+	name   string
+	result pluginResult
+	err    error
+}
+
+func (m model) runPluginAction(p plugin, a pluginAction) (model, tea.Cmd) {
+	item, ok := m.pluginTarget()
+	if !ok {
+		return m, nil
+	}
+	title := a.Title
+	if title == "" {
+		title = a.ID
+	}
+	m.status = fmt.Sprintf("%s: %s...", p.info.Name, title)
+	req := pluginRequest{Type: "action", Action: a.ID, Screen: strings.ToLower(screenNames[m.screen]), Item: item}
+	return m, func() tea.Msg {
+		var result pluginResult
+		err := callPlugin(p.path, req, &result)
+		return pluginResultMsg{name: p.info.Name, result: result, err: err}
+	}
+}
+
+func (m model) pluginResult(msg pluginResultMsg) (model, tea.Cmd) {
+	switch {
+	case msg.err != nil:
+		m.status = fmt.Sprintf("%s failed: %v", msg.name, msg.err)
+	case msg.result.Error != "":
+		m.status = fmt.Sprintf("%s: %s", msg.name, msg.result.Error)
+	case msg.result.Status != "":
+		m.status = fmt.Sprintf("%s: %s", msg.name, msg.result.Status)
+	default:
+		m.status = msg.name + ": done"
+	}
+	if msg.err == nil && msg.result.Open != "" {
+		if err := openBrowser(msg.result.Open); err != nil {
+			m.status = fmt.Sprintf("Error opening browser: %v", err)
+		}
+	}
+	return m, nil
+}
+
+type pluginScreenMsg struct {
+	path   string
+	screen pluginScreen
+	err    error
+}
+
+func pluginScreenCmd(p plugin) tea.Cmd {
+	return func() tea.Msg {
+		var s pluginScreen
+		err := callPlugin(p.path, pluginRequest{Type: "screen"}, &s)
+		return pluginScreenMsg{path: p.path, screen: s, err: err}
+	}
+}
+
+// pluginsModel is the plugins tab: the plugins that have a screen, then
+// the one picked
+type pluginsModel struct {
+	plugins  []plugin // with a screen
+	selected int
+	open     *plugin
+	screen   pluginScreen
+	item     int
+	loading  bool
+	err      error
+}
+
+func (p pluginsModel) setPlugins(all []plugin) pluginsModel {
+	p.plugins = nil
+	for _, pl := range all {
+		if pl.info.Screen != "" {
+			p.plugins = append(p.plugins, pl)
+		}
+	}
+	p.selected = min(p.selected, max(len(p.plugins)-1, 0))
+	return p
+}
+
+// activate reloads the open plugin's screen, since it's likely changed
+func (p pluginsModel) activate() (pluginsModel, tea.Cmd) {
+	if p.open == nil {
+		return p, nil
+	}
+	p.loading = true
+	return p, pluginScreenCmd(*p.open)
+}
+
+// back closes the open plugin, reporting false when there was none
+func (p pluginsModel) back() (pluginsModel, bool) {
+	if p.open == nil {
+		return p, false
+	}
+	p.open, p.err = nil, nil
+	return p, true
+}
+
+func (p pluginsModel) update(msg tea.Msg) (pluginsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pluginScreenMsg:
+		if p.open == nil || p.open.path != msg.path {
+			return p, nil
+		}
+		p.loading = false
+		p.screen, p.err = msg.screen, msg.err
+		p.item = min(p.item, max(len(p.screen.Items)-1, 0))
+	case tea.KeyMsg:
+		count := len(p.plugins)
+		if p.open != nil {
+			count = len(p.screen.Items)
+		}
+		cursor := &p.selected
+		if p.open != nil {
+			cursor = &p.item
+		}
+		switch msg.String() {
+		case "up", "k":
+			if *cursor > 0 {
+				*cursor--
+			}
+		case "down", "j":
+			if *cursor < count-1 {
+				*cursor++
+			}
+		case "r":
+			return p.activate()
+		case "enter":
+			if p.open == nil {
+				if p.selected >= len(p.plugins) {
+					return p, nil
+				}
+				pl := p.plugins[p.selected]
+				p.open, p.item, p.screen = &pl, 0, pluginScreen{}
+				return p.activate()
+			}
+			if p.item < len(p.screen.Items) {
+				if url := p.screen.Items[p.item].URL; url != "" {
+					if err := openBrowser(url); err != nil {
+						return p, statusCmd("Error opening browser: %v", err)
+					}
+				}
+			}
+		}
+	}
+	return p, nil
+}
+
+func (p pluginsModel) title() string {
+	if p.open != nil {
+		return p.open.info.Screen
+	}
+	return ""
+}
+
+func (p pluginsModel) view() string {
+	if p.open == nil {
+		if len(p.plugins) == 0 {
+			dir, _ := configDir()
+			return "No plugins with a screen. Plugins go in " + filepath.Join(dir, pluginsDir) + "; see the README."
+		}
+		var b strings.Builder
+		for i, pl := range p.plugins {
+			line := fmt.Sprintf("%s  %s", pl.info.Screen, urlStyle.Render(pl.info.Name))
+			if i == p.selected {
+				b.WriteString(selectedItemStyle.Render("> "+line) + "\n")
+			} else {
+				b.WriteString(unselectedItemStyle.Render("  "+line) + "\n")
+			}
+		}
+		return b.String()
+	}
+
+	switch {
+	case p.loading:
+		return "Loading..."
+	case p.err != nil:
+		return fmt.Sprintf("Error: %v", p.err)
+	}
+	var b strings.Builder
+	if p.screen.Text != "" {
+		b.WriteString(p.screen.Text + "\n\n")
+	}
+	for i, item := range p.screen.Items {
+		line := item.Title
+		if i == p.item {
+			b.WriteString(selectedItemStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString(unselectedItemStyle.Render("  "+line) + "\n")
+		}
+		if item.Detail != "" {
+			b.WriteString(urlStyle.Render("    "+item.Detail) + "\n")
+		}
+	}
+	if b.Len() == 0 {
+		return "Nothing to show"
+	}
+	return b.String()
+}
+
+func (p pluginsModel) help() string {
+	if p.open == nil {
+		return "enter open • esc back"
+	}
+	return "enter open link • r reload • esc back"
+}
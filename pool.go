@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultPoolWorkers = 8
+	defaultPerHost     = 4
+)
+
+// Job is a single unit of work for the pool, usually one leaderboard fetch
+type Job struct {
+	Name string // used in error reports, e.g. "sm64/120 Star"
+	Host string // requests to the same host share a concurrency limit
+	Run  func() error
+}
+
+// Pool runs jobs with a bounded number of workers and a per-host limit so
+// polling many boards doesn't hammer a single site.
+type Pool struct {
+	workers int
+	perHost int
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+func NewPool(workers, perHost int) *Pool {
+	if workers < 1 {
+		workers = defaultPoolWorkers
+	}
+	if perHost < 1 {
+		perHost = defaultPerHost
+	}
+	return &Pool{
+		workers: workers,
+		perHost: perHost,
+		hosts:   make(map[string]chan struct{}),
+	}
+}
+
+// Run executes all jobs and blocks until they finish. Every failure is
+// collected into a single *PoolError instead of stopping at the first one.
+func (p *Pool) Run(jobs []Job) error {
+	queue := make(chan Job)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []JobError
+	)
+
+	workers := p.workers
+	if len(jobs) < workers {
+		workers = len(jobs)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if err := p.run(job); err != nil {
+					mu.Lock()
+					failed = append(failed, JobError{Name: job.Name, Err: err})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+	return &PoolError{Total: len(jobs), Failed: failed}
+}
+
+func (p *Pool) run(job Job) (err error) {
+	sem := p.hostSemaphore(job.Host)
+	sem <- struct{}{}
+	defer func() { <-sem }()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run()
+}
+
+func (p *Pool) hostSemaphore(host string) chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sem, ok := p.hosts[host]
+	if !ok {
+		sem = make(chan struct{}, p.perHost)
+		p.hosts[host] = sem
+	}
+	return sem
+}
+
+// hostOf returns the host part of a URL for use as a Job.Host
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host
+}
+
+// JobError ties a failure back to the job that produced it
+type JobError struct {
+	Name string
+	Err  error
+}
+
+func (e JobError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+func (e JobError) Unwrap() error {
+	return e.Err
+}
+
+// PoolError aggregates every failed job from a Pool.Run
+type PoolError struct {
+	Total  int
+	Failed []JobError
+}
+
+func (e *PoolError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("%d of %d jobs failed: %s", len(e.Failed), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *PoolError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f
+	}
+	return errs
+}
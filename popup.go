@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// -popup is a layout for a small overlay, like tmux's display-popup: one
+// line per notification, single keys and no tabs. Opening a notification
+// sends it to the browser and closes the app.
+
+var (
+	popupHeaderStyle    = lipgloss.NewStyle().Bold(true)
+	popupSelectedStyle  = lipgloss.NewStyle().Reverse(true)
+	popupHighlightStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9F43")).Bold(true)
+)
+
+const popupHints = "j/k move • enter open • r read • R all read • q quit"
+
+// popupHeight is what the header and the key line take
+const popupHeight = 2
+
+func (m model) updatePopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.inbox.apply(m.notifications)
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "down", "j":
+		if m.selected < len(visible)-1 {
+			m.selected++
+		}
+	case "g", "home":
+		m.selected = 0
+	case "G", "end":
+		m.selected = max(len(visible)-1, 0)
+	case "enter", "o":
+		if m.selected >= len(visible) {
+			return m, nil
+		}
+		n := visible[m.selected]
+		openBrowser("https://www.speedrun.com" + n.Path)
+		var readCmd tea.Cmd
+		if m.autoMarkRead && !n.Read {
+			m, readCmd = m.markRead([]string{n.ID})
+		}
+		// The mark read has to reach the site before the app goes
+		return m, tea.Sequence(readCmd, tea.Quit)
+	case "r":
+		if m.selected < len(visible) && !visible[m.selected].Read {
+			var cmd tea.Cmd
+			m, cmd = m.markRead([]string{visible[m.selected].ID})
+			m.viewport.SetContent(m.renderContent())
+			return m, cmd
+		}
+	case "R":
+		var ids []string
+		for _, n := range visible {
+			if !n.Read {
+				ids = append(ids, n.ID)
+			}
+		}
+		if len(ids) > 0 {
+			return m, confirmCmd(markAllReadMsg{ids: ids}, "Mark all %d unread read?", len(ids))
+		}
+	}
+	m.viewport.SetContent(m.renderContent())
+	m = m.scrollPopup()
+	return m, nil
+}
+
+// scrollPopup keeps the selected line in view
+func (m model) scrollPopup() model {
+	switch {
+	case m.selected < m.viewport.YOffset:
+		m.viewport.SetYOffset(m.selected)
+	case m.selected >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(m.selected - m.viewport.Height + 1)
+	}
+	return m
+}
+
+// renderPopupList is one line per notification: read mark, age, title
+func (m model) renderPopupList() string {
+	visible := m.inbox.apply(m.notifications)
+	if len(visible) == 0 {
+		return "No notifications"
+	}
+	var b strings.Builder
+	now := time.Now()
+	for i, n := range visible {
+		status := unreadDotStyle.String()
+		if n.Read {
+			status = readDotStyle.String()
+		}
+		age := formatAge(now.Sub(time.Unix(n.Date, 0)))
+		title := truncate(n.Title, max(m.viewport.Width-8, 10))
+		line := fmt.Sprintf("%s %4s %s", status, age, m.keywords.highlight(title))
+		switch {
+		case i == m.selected:
+			line = popupSelectedStyle.Render(fmt.Sprintf("%s %4s %s", status, age, title))
+		case m.highlighted[n.ID]:
+			line = popupHighlightStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (m model) popupView() string {
+	header := popupHeaderStyle.Render("speedrun.com") + fmt.Sprintf(" • %d unread", m.unreadCount)
+	hints := popupHints
+	switch {
+	case m.confirm != nil:
+		hints = m.confirm.view()
+	case m.status != "":
+		hints = m.status
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, m.viewport.View(), urlStyle.Render(hints))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// With -print-on-exit, quitting with q prints the item selected at the
+// time to stdout, so the app can be used inside $(...). ctrl+c quits
+// without printing.
+
+// printFormats are what -print-on-exit takes
+var printFormats = map[string]bool{"url": true, "json": true}
+
+// exitItem is the item selected when the app quit and its URL
+func (m model) exitItem() (any, string, bool) {
+	if m.popup {
+		// The popup lists the inbox as it is, without pins or day headers
+		visible := m.inbox.apply(m.notifications)
+		if m.selected >= len(visible) {
+			return nil, "", false
+		}
+		n := visible[m.selected]
+		return n, "https://www.speedrun.com" + n.Path, true
+	}
+	if m.screen == screenNotifications && m.detail != nil {
+		return struct {
+			URL string `json:"url"`
+		}{m.detail.url}, m.detail.url, true
+	}
+	if m.screen == screenNotifications && m.selected < len(m.pins) {
+		p := m.pins[m.selected]
+		return p, p.URL, true
+	}
+	item, ok := m.pluginTarget()
+	if !ok {
+		return nil, "", false
+	}
+	switch item := item.(type) {
+	case Notification:
+		return item, "https://www.speedrun.com" + item.Path, true
+	case yankedRun:
+		return item, item.URL, true
+	}
+	return nil, "", false
+}
+
+// printExitItem writes the selected item to w as a URL or a line of JSON,
+// reporting whether there was one
+func (m model) printExitItem(w io.Writer, format string) (bool, error) {
+	if m.cancelled {
+		return false, nil
+	}
+	item, url, ok := m.exitItem()
+	if !ok {
+		return false, nil
+	}
+	if format == "json" {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return false, fmt.Errorf("encoding the selected item: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(raw))
+		return true, err
+	}
+	_, err := fmt.Fprintln(w, url)
+	return true, err
+}
+
+// openTerminal is the terminal itself, for drawing the app on when stdout
+// is being captured
+func openTerminal() (*os.File, error) {
+	name := "/dev/tty"
+	if runtime.GOOS == "windows" {
+		name = "CONOUT$"
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening the terminal: %w", err)
+	}
+	// Styles pick their colors from whatever they're drawn on
+	lipgloss.DefaultRenderer().SetOutput(termenv.NewOutput(f))
+	return f, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Q shows the selected item's link as a QR code, for opening it on a
+// phone. Runs with a video link to the video.
+
+// qrOverlay is a QR code shown over the screen until the next key
+type qrOverlay struct {
+	url  string
+	code string
+}
+
+func newQROverlay(url string) (*qrOverlay, error) {
+	q, err := qrcode.New(url, qrcode.Low)
+	if err != nil {
+		return nil, fmt.Errorf("making QR code: %w", err)
+	}
+	// Light modules are the blocks, so the code reads the right way round
+	// on a dark terminal
+	return &qrOverlay{url: url, code: q.ToSmallString(false)}, nil
+}
+
+// qrURL is the link Q shows for the selected item
+func (m model) qrURL() (string, bool) {
+	item, url, ok := m.exitItem()
+	if !ok {
+		return "", false
+	}
+	if r, ok := item.(yankedRun); ok && r.Video != "" {
+		return r.Video, true
+	}
+	return url, true
+}
+
+func (o *qrOverlay) view(width, height int) string {
+	code := strings.TrimRight(o.code, "\n")
+	body := lipgloss.JoinVertical(lipgloss.Center, code, "", urlStyle.Render(o.url), "", "any key closes")
+	if lipgloss.Height(body) > height || lipgloss.Width(code) > width {
+		body = "The terminal is too small for the QR code\n\n" + urlStyle.Render(o.url) + "\n\nany key closes"
+	}
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, body)
+}
@@ -0,0 +1,1182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// Verified runs from this far back set the queue's throughput
+	throughputWindow = 14 * 24 * time.Hour
+
+	v1PageSize = 200
+
+	trustTTL = 24 * time.Hour
+)
+
+// Age buckets for pending runs, oldest last
+var queueAgeBuckets = []struct {
+	max   time.Duration
+	style lipgloss.Style
+}{
+	{3 * 24 * time.Hour, lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))},
+	{7 * 24 * time.Hour, lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700"))},
+	{14 * 24 * time.Hour, lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9F43"))},
+	{0, lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555")).Bold(true)},
+}
+
+func ageStyle(age time.Duration) lipgloss.Style {
+	for _, b := range queueAgeBuckets {
+		if b.max == 0 || age < b.max {
+			return b.style
+		}
+	}
+	return lipgloss.NewStyle()
+}
+
+// formatAge is a compact "how long ago": 45m, 5h, 3d
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// QueueRun is a run waiting for verification
+type QueueRun struct {
+	ID         string
+	Weblink    string
+	GameID     string
+	CategoryID string
+	LevelID    string
+	Players    []string
+	PlayerIDs  []string // registered players only
+	Runners    []Runner
+	Trust      trustLevel
+	Time       time.Duration
+	Submitted  time.Time
+	Video      string
+	Comment    string
+	PlatformID string
+	Emulated   bool
+	Values     map[string]string // variable ID -> value ID
+}
+
+// v1Run is a run as returned by the v1 runs endpoint with players embedded
+type v1Run struct {
+	ID       string `json:"id"`
+	Weblink  string `json:"weblink"`
+	Game     string `json:"game"`
+	Level    string `json:"level"`
+	Category string `json:"category"`
+	Videos   *struct {
+		Links []struct {
+			URI string `json:"uri"`
+		} `json:"links"`
+	} `json:"videos"`
+	Comment string `json:"comment"`
+	Status  struct {
+		Status     string `json:"status"`
+		Examiner   string `json:"examiner"`
+		VerifyDate string `json:"verify-date"`
+		Reason     string `json:"reason"` // rejections
+	} `json:"status"`
+	Players struct {
+		Data []struct {
+			ID    string `json:"id"`
+			Name  string `json:"name"` // guests
+			Names struct {
+				International string `json:"international"`
+			} `json:"names"`
+			Role      string       `json:"role"`
+			Pronouns  string       `json:"pronouns"`
+			NameStyle *v1NameStyle `json:"name-style"`
+			Animated  bool         `json:"supporterAnimation"`
+			Location  *struct {
+				Country struct {
+					Code string `json:"code"` // e.g. "se" or "us/ca"
+				} `json:"country"`
+			} `json:"location"`
+		} `json:"data"`
+	} `json:"players"`
+	Submitted string `json:"submitted"`
+	Date      string `json:"date"` // when it was played, YYYY-MM-DD
+	Times     struct {
+		Primary float64 `json:"primary_t"`
+	} `json:"times"`
+	System struct {
+		Platform string `json:"platform"`
+		Emulated bool   `json:"emulated"`
+	} `json:"system"`
+	Values map[string]string `json:"values"`
+	Splits *struct {
+		URI string `json:"uri"`
+	} `json:"splits"`
+}
+
+func (r v1Run) queueRun() QueueRun {
+	q := QueueRun{
+		ID:         r.ID,
+		Weblink:    r.Weblink,
+		GameID:     r.Game,
+		CategoryID: r.Category,
+		LevelID:    r.Level,
+		Time:       time.Duration(r.Times.Primary * float64(time.Second)).Round(time.Millisecond),
+		Comment:    r.Comment,
+		PlatformID: r.System.Platform,
+		Emulated:   r.System.Emulated,
+		Values:     r.Values,
+	}
+	q.Submitted, _ = time.Parse(time.RFC3339, r.Submitted)
+	if r.Videos != nil && len(r.Videos.Links) > 0 {
+		q.Video = r.Videos.Links[0].URI
+	}
+	for _, p := range r.Players.Data {
+		name := p.Names.International
+		if name == "" {
+			name = p.Name
+		}
+		q.Players = append(q.Players, name)
+		runner := Runner{Name: name, Pronouns: p.Pronouns, Role: v1Role(p.Role), Colors: p.NameStyle.colors()}
+		runner.Supporter = runner.Colors.gradient() || p.Animated
+		if p.Location != nil {
+			runner.Country = strings.SplitN(p.Location.Country.Code, "/", 2)[0]
+		}
+		q.Runners = append(q.Runners, runner)
+		if p.ID != "" {
+			q.PlayerIDs = append(q.PlayerIDs, p.ID)
+		}
+	}
+	return q
+}
+
+type v1Pagination struct {
+	Offset int `json:"offset"`
+	Max    int `json:"max"`
+	Size   int `json:"size"`
+}
+
+// listRuns pages through a v1 runs query, stopping at the end of the
+// results or at the first run keep rejects.
+func (c *Client) listRuns(q url.Values, keep func(v1Run) bool) ([]v1Run, error) {
+	var runs []v1Run
+	q.Set("max", fmt.Sprint(v1PageSize))
+	for offset := 0; ; offset += v1PageSize {
+		q.Set("offset", fmt.Sprint(offset))
+
+		var result struct {
+			Data       []v1Run      `json:"data"`
+			Pagination v1Pagination `json:"pagination"`
+		}
+		if err := c.getV1("/runs?"+q.Encode(), &result); err != nil {
+			return nil, err
+		}
+
+		for _, r := range result.Data {
+			if keep != nil && !keep(r) {
+				return runs, nil
+			}
+			runs = append(runs, r)
+		}
+		if result.Pagination.Size < v1PageSize {
+			return runs, nil
+		}
+	}
+}
+
+// GetPendingRuns returns every run of a game waiting for verification,
+// oldest first.
+func (c *Client) GetPendingRuns(gameID string) ([]QueueRun, error) {
+	q := url.Values{
+		"game":      {gameID},
+		"status":    {"new"},
+		"orderby":   {"submitted"},
+		"direction": {"asc"},
+		"embed":     {"players"},
+	}
+	result, err := c.listRuns(q, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pending runs: %w", err)
+	}
+
+	runs := make([]QueueRun, len(result))
+	for i, r := range result {
+		runs[i] = r.queueRun()
+	}
+	return runs, nil
+}
+
+// GetVerifiedSince returns a game's runs verified after since, newest
+// first.
+func (c *Client) GetVerifiedSince(gameID string, since time.Time) ([]v1Run, error) {
+	q := url.Values{
+		"game":      {gameID},
+		"status":    {"verified"},
+		"orderby":   {"verify-date"},
+		"direction": {"desc"},
+	}
+	runs, err := c.listRuns(q, func(r v1Run) bool {
+		verified, err := time.Parse(time.RFC3339, r.Status.VerifyDate)
+		return err != nil || !verified.Before(since)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching verified runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetModeratedGames lists the games a user moderates
+func (c *Client) GetModeratedGames(userID string) ([]Game, error) {
+	q := url.Values{
+		"moderator": {userID},
+		"max":       {"200"},
+	}
+	var result struct {
+		Data []struct {
+			ID    string `json:"id"`
+			Names struct {
+				International string `json:"international"`
+			} `json:"names"`
+			Abbreviation string `json:"abbreviation"`
+		} `json:"data"`
+	}
+	if err := c.getV1("/games?"+q.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("fetching moderated games: %w", err)
+	}
+
+	games := make([]Game, len(result.Data))
+	for i, g := range result.Data {
+		games[i] = Game{ID: g.ID, Name: g.Names.International, URL: g.Abbreviation}
+	}
+	return games, nil
+}
+
+// loadTrust fills in how established each run's runners are in its game.
+// A run is only as trusted as its least known runner. Counts are cached
+// for a day since they only ever go up.
+func loadTrust(client *Client, runs []QueueRun) error {
+	cache, err := openCache("trust")
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int) // game/user -> verified runs
+	var mu sync.Mutex
+	var jobs []Job
+	for _, r := range runs {
+		for _, id := range r.PlayerIDs {
+			key := r.GameID + "-" + id
+			if _, ok := counts[key]; ok {
+				continue
+			}
+			var n int
+			if cache.load(key, trustTTL, &n) {
+				counts[key] = n
+				continue
+			}
+			counts[key] = 0
+			jobs = append(jobs, Job{Name: "runs of " + id, Host: hostOf(client.baseURL), Run: func() error {
+				n, err := client.CountVerifiedRuns(id, r.GameID, trustRegularRuns)
+				if err != nil {
+					return err
+				}
+				cache.store(key, n)
+				mu.Lock()
+				counts[key] = n
+				mu.Unlock()
+				return nil
+			}})
+		}
+	}
+	err = NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs)
+
+	for i, r := range runs {
+		if len(r.PlayerIDs) < len(r.Players) {
+			// Guests have no history to go on
+			runs[i].Trust = trustNew
+			continue
+		}
+		level := trustRegular
+		for _, id := range r.PlayerIDs {
+			level = min(level, trustFor(counts[r.GameID+"-"+id]))
+		}
+		runs[i].Trust = level
+	}
+	return err
+}
+
+// queueStats summarises one game's queue
+type queueStats struct {
+	Pending int
+	PerDay  float64 // runs verified per day over throughputWindow
+}
+
+// ETA estimates how long the current backlog takes to clear at the recent
+// verification rate, or false if nothing was verified recently.
+func (s queueStats) ETA() (time.Duration, bool) {
+	if s.PerDay == 0 {
+		return 0, false
+	}
+	return time.Duration(float64(s.Pending) / s.PerDay * float64(24*time.Hour)), true
+}
+
+type queueMsg struct {
+	games      []Game
+	runs       []QueueRun
+	stats      map[string]queueStats
+	categories map[string]string
+	platforms  map[string]string
+	claims     map[string]Claim
+	me         string // the signed in user's name, who claims are made as
+	err        error
+}
+
+// moderatedGames resolves the configured game abbreviations, or asks the
+// site which games the signed in user moderates.
+func moderatedGames(client *Client, cache *GameCache, configured []string) ([]Game, error) {
+	if len(configured) == 0 {
+		session, err := client.GetSession()
+		if err != nil {
+			return nil, err
+		}
+		if !session.SignedIn || session.User == nil {
+			return nil, errors.New("session is not signed in")
+		}
+		return client.GetModeratedGames(session.User.ID)
+	}
+
+	var games []Game
+	for _, abbr := range configured {
+		data, err := cache.Get(abbr)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, data.Game)
+	}
+	return games, nil
+}
+
+// loadQueue fetches pending runs, throughput and category names for every
+// moderated game in parallel, and reads the claims file.
+func loadQueue(client *Client, cache *GameCache, configured []string, claimsFile string) queueMsg {
+	games, err := moderatedGames(client, cache, configured)
+	if err != nil {
+		return queueMsg{err: err}
+	}
+
+	msg := queueMsg{
+		games:      games,
+		stats:      make(map[string]queueStats),
+		categories: make(map[string]string),
+		platforms:  make(map[string]string),
+	}
+	var mu sync.Mutex
+	since := time.Now().Add(-throughputWindow)
+	host := hostOf(client.baseURL)
+
+	var jobs []Job
+	for _, g := range games {
+		jobs = append(jobs,
+			Job{Name: g.Name + " queue", Host: host, Run: func() error {
+				runs, err := client.GetPendingRuns(g.ID)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				msg.runs = append(msg.runs, runs...)
+				s := msg.stats[g.ID]
+				s.Pending = len(runs)
+				msg.stats[g.ID] = s
+				return nil
+			}},
+			Job{Name: g.Name + " throughput", Host: host, Run: func() error {
+				verified, err := client.GetVerifiedSince(g.ID, since)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				s := msg.stats[g.ID]
+				s.PerDay = float64(len(verified)) / (throughputWindow.Hours() / 24)
+				msg.stats[g.ID] = s
+				return nil
+			}},
+			Job{Name: g.Name + " categories", Host: host, Run: func() error {
+				data, err := cache.Get(g.URL)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, c := range data.Categories {
+					msg.categories[c.ID] = c.Name
+				}
+				for _, p := range data.Platforms {
+					msg.platforms[p.ID] = p.Name
+				}
+				return nil
+			}},
+		)
+	}
+	msg.err = NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs)
+	if msg.err == nil {
+		msg.err = loadTrust(client, msg.runs)
+	}
+
+	texts := make([]textEntry, 0, len(msg.runs))
+	for _, r := range msg.runs {
+		texts = append(texts, runCommentEntry(r))
+	}
+	if err := archiveTexts(texts); err != nil {
+		log.Printf("texts: %v", err)
+	}
+
+	sort.Slice(msg.runs, func(i, j int) bool {
+		return msg.runs[i].Submitted.Before(msg.runs[j].Submitted)
+	})
+
+	// Claims are a courtesy; the queue works without them
+	if claimsFile != "" {
+		if session, err := client.GetSession(); err == nil && session.SignedIn && session.User != nil {
+			msg.me = session.User.Name
+		}
+		claims, err := loadClaims(claimsFile)
+		if err != nil {
+			log.Printf("claims: %v", err)
+		} else {
+			msg.claims = claims
+			if msg.err == nil {
+				if err := pruneClaims(claimsFile, claims, msg.games, msg.runs); err != nil {
+					log.Printf("claims: %v", err)
+				}
+			}
+		}
+	}
+	return msg
+}
+
+// queueModel is the moderation tab: pending runs across moderated games,
+// oldest first, colored by how long they've been waiting.
+type queueModel struct {
+	client     *Client
+	games      *GameCache
+	configured []string
+	scripts    *scriptSet // for sort_queue
+
+	gameList   []Game
+	runs       []QueueRun
+	stats      map[string]queueStats
+	categories map[string]string
+	platforms  map[string]string
+	filter     queueFilter
+	saved      queueFilterState     // each game's filter, from last time
+	views      map[string]QueueView // queue_views, by game abbreviation
+	selected   int                  // index into visible()
+	loaded     bool
+	loading    bool
+	fetched    time.Time // when the runs last loaded
+	err        error
+
+	// Bulk verification: once confirmed, batch runs and stays on screen
+	// as a report until dismissed
+	batch *verifyBatch
+
+	rejectTemplates []RejectionTemplate
+	times           TimeFormat
+	flags           string
+	reject          *rejectDialog
+	keywords        *keywordHighlighter
+
+	// Notes taken on runs' videos, by run ID, and the one being typed
+	bookmarks map[string][]Bookmark
+	bookmark  *bookmarkDialog
+
+	retime *retimeDialog
+	// The start of a run marked in mpv with [, for ] to time it from
+	marked *retimeMark
+
+	// Who is looking at which run, by run ID, from the claims file
+	claimsPath string
+	claims     map[string]Claim
+	me         string
+
+	// Runs verified or rejected but not yet confirmed by the site, by ID.
+	// They leave the list right away and come back if the write fails.
+	hidden map[string]QueueRun
+
+	// The stats view replaces the run list while showStats is set
+	showStats    bool
+	modStats     []modStats
+	statsLoading bool
+	statsErr     error
+}
+
+func newQueueModel(client *Client, cfg Config, scripts *scriptSet) queueModel {
+	q := queueModel{
+		client:          client,
+		scripts:         scripts,
+		configured:      cfg.ModeratedGames,
+		rejectTemplates: cfg.RejectionTemplates,
+		times:           cfg.TimeFormat,
+		flags:           cfg.CountryFlags,
+		keywords:        newKeywordHighlighter(cfg.Keywords),
+		hidden:          map[string]QueueRun{},
+		views:           cfg.QueueViews,
+		saved:           loadQueueFilters(),
+	}
+	var err error
+	if q.bookmarks, err = loadBookmarks(); err != nil {
+		log.Printf("bookmarks: %v", err)
+	}
+	q.filter = q.saved.Filters[q.saved.Game]
+	q.filter.Game = q.saved.Game
+	q.games, q.err = NewGameCache(client)
+	// Without a config dir claims are off
+	q.claimsPath, _ = claimsPath(cfg.ClaimsFile)
+	return q
+}
+
+func (q queueModel) loadCmd() tea.Cmd {
+	client, cache, configured, claimsFile := q.client, q.games, q.configured, q.claimsPath
+	return func() tea.Msg {
+		return loadQueue(client, cache, configured, claimsFile)
+	}
+}
+
+func (q queueModel) activate() (queueModel, tea.Cmd) {
+	if q.loaded || q.loading || q.games == nil {
+		return q, nil
+	}
+	q.loading = true
+	return q, q.loadCmd()
+}
+
+// visible is the queue after filtering
+func (q queueModel) visible() []QueueRun {
+	var runs []QueueRun
+	for _, r := range q.runs {
+		if q.filter.match(r) {
+			runs = append(runs, r)
+		}
+	}
+	return runs
+}
+
+// options lists the distinct values of a run field present in the queue,
+// in queue order, from the game shown when there is one
+func (q queueModel) options(field func(QueueRun) string) []string {
+	seen := make(map[string]bool)
+	var opts []string
+	for _, r := range q.runs {
+		if q.filter.Game != "" && r.GameID != q.filter.Game {
+			continue
+		}
+		if v := field(r); v != "" && !seen[v] {
+			seen[v] = true
+			opts = append(opts, v)
+		}
+	}
+	return opts
+}
+
+// capturing reports whether a prompt wants every key, including the ones
+// the tab would otherwise handle itself
+func (q queueModel) capturing() bool {
+	return q.reject != nil || q.bookmark != nil || q.retime != nil
+}
+
+func (q queueModel) busy() bool {
+	return q.batch != nil && !q.batch.done()
+}
+
+func (q queueModel) update(msg tea.Msg) (queueModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case queueMsg:
+		q.loading = false
+		q.loaded = true
+		q.err = msg.err
+		if msg.err == nil {
+			q.fetched = time.Now()
+		}
+		q.gameList = msg.games
+		q.runs = msg.runs
+		q.stats = msg.stats
+		q.categories = msg.categories
+		q.platforms = msg.platforms
+		q.claims = msg.claims
+		q.me = msg.me
+		q.scripts.sortQueue(q.runs, q.scriptRun)
+		// A reload can race a write still on its way
+		for _, r := range q.hidden {
+			q.remove(r)
+		}
+		q.clampSelection()
+
+	case modStatsMsg:
+		q.statsLoading = false
+		q.statsErr = msg.err
+		q.modStats = msg.stats
+
+	case claimsMsg:
+		if msg.err != nil {
+			return q, statusCmd("Claiming failed: %v", msg.err)
+		}
+		q.claims = msg.claims
+		return q, statusCmd("%s", msg.status)
+
+	case verifyResultMsg:
+		return q.verified(verifyResult(msg))
+
+	case verifyAllMsg:
+		return q.startBatch()
+
+	case rejectConfirmedMsg:
+		// Gone from the list straight away; it comes back if the site
+		// refuses
+		q.hide(msg.run)
+		return q, tea.Batch(rejectRunCmd(q.client, msg.run, msg.reason),
+			statusCmd("Rejected run by %s", strings.Join(msg.run.Players, ", ")))
+
+	case rejectResultMsg:
+		who := strings.Join(msg.run.Players, ", ")
+		switch {
+		case msg.queued:
+			return q, statusCmd("Couldn't reject run by %s yet, will retry: %v", who, msg.err)
+		case msg.err != nil:
+			q.restore(msg.run.ID)
+			return q, statusCmd("Rejecting failed, run by %s is back in the queue: %v", who, msg.err)
+		}
+		q.confirmed(msg.run)
+
+	case bookmarkPositionMsg:
+		if msg.err != nil {
+			return q, statusCmd("Couldn't bookmark: %v", msg.err)
+		}
+		var cmd tea.Cmd
+		q.bookmark, cmd = newBookmarkDialog(msg.run, msg.at)
+		return q, cmd
+
+	case bookmarkedMsg:
+		if msg.err != nil {
+			return q, statusCmd("Saving the bookmark failed: %v", msg.err)
+		}
+		q.bookmarks[msg.run.ID] = msg.marks
+		return q, statusCmd("Bookmarked the run by %s, %d notes so far", strings.Join(msg.run.Players, ", "), len(msg.marks))
+
+	case mpvMarkMsg:
+		return q.mpvMarked(msg)
+
+	case retimedMsg:
+		if msg.marks != nil {
+			q.bookmarks[msg.run.ID] = msg.marks
+		}
+		if msg.err != nil {
+			return q, statusCmd("Retimed to %s, but copying failed: %v", formatRunTime(msg.time), msg.err)
+		}
+		if msg.marks != nil {
+			return q, statusCmd("Retimed to %s (submitted as %s), copied and added to the video notes", formatRunTime(msg.time), formatRunTime(msg.run.Time))
+		}
+		return q, statusCmd("Retimed to %s (submitted as %s), copied", formatRunTime(msg.time), formatRunTime(msg.run.Time))
+
+	case tea.KeyMsg:
+		if q.showStats {
+			return q.updateStats(msg)
+		}
+		if q.retime != nil {
+			var cmd tea.Cmd
+			q.retime, cmd = q.retime.update(msg)
+			return q, cmd
+		}
+		if q.bookmark != nil {
+			var cmd tea.Cmd
+			q.bookmark, cmd = q.bookmark.update(msg)
+			return q, cmd
+		}
+		if q.reject != nil {
+			var cmd tea.Cmd
+			q.reject, cmd = q.reject.update(msg, q.client)
+			return q, cmd
+		}
+		if q.busy() {
+			return q, nil
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if q.selected > 0 {
+				q.selected--
+			}
+		case "down", "j":
+			if q.selected < len(q.visible())-1 {
+				q.selected++
+			}
+		case "enter":
+			if r, ok := q.selectedRun(); ok {
+				openBrowser(r.Weblink)
+			}
+		case "r":
+			if !q.loading {
+				q.loading = true
+				q.batch = nil
+				return q, q.loadCmd()
+			}
+		case "s":
+			q.showStats = true
+			if q.modStats == nil && !q.statsLoading && q.games != nil {
+				q.statsLoading = true
+				return q, q.loadStatsCmd(false)
+			}
+		case "g":
+			return q.showGame(cycle(q.filter.Game, q.options(func(r QueueRun) string { return r.GameID })))
+		case "c":
+			q.filter.Category = cycle(q.filter.Category, q.options(func(r QueueRun) string { return r.CategoryID }))
+			return q.saveFilter()
+		case "p":
+			q.filter.Platform = cycle(q.filter.Platform, q.options(func(r QueueRun) string { return r.PlatformID }))
+			return q.saveFilter()
+		case "t":
+			q.filter.MinTrust = (q.filter.MinTrust + 1) % trustLevel(len(trustNames))
+			return q.saveFilter()
+		case "a":
+			q.filter.MinAge = nextAge(q.filter.MinAge)
+			return q.saveFilter()
+		case "F":
+			q.filter = q.defaultFilter(q.filter.Game)
+			return q.saveFilter()
+		case "V":
+			runs, claimed := q.unclaimed()
+			switch {
+			case len(runs) > 0 && claimed > 0:
+				return q, confirmCmd(verifyAllMsg{}, "Verify %d runs shown? %d claimed by others are left alone", len(runs), claimed)
+			case len(runs) > 0:
+				return q, confirmCmd(verifyAllMsg{}, "Verify all %d runs shown?", len(runs))
+			case claimed > 0:
+				return q, statusCmd("Every run shown is claimed by someone else")
+			}
+		case "R":
+			if r, ok := q.selectedRun(); ok {
+				q.reject = newRejectDialog(r, q.rejectTemplates, q.gameName(r.GameID), q.categories[r.CategoryID], q.formatTime(r))
+				if c, ok := q.otherClaim(r); ok {
+					return q, statusCmd("Heads up: %s claimed this run %s ago", c.By, formatAge(time.Since(c.At)))
+				}
+			}
+		case "C":
+			if r, ok := q.selectedRun(); ok {
+				if q.claimsPath == "" || q.me == "" {
+					return q, statusCmd("Claims need a signed in session")
+				}
+				return q, toggleClaimCmd(q.claimsPath, r, q.me)
+			}
+		case "x":
+			q.batch = nil
+		case "n":
+			if r, ok := q.selectedRun(); ok {
+				return q, bookmarkCmd(r)
+			}
+		case "N":
+			if r, ok := q.selectedRun(); ok {
+				return q, copyBookmarksCmd(q.bookmarks[r.ID])
+			}
+		case "[", "]":
+			if r, ok := q.selectedRun(); ok {
+				return q, mpvMarkCmd(r, msg.String() == "]")
+			}
+		case "T":
+			if r, ok := q.selectedRun(); ok {
+				var cmd tea.Cmd
+				q.retime, cmd = newRetimeDialog(r)
+				return q, cmd
+			}
+		}
+
+	default:
+		if q.retime != nil {
+			var cmd tea.Cmd
+			q.retime, cmd = q.retime.update(msg)
+			return q, cmd
+		}
+		if q.bookmark != nil {
+			var cmd tea.Cmd
+			q.bookmark, cmd = q.bookmark.update(msg)
+			return q, cmd
+		}
+		// Cursor blinks for the rejection text
+		if q.reject != nil && q.reject.editing {
+			var cmd tea.Cmd
+			q.reject.input, cmd = q.reject.input.Update(msg)
+			return q, cmd
+		}
+	}
+	return q, nil
+}
+
+// mpvMarked keeps the start of a run marked in mpv, or times the run once
+// its end is marked too, noting the time on the run
+func (q queueModel) mpvMarked(msg mpvMarkMsg) (queueModel, tea.Cmd) {
+	if msg.err != nil {
+		return q, statusCmd("Couldn't mark the video: %v", msg.err)
+	}
+	frame := frameAt(msg.at, msg.fps)
+	if !msg.end {
+		q.marked = &retimeMark{runID: msg.run.ID, frame: frame, fps: msg.fps}
+		return q, statusCmd("Start marked at %s (frame %d), ] marks the end", formatClock(msg.at), frame)
+	}
+	start := q.marked
+	if start == nil || start.runID != msg.run.ID {
+		return q, statusCmd("Mark the start with [ first")
+	}
+	t, err := retime(start.fps, start.frame, frame)
+	if err != nil {
+		return q, statusCmd("Couldn't retime: %v", err)
+	}
+	q.marked = nil
+	return q, retimeNoteCmd(msg.run, *start, frame, msg.at, t)
+}
+
+func (q *queueModel) clampSelection() {
+	if n := len(q.visible()); q.selected >= n {
+		q.selected = max(n-1, 0)
+	}
+}
+
+// unclaimed is the runs shown that nobody else has claimed, and how many
+// were left out
+func (q queueModel) unclaimed() ([]QueueRun, int) {
+	var runs []QueueRun
+	claimed := 0
+	for _, r := range q.visible() {
+		if _, ok := q.otherClaim(r); ok {
+			claimed++
+			continue
+		}
+		runs = append(runs, r)
+	}
+	return runs, claimed
+}
+
+func (q queueModel) startBatch() (queueModel, tea.Cmd) {
+	runs, _ := q.unclaimed()
+	if len(runs) == 0 {
+		return q, nil
+	}
+	q.batch = &verifyBatch{runs: runs}
+	for _, r := range runs {
+		q.hide(r)
+	}
+	return q, verifyRunCmd(q.client, runs[0])
+}
+
+// verified records one batch result, drops the run from the queue if it
+// went through and moves on to the next
+func (q queueModel) verified(res verifyResult) (queueModel, tea.Cmd) {
+	if q.batch == nil {
+		// A single run, verified by a binding
+		who := strings.Join(res.run.Players, ", ")
+		switch {
+		case res.queued:
+			return q, statusCmd("Couldn't verify run by %s yet, will retry: %v", who, res.err)
+		case res.err != nil:
+			q.restore(res.run.ID)
+			return q, statusCmd("Verifying failed, run by %s is back in the queue: %v", who, res.err)
+		}
+		q.confirmed(res.run)
+		return q, nil
+	}
+	q.batch.results = append(q.batch.results, res)
+
+	switch {
+	case res.err == nil:
+		q.confirmed(res.run)
+	case !res.queued:
+		q.restore(res.run.ID)
+	}
+
+	if q.batch.done() {
+		return q, nil
+	}
+	return q, verifyRunCmd(q.client, q.batch.runs[len(q.batch.results)])
+}
+
+// verifySelected verifies the run under the cursor, for bindings
+func (q queueModel) verifySelected() (queueModel, tea.Cmd) {
+	r, ok := q.selectedRun()
+	if !ok || q.busy() {
+		return q, nil
+	}
+	q.hide(r)
+	status := statusCmd("Verified run by %s", strings.Join(r.Players, ", "))
+	if c, ok := q.otherClaim(r); ok {
+		status = statusCmd("Verified run by %s, though %s claimed it %s ago", strings.Join(r.Players, ", "), c.By, formatAge(time.Since(c.At)))
+	}
+	return q, tea.Batch(verifyRunCmd(q.client, r), status)
+}
+
+// rejectSelected rejects the run under the cursor with a rejection
+// template as it is, for bindings
+func (q queueModel) rejectSelected(template int) (queueModel, tea.Cmd) {
+	r, ok := q.selectedRun()
+	if !ok || q.busy() {
+		return q, nil
+	}
+	d := newRejectDialog(r, q.rejectTemplates, q.gameName(r.GameID), q.categories[r.CategoryID], q.formatTime(r))
+	if template >= len(d.templates) {
+		return q, nil
+	}
+	return q.update(rejectConfirmedMsg{run: r, reason: d.templates[template].expand(d.vars)})
+}
+
+// remove drops a run that's been dealt with from the queue
+func (q *queueModel) remove(run QueueRun) {
+	for i, r := range q.runs {
+		if r.ID == run.ID {
+			q.runs = append(q.runs[:i:i], q.runs[i+1:]...)
+			if s, ok := q.stats[run.GameID]; ok {
+				s.Pending--
+				q.stats[run.GameID] = s
+			}
+			break
+		}
+	}
+	q.clampSelection()
+}
+
+// hide removes a run as soon as a write about it is sent
+func (q *queueModel) hide(run QueueRun) {
+	q.hidden[run.ID] = run
+	q.remove(run)
+}
+
+// confirmed forgets a run the site has dealt with
+func (q *queueModel) confirmed(run QueueRun) {
+	delete(q.hidden, run.ID)
+	q.remove(run)
+}
+
+// restore puts a hidden run back where it was, after its write failed
+func (q *queueModel) restore(id string) {
+	run, ok := q.hidden[id]
+	if !ok {
+		return
+	}
+	delete(q.hidden, id)
+	i := sort.Search(len(q.runs), func(i int) bool { return q.runs[i].Submitted.After(run.Submitted) })
+	q.runs = slices.Insert(q.runs, i, run)
+	if s, ok := q.stats[run.GameID]; ok {
+		s.Pending++
+		q.stats[run.GameID] = s
+	}
+}
+
+func (q queueModel) updateStats(msg tea.KeyMsg) (queueModel, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		q.showStats = false
+	case "r":
+		if !q.statsLoading {
+			q.statsLoading = true
+			return q, q.loadStatsCmd(true)
+		}
+	}
+	return q, nil
+}
+
+func (q queueModel) selectedRun() (QueueRun, bool) {
+	runs := q.visible()
+	if q.showStats || q.selected >= len(runs) {
+		return QueueRun{}, false
+	}
+	return runs[q.selected], true
+}
+
+func (q queueModel) gameName(id string) string {
+	for _, g := range q.gameList {
+		if g.ID == id {
+			return g.Name
+		}
+	}
+	return id
+}
+
+// formatTime renders a run time in the configured format, with
+// milliseconds if the run's game times them
+func (q queueModel) formatTime(r QueueRun) string {
+	millis := false
+	for _, g := range q.gameList {
+		if g.ID == r.GameID {
+			millis = g.Milliseconds
+		}
+	}
+	return q.times.render(r.Time, millis)
+}
+
+// summary is the per-game backlog line: pending count, rate and ETA
+func (q queueModel) summary() string {
+	var parts []string
+	for _, g := range q.gameList {
+		s := q.stats[g.ID]
+		part := fmt.Sprintf("%s: %d pending", g.Name, s.Pending)
+		if eta, ok := s.ETA(); ok {
+			part += fmt.Sprintf(", %.1f/day, clears in ~%s", s.PerDay, formatAge(eta))
+		} else if s.Pending > 0 {
+			part += ", none verified recently"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// filterLine describes the active filter, e.g. "Super Mario 64 • 120 Star
+// • N64 • returning+ runners • 3d+ old"
+func (q queueModel) filterLine() string {
+	var parts []string
+	if q.filter.Game != "" {
+		parts = append(parts, q.gameName(q.filter.Game))
+	}
+	if q.filter.Category != "" {
+		parts = append(parts, q.categories[q.filter.Category])
+	}
+	if q.filter.Platform != "" {
+		name := q.platforms[q.filter.Platform]
+		if name == "" {
+			name = q.filter.Platform
+		}
+		parts = append(parts, name)
+	}
+	if q.filter.MinTrust > trustNew {
+		parts = append(parts, q.filter.MinTrust.String()+"+ runners")
+	}
+	if q.filter.MinAge > 0 {
+		parts = append(parts, formatAge(q.filter.MinAge)+"+ old")
+	}
+	return fmt.Sprintf("Filter: %s (%d of %d runs)", strings.Join(parts, " • "), len(q.visible()), len(q.runs))
+}
+
+func (q queueModel) title() string {
+	if q.showStats {
+		return "Moderator stats"
+	}
+	return ""
+}
+
+func (q queueModel) view() string {
+	if q.showStats {
+		return q.statsView()
+	}
+	if q.loading {
+		return "Loading queue..."
+	}
+
+	var b strings.Builder
+	if q.err != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n\n", q.err))
+	}
+	if !q.loaded {
+		return b.String()
+	}
+	if len(q.gameList) == 0 {
+		b.WriteString("You don't moderate any games (or set moderated_games in the config)")
+		return b.String()
+	}
+
+	b.WriteString(urlStyle.Render(q.summary()))
+	b.WriteString("\n\n")
+
+	if q.batch != nil {
+		b.WriteString(q.batchView())
+		b.WriteString("\n")
+	}
+	if q.reject != nil {
+		b.WriteString(q.reject.view())
+		b.WriteString("\n")
+	}
+	if q.bookmark != nil {
+		b.WriteString(q.bookmark.view())
+		b.WriteString("\n")
+	}
+	if q.retime != nil {
+		b.WriteString(q.retime.view())
+		b.WriteString("\n")
+	}
+	if q.filter.active() {
+		b.WriteString(q.filterLine())
+		b.WriteString("\n")
+	}
+
+	runs := q.visible()
+	if len(runs) == 0 {
+		if len(q.runs) == 0 {
+			b.WriteString("Queue is empty")
+		} else {
+			b.WriteString("No runs match the filter")
+		}
+		return b.String()
+	}
+
+	now := time.Now()
+	for i, r := range runs {
+		age := now.Sub(r.Submitted)
+
+		var item strings.Builder
+		item.WriteString(fmt.Sprintf("[%s] %s • %s\n",
+			ageStyle(age).Render(formatAge(age)), q.gameName(r.GameID), q.categories[r.CategoryID]))
+		item.WriteString(fmt.Sprintf("%s by %s", q.formatTime(r), renderRunners(r.Runners, q.flags, 0)))
+		if badge := platformBadge(q.platforms[r.PlatformID], r.Emulated); badge != "" {
+			item.WriteString("  " + badge)
+		}
+		if r.Trust == trustNew {
+			item.WriteString(urlStyle.Render(" (new runner)"))
+		}
+		item.WriteString("\n")
+		if r.Comment != "" {
+			comment, _, _ := strings.Cut(r.Comment, "\n")
+			item.WriteString(q.keywords.highlight(truncate(comment, 80)))
+			item.WriteString("\n")
+		}
+		item.WriteString(urlStyle.Render(fmt.Sprintf("submitted %s", r.Submitted.Local().Format("2006-01-02 15:04"))))
+		if claim := q.claimLine(r); claim != "" {
+			item.WriteString(" • " + claim)
+		}
+		if n := len(q.bookmarks[r.ID]); n > 0 {
+			item.WriteString(urlStyle.Render(fmt.Sprintf(" • %d video notes", n)))
+		}
+
+		style := unselectedItemStyle
+		if i == q.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(item.String()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (q queueModel) help() string {
+	switch {
+	case q.showStats:
+		return "s back to queue • r recompute"
+	case q.reject != nil:
+		return q.reject.help()
+	case q.bookmark != nil:
+		return q.bookmark.help()
+	case q.retime != nil:
+		return q.retime.help()
+	case q.busy():
+		return "verifying..."
+	}
+	return "j/k navigate • enter open run • m comments • v play video • n bookmark video • N copy notes • T retime • [/] mark start/end in mpv • g/c/p/t/a filter game/category/platform/trust/age • F default filter • V verify all shown • R reject • C claim • b pin • y/Y copy • ! report • s stats • r refresh"
+}
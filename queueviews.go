@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Each game's queue filter, and the game the queue was showing, so the
+// queue opens the way it was left
+const queueFiltersFile = "queue_filters.json"
+
+// Ages a steps through: any, then the age buckets' edges
+var queueAgeSteps = []time.Duration{0, 3 * 24 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour}
+
+// QueueView is how a game's queue opens until its filter has been changed,
+// by names as the site shows them
+type QueueView struct {
+	Category   string `json:"category,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+	MinAgeDays int    `json:"min_age_days,omitempty"`
+	// Least trusted runners shown: "returning" or "regular"
+	Trust string `json:"trust,omitempty"`
+}
+
+func validateQueueViews(views map[string]QueueView) error {
+	for game, v := range views {
+		if v.MinAgeDays < 0 {
+			return fmt.Errorf("queue_views: %s: min_age_days can't be negative, got %d", game, v.MinAgeDays)
+		}
+		if _, err := parseTrust(v.Trust); err != nil {
+			return fmt.Errorf("queue_views: %s: %w", game, err)
+		}
+	}
+	return nil
+}
+
+// parseTrust reads a trust level by name, "" being any runner
+func parseTrust(name string) (trustLevel, error) {
+	if name == "" {
+		return trustNew, nil
+	}
+	for t, n := range trustNames {
+		if strings.EqualFold(n, name) {
+			return trustLevel(t), nil
+		}
+	}
+	return trustNew, fmt.Errorf("trust must be one of %s, got %q", strings.Join(trustNames[:], ", "), name)
+}
+
+type queueFilterState struct {
+	Game    string                 `json:"game,omitempty"`
+	Filters map[string]queueFilter `json:"filters,omitempty"` // by game ID, "" for every game
+}
+
+func loadQueueFilters() queueFilterState {
+	var s queueFilterState
+	if err := loadState(queueFiltersFile, &s); err != nil {
+		log.Printf("queue filters: %v", err)
+	}
+	if s.Filters == nil {
+		s.Filters = map[string]queueFilter{}
+	}
+	return s
+}
+
+// saveFilter remembers the filter for the game it's on
+func (q queueModel) saveFilter() (queueModel, tea.Cmd) {
+	q.clampSelection()
+	q.saved.Game = q.filter.Game
+	q.saved.Filters[q.filter.Game] = q.filter
+	if err := saveState(queueFiltersFile, q.saved); err != nil {
+		return q, statusCmd("Couldn't save the queue filter: %v", err)
+	}
+	return q, nil
+}
+
+// showGame narrows the queue to a game ("" for all of them) with the filter
+// it had last time, or its queue_views entry the first time
+func (q queueModel) showGame(id string) (queueModel, tea.Cmd) {
+	f, ok := q.saved.Filters[id]
+	if !ok {
+		f = q.defaultFilter(id)
+	}
+	f.Game = id
+	q.filter = f
+	return q.saveFilter()
+}
+
+// defaultFilter is a game's queue_views entry with its names looked up,
+// or no filter when it has none. Names the game doesn't have are left out.
+func (q queueModel) defaultFilter(id string) queueFilter {
+	f := queueFilter{Game: id}
+	idx := slices.IndexFunc(q.gameList, func(g Game) bool { return g.ID == id })
+	if idx < 0 {
+		return f
+	}
+	view, ok := q.views[q.gameList[idx].URL]
+	if !ok {
+		return f
+	}
+	f.MinAge = time.Duration(view.MinAgeDays) * 24 * time.Hour
+	f.MinTrust, _ = parseTrust(view.Trust)
+
+	if q.games == nil || (view.Category == "" && view.Platform == "") {
+		return f
+	}
+	data, err := q.games.Get(q.gameList[idx].URL)
+	if err != nil {
+		log.Printf("queue view: %v", err)
+		return f
+	}
+	for _, c := range data.Categories {
+		if strings.EqualFold(c.Name, view.Category) {
+			f.Category = c.ID
+		}
+	}
+	for _, p := range data.Platforms {
+		if strings.EqualFold(p.Name, view.Platform) || strings.EqualFold(platformShort(p.Name), view.Platform) {
+			f.Platform = p.ID
+		}
+	}
+	return f
+}
+
+// nextAge steps the age filter to the next of queueAgeSteps, back to any
+// after the last
+func nextAge(current time.Duration) time.Duration {
+	i := slices.Index(queueAgeSteps, current)
+	return queueAgeSteps[(i+1)%len(queueAgeSteps)]
+}
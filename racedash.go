@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A watched race refreshes this often, a lot more than the list
+const raceWatchInterval = 5 * time.Second
+
+var raceDNFStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FF5555"))
+
+// RaceEntrant is someone in a race, as racetime.gg reports them
+type RaceEntrant struct {
+	User struct {
+		Name       string `json:"name"`
+		TwitchName string `json:"twitch_name"`
+	} `json:"user"`
+	Status     RaceStatus `json:"status"` // requested, invited, declined, ready, not_ready, in_progress, done, dnf, dq
+	FinishTime string     `json:"finish_time"`
+	Place      int        `json:"place"`
+	Comment    string     `json:"comment"`
+	StreamLive bool       `json:"stream_live"`
+}
+
+// RaceDetail is a race with everyone in it
+type RaceDetail struct {
+	Race
+	Entrants []RaceEntrant `json:"entrants"`
+}
+
+// GetRace fetches a race's current state from its data URL
+func GetRace(dataURL string) (*RaceDetail, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(racetimeURL + dataURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching race: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching race: unexpected status code %d", resp.StatusCode)
+	}
+
+	var race RaceDetail
+	if err := json.NewDecoder(resp.Body).Decode(&race); err != nil {
+		return nil, fmt.Errorf("decoding race: %w", err)
+	}
+	return &race, nil
+}
+
+type raceMsg struct {
+	dataURL string
+	race    *RaceDetail
+	err     error
+}
+
+func fetchRaceCmd(dataURL string) tea.Cmd {
+	return func() tea.Msg {
+		race, err := GetRace(dataURL)
+		return raceMsg{dataURL: dataURL, race: race, err: err}
+	}
+}
+
+// racetime.gg's durations, e.g. P0DT01H23M45.678000S
+var raceDurationPattern = regexp.MustCompile(`^P(\d+)DT(\d+)H(\d+)M(\d+(?:\.\d+)?)S$`)
+
+func parseRaceDuration(s string) (time.Duration, bool) {
+	m := raceDurationPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	days, _ := strconv.Atoi(m[1])
+	hours, _ := strconv.Atoi(m[2])
+	minutes, _ := strconv.Atoi(m[3])
+	seconds, _ := strconv.ParseFloat(m[4], 64)
+	return time.Duration(days)*24*time.Hour + time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)), true
+}
+
+// raceWatch is one race followed live: every entrant's status and time,
+// refreshed every few seconds
+type raceWatch struct {
+	dataURL string
+	race    *RaceDetail
+	fetched time.Time
+	err     error
+}
+
+// watchTime is an entrant's finish time once they're done, and the race
+// clock while they're still running
+func (w *raceWatch) watchTime(e RaceEntrant, now time.Time) string {
+	if d, ok := parseRaceDuration(e.FinishTime); ok {
+		return formatRunTime(d.Round(time.Millisecond))
+	}
+	if e.Status.Value == "in_progress" && w.race.StartedAt != nil && now.After(*w.race.StartedAt) {
+		return formatClock(now.Sub(*w.race.StartedAt))
+	}
+	return ""
+}
+
+func (w *raceWatch) view(now time.Time) string {
+	if w.race == nil {
+		if w.err != nil {
+			return fmt.Sprintf("Error: %v", w.err)
+		}
+		return "Loading race..."
+	}
+
+	var b strings.Builder
+	race := w.race
+	b.WriteString(fmt.Sprintf("%s • %s\n", race.Category.Name, race.Goal.Name))
+	summary := race.Status.Verbose
+	if c := race.Countdown(now); c != "" {
+		summary += " • " + c
+	}
+	summary += fmt.Sprintf(" • %d entrants, %d finished", race.Race.Entrants, race.Finished)
+	b.WriteString(urlStyle.Render(summary) + "\n")
+	if race.Info != "" {
+		b.WriteString(urlStyle.Render(race.Info) + "\n")
+	}
+	if w.err != nil {
+		b.WriteString(fmt.Sprintf("Couldn't refresh: %v\n", w.err))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(fmt.Sprintf("%4s  %-24s %-12s %13s\n", "", "Entrant", "Status", "Time"))
+	for _, e := range race.Entrants {
+		place := ""
+		if e.Place > 0 {
+			place = strconv.Itoa(e.Place)
+		}
+		status := raceDoneStyle
+		switch e.Status.Value {
+		case "in_progress":
+			status = raceRunningStyle
+		case "done":
+			status = raceOpenStyle
+		case "dnf", "dq":
+			status = raceDNFStyle
+		}
+		line := fmt.Sprintf("%4s  %-24s %s %13s", place, truncate(e.User.Name, 24),
+			status.Render(fmt.Sprintf("%-12s", truncate(e.Status.Verbose, 12))), w.watchTime(e, now))
+		if e.StreamLive {
+			line += "  " + liveStyle.Render("● LIVE")
+		}
+		if e.Comment != "" {
+			line += "  " + urlStyle.Render(truncate(e.Comment, 40))
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(race.Entrants) == 0 {
+		b.WriteString(urlStyle.Render("Nobody has joined yet") + "\n")
+	}
+	return b.String()
+}
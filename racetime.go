@@ -0,0 +1,334 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	racetimeURL = "https://racetime.gg"
+
+	racesRefreshInterval = 30 * time.Second
+)
+
+var (
+	raceOpenStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#00FF00"))
+
+	raceRunningStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFD700"))
+
+	raceDoneStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#666666"))
+)
+
+type RaceStatus struct {
+	Value   string `json:"value"` // open, invitational, pending, in_progress, finished, cancelled
+	Verbose string `json:"verbose_value"`
+}
+
+type RaceCategory struct {
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	Slug      string `json:"slug"`
+}
+
+type RaceGoal struct {
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+}
+
+type Race struct {
+	Name          string       `json:"name"`
+	URL           string       `json:"url"`
+	DataURL       string       `json:"data_url"`
+	Status        RaceStatus   `json:"status"`
+	Category      RaceCategory `json:"category"`
+	Goal          RaceGoal     `json:"goal"`
+	Info          string       `json:"info"`
+	Entrants      int          `json:"entrants_count"`
+	Finished      int          `json:"entrants_count_finished"`
+	OpenedAt      *time.Time   `json:"opened_at"`
+	StartedAt     *time.Time   `json:"started_at"`
+	TimeLimit     string       `json:"time_limit"`
+	StartDelay    string       `json:"start_delay"`
+	Unlisted      bool         `json:"unlisted"`
+	StreamingReqd bool         `json:"streaming_required"`
+}
+
+// Countdown describes where the race is in time: how long until it starts
+// or how long it has been running.
+func (r Race) Countdown(now time.Time) string {
+	switch r.Status.Value {
+	case "pending":
+		if r.StartedAt != nil && r.StartedAt.After(now) {
+			return "starts in " + formatClock(r.StartedAt.Sub(now))
+		}
+		return "starting"
+	case "in_progress":
+		if r.StartedAt != nil {
+			return formatClock(now.Sub(*r.StartedAt))
+		}
+	case "open", "invitational":
+		if r.OpenedAt != nil {
+			return "open " + formatClock(now.Sub(*r.OpenedAt))
+		}
+	}
+	return ""
+}
+
+// formatClock is a whole second h:mm:ss clock for countdowns
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// GetRaces lists every current (not yet finished) public race on racetime.gg
+func GetRaces() ([]Race, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(racetimeURL + "/races/data")
+	if err != nil {
+		return nil, fmt.Errorf("fetching races: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching races: unexpected status code %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Races []Race `json:"races"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding races: %w", err)
+	}
+	return result.Races, nil
+}
+
+type racesMsg struct {
+	races []Race
+	err   error
+}
+
+type racesTickMsg time.Time
+
+func fetchRacesCmd() tea.Cmd {
+	return func() tea.Msg {
+		races, err := GetRaces()
+		return racesMsg{races: races, err: err}
+	}
+}
+
+func racesTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return racesTickMsg(t)
+	})
+}
+
+// racesModel is the races tab: open and running races for followed games
+type racesModel struct {
+	followed map[string]bool // racetime category slugs, empty means all
+	races    []Race
+	selected int
+	loaded   bool
+	ticking  bool
+	fetched  time.Time
+	now      time.Time
+	err      error
+
+	// The race being watched, shown instead of the list
+	watch *raceWatch
+}
+
+func newRacesModel(followed []string) racesModel {
+	r := racesModel{followed: make(map[string]bool)}
+	for _, slug := range followed {
+		r.followed[strings.ToLower(slug)] = true
+	}
+	return r
+}
+
+// activate is called when the tab is shown, starting the first fetch and
+// the countdown clock.
+func (r racesModel) activate() (racesModel, tea.Cmd) {
+	var cmds []tea.Cmd
+	if !r.loaded {
+		cmds = append(cmds, fetchRacesCmd())
+	}
+	if !r.ticking {
+		r.ticking = true
+		r.now = time.Now()
+		cmds = append(cmds, racesTick())
+	}
+	return r, tea.Batch(cmds...)
+}
+
+func (r racesModel) update(msg tea.Msg, visible bool) (racesModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case racesMsg:
+		r.loaded = true
+		r.fetched = time.Now()
+		r.err = msg.err
+		if msg.err == nil {
+			r.races = r.filter(msg.races)
+			if r.selected >= len(r.races) {
+				r.selected = max(len(r.races)-1, 0)
+			}
+		}
+
+	case raceMsg:
+		if r.watch == nil || msg.dataURL != r.watch.dataURL {
+			return r, nil
+		}
+		r.watch.err = msg.err
+		if msg.err == nil {
+			r.watch.race = msg.race
+		}
+
+	case racesTickMsg:
+		// Stop the clock while another tab is shown; activate restarts it
+		if !visible {
+			r.ticking = false
+			return r, nil
+		}
+		r.now = time.Time(msg)
+		cmds := []tea.Cmd{racesTick()}
+		if r.loaded && r.now.Sub(r.fetched) >= racesRefreshInterval {
+			r.fetched = r.now
+			cmds = append(cmds, fetchRacesCmd())
+		}
+		if r.watch != nil && r.now.Sub(r.watch.fetched) >= raceWatchInterval {
+			r.watch.fetched = r.now
+			cmds = append(cmds, fetchRaceCmd(r.watch.dataURL))
+		}
+		return r, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		if r.watch != nil {
+			return r.updateWatch(msg)
+		}
+		switch msg.String() {
+		case "up", "k":
+			if r.selected > 0 {
+				r.selected--
+			}
+		case "down", "j":
+			if r.selected < len(r.races)-1 {
+				r.selected++
+			}
+		case "enter":
+			if r.selected < len(r.races) {
+				race := r.races[r.selected]
+				r.watch = &raceWatch{dataURL: race.DataURL, fetched: time.Now()}
+				return r, fetchRaceCmd(race.DataURL)
+			}
+		case "o":
+			if r.selected < len(r.races) {
+				openBrowser(racetimeURL + r.races[r.selected].URL)
+			}
+		case "r":
+			return r, fetchRacesCmd()
+		}
+	}
+
+	return r, nil
+}
+
+func (r racesModel) updateWatch(msg tea.KeyMsg) (racesModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		r.watch = nil
+	case "o":
+		if r.watch.race != nil {
+			openBrowser(racetimeURL + r.watch.race.URL)
+		}
+	case "r":
+		r.watch.fetched = time.Now()
+		return r, fetchRaceCmd(r.watch.dataURL)
+	}
+	return r, nil
+}
+
+func (r racesModel) filter(races []Race) []Race {
+	var out []Race
+	for _, race := range races {
+		if race.Unlisted || race.Status.Value == "finished" || race.Status.Value == "cancelled" {
+			continue
+		}
+		if len(r.followed) > 0 && !r.followed[strings.ToLower(race.Category.Slug)] {
+			continue
+		}
+		out = append(out, race)
+	}
+	return out
+}
+
+func (r racesModel) view() string {
+	if r.watch != nil {
+		return r.watch.view(r.now)
+	}
+	if r.err != nil {
+		return fmt.Sprintf("Error: %v", r.err)
+	}
+	if !r.loaded {
+		return "Loading races..."
+	}
+	if len(r.races) == 0 {
+		if len(r.followed) > 0 {
+			return "No open or running races for your followed games"
+		}
+		return "No open or running races"
+	}
+
+	var b strings.Builder
+	for i, race := range r.races {
+		status := raceOpenStyle
+		switch race.Status.Value {
+		case "in_progress", "pending":
+			status = raceRunningStyle
+		case "finished", "cancelled":
+			status = raceDoneStyle
+		}
+
+		var item strings.Builder
+		item.WriteString(fmt.Sprintf("[%s] %s • %s\n",
+			status.Render(race.Status.Verbose), race.Category.Name, race.Countdown(r.now)))
+		item.WriteString(race.Goal.Name)
+		if race.Info != "" {
+			item.WriteString(" • " + race.Info)
+		}
+		item.WriteString("\n")
+		entrants := fmt.Sprintf("%d entrants", race.Entrants)
+		if race.Status.Value == "in_progress" {
+			entrants += fmt.Sprintf(", %d finished", race.Finished)
+		}
+		item.WriteString(urlStyle.Render(entrants + " • racetime.gg" + race.URL))
+
+		style := unselectedItemStyle
+		if i == r.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(item.String()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (r racesModel) help() string {
+	if r.watch != nil {
+		return "o open race room • r refresh • esc back"
+	}
+	return "j/k navigate • enter watch race • o open race room • r refresh"
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// How far back the Records tab calls a record new, unless record_days says
+const defaultRecordDays = 7
+
+var newRecordStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FFD700")).
+	Bold(true)
+
+// currentWRs fetches the record on the default board of every full game
+// category of the followed games, sorted by game then category order
+func currentWRs(client *Client, cache *GameCache, followed []string) ([]wrChange, error) {
+	var (
+		mu      sync.Mutex
+		records []wrChange
+		order   = make(map[string]int) // game and category -> position
+		jobs    []Job
+	)
+	host := hostOf(client.baseURL)
+	for _, abbr := range followed {
+		data, err := cache.Get(abbr)
+		if err != nil {
+			return nil, err
+		}
+		for _, cat := range data.Categories {
+			if cat.IsPerLevel || cat.IsMisc || cat.Archived {
+				continue
+			}
+			order[data.Game.Name+"\x00"+cat.Name] = len(order)
+			jobs = append(jobs, Job{Name: data.Game.Name + " " + cat.Name, Host: host, Run: func() error {
+				board, err := client.GetLeaderboard(defaultParams(data, cat), 1)
+				if err != nil {
+					return err
+				}
+				if len(board.Runs) == 0 || board.Runs[0].Place != 1 {
+					return nil
+				}
+				wr := board.Runs[0]
+				date := time.Unix(wr.DateVerified, 0)
+				if wr.DateVerified == 0 {
+					date = time.Unix(wr.Date, 0)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				records = append(records, wrChange{
+					Game:     data.Game.Name,
+					Category: cat.Name,
+					Time:     wr.Duration(),
+					Millis:   data.Game.Milliseconds,
+					Players:  board.PlayerNames(wr),
+					Date:     date,
+					URL:      fmt.Sprintf("https://www.speedrun.com/%s/runs/%s", data.Game.URL, wr.ID),
+				})
+				return nil
+			}})
+		}
+	}
+	err := NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs)
+
+	sort.Slice(records, func(i, j int) bool {
+		return order[records[i].Game+"\x00"+records[i].Category] < order[records[j].Game+"\x00"+records[j].Category]
+	})
+	return records, err
+}
+
+type recordsMsg struct {
+	records []wrChange
+	err     error
+}
+
+// recordsModel is the Records tab: the standing record of every followed
+// board, with the ones set lately marked
+type recordsModel struct {
+	client   *Client
+	games    *GameCache
+	followed []string
+	days     int
+	times    TimeFormat
+
+	records    []wrChange
+	selected   int
+	onlyRecent bool
+	loaded     bool
+	loading    bool
+	err        error
+}
+
+func newRecordsModel(client *Client, cfg Config) recordsModel {
+	r := recordsModel{client: client, followed: cfg.FollowedGames, days: cfg.RecordDays, times: cfg.TimeFormat}
+	if r.days == 0 {
+		r.days = defaultRecordDays
+	}
+	r.games, r.err = NewGameCache(client)
+	return r
+}
+
+func (r recordsModel) loadCmd() tea.Cmd {
+	client, cache, followed := r.client, r.games, r.followed
+	return func() tea.Msg {
+		records, err := currentWRs(client, cache, followed)
+		return recordsMsg{records: records, err: err}
+	}
+}
+
+func (r recordsModel) activate() (recordsModel, tea.Cmd) {
+	if r.loaded || r.loading || r.games == nil || len(r.followed) == 0 {
+		return r, nil
+	}
+	r.loading = true
+	return r, r.loadCmd()
+}
+
+// isNew reports whether a record was set within the window
+func (r recordsModel) isNew(wr wrChange) bool {
+	return time.Since(wr.Date) < time.Duration(r.days)*24*time.Hour
+}
+
+// shown is the records the list has, all or only the new ones
+func (r recordsModel) shown() []wrChange {
+	if !r.onlyRecent {
+		return r.records
+	}
+	var recent []wrChange
+	for _, wr := range r.records {
+		if r.isNew(wr) {
+			recent = append(recent, wr)
+		}
+	}
+	return recent
+}
+
+func (r recordsModel) selectedRecord() (wrChange, bool) {
+	shown := r.shown()
+	if r.selected >= len(shown) {
+		return wrChange{}, false
+	}
+	return shown[r.selected], true
+}
+
+func (r recordsModel) update(msg tea.Msg) (recordsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case recordsMsg:
+		r.loading = false
+		r.loaded = true
+		r.records, r.err = msg.records, msg.err
+		r.selected = min(r.selected, max(len(r.shown())-1, 0))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if r.selected > 0 {
+				r.selected--
+			}
+		case "down", "j":
+			if r.selected < len(r.shown())-1 {
+				r.selected++
+			}
+		case "n":
+			r.onlyRecent = !r.onlyRecent
+			r.selected = 0
+		case "r":
+			if !r.loading && r.games != nil && len(r.followed) > 0 {
+				r.loading = true
+				return r, r.loadCmd()
+			}
+		}
+	}
+	return r, nil
+}
+
+func (r recordsModel) view() string {
+	switch {
+	case len(r.followed) == 0:
+		return urlStyle.Render("Add followed_games to the config to see their records")
+	case r.loading:
+		return "Fetching records..."
+	}
+
+	var b strings.Builder
+	if r.err != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n\n", r.err))
+	}
+	if !r.loaded {
+		return b.String()
+	}
+
+	recent := 0
+	for _, wr := range r.records {
+		if r.isNew(wr) {
+			recent++
+		}
+	}
+	noun := "records"
+	if recent == 1 {
+		noun = "record"
+	}
+	b.WriteString(urlStyle.Render(fmt.Sprintf("%d boards, %d %s broken in the last %d days", len(r.records), recent, noun, r.days)))
+	b.WriteString("\n\n")
+
+	shown := r.shown()
+	if len(shown) == 0 {
+		b.WriteString("No records broken lately")
+		return b.String()
+	}
+	game := ""
+	for i, wr := range shown {
+		if wr.Game != game {
+			if game != "" {
+				b.WriteString("\n")
+			}
+			game = wr.Game
+			b.WriteString(titleStyle.Render(game) + "\n")
+		}
+		line := fmt.Sprintf("%s  %s by %s, %s", wr.Category, r.times.render(wr.Time, wr.Millis), wr.Players, wr.Date.Local().Format("2006-01-02"))
+		if r.isNew(wr) {
+			line += "  " + newRecordStyle.Render("new")
+		}
+		style := unselectedItemStyle
+		if i == r.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(line) + "\n")
+	}
+	return b.String()
+}
+
+func (r recordsModel) help() string {
+	if r.onlyRecent {
+		return "↑/↓ navigate • enter open run • n show all • r refresh"
+	}
+	return fmt.Sprintf("↑/↓ navigate • enter open run • n last %d days only • r refresh", r.days)
+}
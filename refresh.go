@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The inbox reloads every poll_interval while the app is open, and the
+// status bar says how old the list on screen is: yellow once it's older
+// than the interval, red when the last reload failed. While the terminal
+// doesn't have focus it reloads less often, and catches up on focus.
+
+const (
+	// How much slower reloads get while the terminal isn't focused
+	unfocusedSlowdown = 5
+
+	// Coming back to the app reloads the inbox if it's older than this, so
+	// flicking between windows doesn't reload every time
+	focusRefreshAfter = 30 * time.Second
+
+	// Longest wait between reloads while the site keeps failing them
+	maxRefreshBackoff = 15 * time.Minute
+)
+
+var (
+	staleStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700"))
+	refreshFailedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
+)
+
+type refreshTickMsg time.Time
+
+type inboxMsg struct {
+	notifications []Notification
+	unread        int
+	pagination    Pagination
+	failed        []string // accounts that didn't load
+	err           error
+}
+
+// refreshTickCmd redraws the age every second and reloads the inbox once
+// it's due
+func refreshTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return refreshTickMsg(t) })
+}
+
+// reloadInboxCmd fetches every account's notifications again
+func (m model) reloadInboxCmd() tea.Cmd {
+	clients := map[string]*Client{"": m.client}
+	if len(m.accounts) > 0 {
+		clients = m.accounts
+	}
+	primary, perPage, pages := m.client, m.perPage, m.pages
+	return func() tea.Msg {
+		var msg inboxMsg
+		for name, client := range clients {
+			result, err := client.GetNotificationPages(perPage, pages)
+			if err != nil {
+				// Without the primary account there's nothing to show
+				if client == primary {
+					return inboxMsg{err: err}
+				}
+				log.Printf("accounts: %s: %v", name, err)
+				msg.failed = append(msg.failed, name)
+				continue
+			}
+			for _, n := range result.Notifications {
+				n.Account = name
+				msg.notifications = append(msg.notifications, n)
+			}
+			msg.unread += result.UnreadCount
+			if client == primary {
+				msg.pagination = result.Pagination
+			}
+		}
+		sort.SliceStable(msg.notifications, func(i, j int) bool {
+			return msg.notifications[i].Date > msg.notifications[j].Date
+		})
+		return msg
+	}
+}
+
+// reloadedInbox swaps in a reloaded inbox. The rules' alerts only go out
+// for notifications that weren't there before.
+func (m model) reloadedInbox(msg inboxMsg) (model, tea.Cmd) {
+	m.refreshing = false
+	m.refreshErr = msg.err
+	m.refreshTried = time.Now()
+	if msg.err != nil {
+		// Back off while the site is down or limiting us, doubling the wait
+		// from the poll interval each time
+		m.backoff = min(max(2*m.backoff, m.pollEvery), maxRefreshBackoff)
+		log.Printf("refresh: %v, next try in %s", msg.err, max(m.pollEvery, m.backoff))
+		return m, nil
+	}
+	m.backoff = 0
+	m.refreshed = m.refreshTried
+	if len(msg.failed) > 0 {
+		m.status = fmt.Sprintf("Couldn't load notifications for %s", strings.Join(msg.failed, ", "))
+	}
+	if err := archiveNotifications(msg.notifications); err != nil {
+		log.Printf("history: %v", err)
+	}
+
+	notifications, highlighted, muted, work := applyRules(m.rules, msg.notifications)
+	var fresh []ruleDelivery
+	for _, d := range work.deliveries {
+		if _, ok := m.known[d.id]; !ok {
+			fresh = append(fresh, d)
+		}
+	}
+	work.deliveries = fresh
+	for _, n := range msg.notifications {
+		m.known[n.ID] = n.Account
+	}
+
+	m.notifications = notifications
+	m.highlighted = highlighted
+	m.muted = muted
+	m.unreadCount = max(msg.unread-len(work.markRead), 0)
+	m.pagination = msg.pagination
+	m.ruleWork = work
+	m.selected = min(m.selected, max(len(m.pins)+len(m.notificationRows(m.inbox.apply(m.notifications)))-1, 0))
+	return m, tea.Batch(m.ruleWorkCmds()...)
+}
+
+// refreshDue reports whether the inbox should be reloaded
+func (m model) refreshDue(now time.Time) bool {
+	if m.refreshing || m.pollEvery == 0 {
+		return false
+	}
+	every := m.pollEvery
+	if m.unfocused {
+		every *= unfocusedSlowdown
+	}
+	return now.Sub(m.refreshTried) >= max(every, m.backoff)
+}
+
+// catchUp reloads the inbox when the user comes back to the app, from
+// another window or from the shell after ctrl+z, if it's been a while
+func (m model) catchUp() (model, tea.Cmd) {
+	m.unfocused = false
+	if m.refreshing || m.client == nil || time.Since(m.refreshTried) < max(focusRefreshAfter, m.backoff) {
+		return m, nil
+	}
+	m.refreshing = true
+	return m, m.reloadInboxCmd()
+}
+
+// renderRefreshed is how old the data on screen is, empty on screens that
+// don't load any that goes stale
+func (m model) renderRefreshed() string {
+	var (
+		at  time.Time
+		err error
+	)
+	switch {
+	case m.screen == screenNotifications && m.detail == nil:
+		at, err = m.refreshed, m.refreshErr
+	case m.screen == screenQueue:
+		at, err = m.queue.fetched, m.queue.err
+	default:
+		return ""
+	}
+	if at.IsZero() {
+		return ""
+	}
+	age := formatSince(time.Since(at)) + " ago"
+	text := "refreshed " + age
+	switch {
+	case err != nil:
+		return refreshFailedStyle.Render("refresh failed, data from " + age)
+	case m.pollEvery > 0 && time.Since(at) > m.pollEvery:
+		return staleStyle.Render(text)
+	}
+	return text
+}
+
+// formatSince is formatAge down to the second
+func formatSince(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return formatAge(d)
+}
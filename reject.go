@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RejectionTemplate is a saved rejection reason. Text may use {runner},
+// {game}, {category} and {time}, filled in from the run being rejected.
+type RejectionTemplate struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+var defaultRejectionTemplates = []RejectionTemplate{
+	{Name: "No video", Text: "Hi {runner}, {category} runs need video proof. Please resubmit with a link to the full run."},
+	{Name: "Missing timer", Text: "Hi {runner}, {game} runs need a visible timer in the video. Please add one and resubmit."},
+	{Name: "Wrong category", Text: "Hi {runner}, this {time} run doesn't follow the {category} rules. Please resubmit it to the right category."},
+}
+
+func (t RejectionTemplate) expand(vars map[string]string) string {
+	var pairs []string
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(t.Text)
+}
+
+// rejectDialog picks a template for a run, then lets the moderator tweak
+// the filled in text before it's sent.
+type rejectDialog struct {
+	run       QueueRun
+	templates []RejectionTemplate
+	vars      map[string]string
+	selected  int
+	editing   bool
+	input     textinput.Model
+}
+
+func newRejectDialog(run QueueRun, templates []RejectionTemplate, game, category, runTime string) *rejectDialog {
+	if len(templates) == 0 {
+		templates = defaultRejectionTemplates
+	}
+	input := textinput.New()
+	input.CharLimit = 500
+	input.Width = 70
+	return &rejectDialog{
+		run:       run,
+		templates: templates,
+		vars: map[string]string{
+			"runner":   strings.Join(run.Players, " & "),
+			"game":     game,
+			"category": category,
+			"time":     runTime,
+		},
+		input: input,
+	}
+}
+
+type rejectResultMsg struct {
+	run    QueueRun
+	reason string
+	err    error
+	queued bool // failed, but waiting in the retry queue
+}
+
+// rejectConfirmedMsg is a rejection that's been confirmed, ready to send
+type rejectConfirmedMsg struct {
+	run    QueueRun
+	reason string
+}
+
+func rejectRunCmd(client *Client, run QueueRun, reason string) tea.Cmd {
+	return func() tea.Msg {
+		err := client.SetRunVerification(run.ID, runRejected, reason)
+		return rejectResultMsg{run: run, reason: reason, err: err}
+	}
+}
+
+// update returns a nil dialog once it's closed, with the command to send
+// the rejection if one was confirmed
+func (d *rejectDialog) update(msg tea.KeyMsg, client *Client) (*rejectDialog, tea.Cmd) {
+	if d.editing {
+		switch msg.String() {
+		case "enter":
+			reason := strings.TrimSpace(d.input.Value())
+			if reason == "" {
+				return d, nil
+			}
+			return nil, confirmCmd(rejectConfirmedMsg{run: d.run, reason: reason},
+				"Reject the run by %s?", strings.Join(d.run.Players, ", "))
+		case "esc":
+			d.editing = false
+			d.input.Blur()
+			return d, nil
+		}
+		var cmd tea.Cmd
+		d.input, cmd = d.input.Update(msg)
+		return d, cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if d.selected > 0 {
+			d.selected--
+		}
+	case "down", "j":
+		if d.selected < len(d.templates)-1 {
+			d.selected++
+		}
+	case "enter":
+		d.editing = true
+		d.input.SetValue(d.templates[d.selected].expand(d.vars))
+		d.input.CursorEnd()
+		return d, d.input.Focus()
+	case "esc", "q":
+		return nil, nil
+	}
+	return d, nil
+}
+
+func (d *rejectDialog) view() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Reject %s by %s\n\n", d.vars["time"], d.vars["runner"]))
+	if d.editing {
+		b.WriteString(d.input.View())
+		return alertBannerStyle.Render(b.String())
+	}
+	for i, t := range d.templates {
+		cursor := "  "
+		if i == d.selected {
+			cursor = "> "
+		}
+		b.WriteString(cursor + t.Name + "\n")
+	}
+	b.WriteString("\n" + urlStyle.Render(truncate(d.templates[d.selected].expand(d.vars), 70)))
+	return alertBannerStyle.Render(b.String())
+}
+
+func (d *rejectDialog) help() string {
+	if d.editing {
+		return "enter reject • esc back to templates"
+	}
+	return "j/k choose reason • enter edit and send • esc cancel"
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reportTarget is something that can be reported to the site's staff
+type reportTarget struct {
+	Kind  string // "run", "comment" or "user"
+	ID    string
+	Label string // what the dialog calls it, e.g. "run by Alice"
+}
+
+// The reasons the site's report form offers
+var reportReasons = []string{
+	"Spam",
+	"Breaks the game's rules",
+	"Cheated run",
+	"Harassment or abuse",
+	"Inappropriate content",
+}
+
+// Report files a report with the site
+func (c *Client) Report(t reportTarget, reason string) error {
+	body := struct {
+		ItemType string `json:"itemType"`
+		ItemID   string `json:"itemId"`
+		Text     string `json:"text"`
+	}{
+		ItemType: t.Kind,
+		ItemID:   t.ID,
+		Text:     reason,
+	}
+	if err := c.write("PutReport", body, nil); err != nil {
+		return fmt.Errorf("reporting %s: %w", t.Kind, err)
+	}
+	return nil
+}
+
+type reportResultMsg struct {
+	target reportTarget
+	err    error
+}
+
+func reportCmd(client *Client, t reportTarget, reason string) tea.Cmd {
+	return func() tea.Msg {
+		return reportResultMsg{target: t, err: client.Report(t, reason)}
+	}
+}
+
+// reportDialog picks what to report when there's a choice, then a
+// reason, then lets the reason be written out before sending
+type reportDialog struct {
+	targets  []reportTarget
+	target   int
+	picked   bool // target chosen
+	selected int  // reason
+	editing  bool
+	input    textinput.Model
+}
+
+func newReportDialog(targets []reportTarget) *reportDialog {
+	input := textinput.New()
+	input.CharLimit = 500
+	input.Width = 70
+	return &reportDialog{targets: targets, picked: len(targets) == 1, input: input}
+}
+
+// update returns nil once the dialog is closed, with the command sending
+// the report if it was confirmed
+func (d *reportDialog) update(msg tea.KeyMsg, client *Client) (*reportDialog, tea.Cmd) {
+	if d.editing {
+		switch msg.String() {
+		case "enter":
+			reason := strings.TrimSpace(d.input.Value())
+			if reason == "" {
+				return d, nil
+			}
+			return nil, reportCmd(client, d.targets[d.target], reason)
+		case "esc":
+			d.editing = false
+			d.input.Blur()
+			return d, nil
+		}
+		var cmd tea.Cmd
+		d.input, cmd = d.input.Update(msg)
+		return d, cmd
+	}
+
+	cursor, count := &d.selected, len(reportReasons)
+	if !d.picked {
+		cursor, count = &d.target, len(d.targets)
+	}
+	switch msg.String() {
+	case "up", "k":
+		if *cursor > 0 {
+			*cursor--
+		}
+	case "down", "j":
+		if *cursor < count-1 {
+			*cursor++
+		}
+	case "enter":
+		if !d.picked {
+			d.picked = true
+			return d, nil
+		}
+		d.editing = true
+		d.input.SetValue(reportReasons[d.selected] + ": ")
+		d.input.CursorEnd()
+		return d, d.input.Focus()
+	case "esc", "q":
+		if d.picked && len(d.targets) > 1 {
+			d.picked = false
+			return d, nil
+		}
+		return nil, nil
+	}
+	return d, nil
+}
+
+func (d *reportDialog) view() string {
+	var b strings.Builder
+	if !d.picked {
+		b.WriteString("Report which?\n\n")
+		for i, t := range d.targets {
+			cursor := "  "
+			if i == d.target {
+				cursor = "> "
+			}
+			b.WriteString(cursor + t.Label + "\n")
+		}
+		return alertBannerStyle.Render(strings.TrimSuffix(b.String(), "\n"))
+	}
+
+	b.WriteString(fmt.Sprintf("Report %s\n\n", d.targets[d.target].Label))
+	if d.editing {
+		b.WriteString(d.input.View())
+		return alertBannerStyle.Render(b.String())
+	}
+	for i, r := range reportReasons {
+		cursor := "  "
+		if i == d.selected {
+			cursor = "> "
+		}
+		b.WriteString(cursor + r + "\n")
+	}
+	return alertBannerStyle.Render(strings.TrimSuffix(b.String(), "\n"))
+}
+
+func (d *reportDialog) help() string {
+	switch {
+	case d.editing:
+		return "enter send report • esc back"
+	case !d.picked:
+		return "j/k choose • enter next • esc cancel"
+	}
+	return "j/k choose reason • enter edit • esc cancel"
+}
+
+// runReportTarget describes a run for the report dialog
+func runReportTarget(id, players string) reportTarget {
+	return reportTarget{Kind: "run", ID: id, Label: "run by " + players}
+}
+
+func userReportTarget(id, name string) reportTarget {
+	return reportTarget{Kind: "user", ID: id, Label: name}
+}
+
+// commentReportTarget labels a comment with its author and opening words,
+// since a thread offers one target per comment
+func commentReportTarget(id, author, text string) reportTarget {
+	words := strings.Join(strings.Fields(text), " ")
+	if runes := []rune(words); len(runes) > 40 {
+		words = string(runes[:39]) + "…"
+	}
+	return reportTarget{Kind: "comment", ID: id, Label: fmt.Sprintf("comment by %s: %q", author, words)}
+}
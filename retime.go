@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Retiming works the way the community's retime tools do: each end of the
+// run is snapped to the frame it falls in, and the time is the frames in
+// between over the frame rate, rounded to the millisecond.
+
+const defaultRetimeFPS = "60"
+
+// retimeFrame reads a frame number, or a timestamp like 1:23.456 (or 83.0)
+// from the video, snapped down to its frame. A bare number is a frame.
+func retimeFrame(s string, fps float64) (int64, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid frame %q", s)
+		}
+		return n, nil
+	}
+	d, err := parseRunTime(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid frame or timestamp %q", s)
+	}
+	return frameAt(d, fps), nil
+}
+
+// frameAt is the frame a moment in the video falls in
+func frameAt(d time.Duration, fps float64) int64 {
+	// The epsilon keeps 1.1 at 10 fps on frame 11, not 10.999...
+	return int64(math.Floor(d.Seconds()*fps + 1e-6))
+}
+
+func parseFPS(s string) (float64, error) {
+	fps, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || fps <= 0 || math.IsInf(fps, 0) {
+		return 0, fmt.Errorf("invalid frame rate %q", s)
+	}
+	return fps, nil
+}
+
+// retime is the run's time from its first and last frame
+func retime(fps float64, start, end int64) (time.Duration, error) {
+	if end < start {
+		return 0, errors.New("the end is before the start")
+	}
+	seconds := float64(end-start) / fps
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond), nil
+}
+
+// retimeInputs works the time out from the frame rate, start and end as
+// typed
+func retimeInputs(fpsText, startText, endText string) (time.Duration, error) {
+	fps, err := parseFPS(fpsText)
+	if err != nil {
+		return 0, err
+	}
+	start, err := retimeFrame(startText, fps)
+	if err != nil {
+		return 0, err
+	}
+	end, err := retimeFrame(endText, fps)
+	if err != nil {
+		return 0, err
+	}
+	return retime(fps, start, end)
+}
+
+// runRetime is the retime command, for working a time out without the TUI
+func runRetime(args []string) error {
+	fs := flag.NewFlagSet("retime", flag.ExitOnError)
+	fps := fs.String("fps", defaultRetimeFPS, "the video's frame rate")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		return errors.New("usage: speedrunner retime [-fps 60] <start> <end>, each a frame number or a timestamp like 1:23.456")
+	}
+	d, err := retimeInputs(*fps, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatRunTime(d))
+	return nil
+}
+
+// Fields of the retime dialog, in tab order
+const (
+	retimeFPS = iota
+	retimeStart
+	retimeEnd
+	retimeFields
+)
+
+var retimeLabels = [retimeFields]string{"Frame rate", "Start", "End"}
+
+// retimeDialog works out a queue run's time from its video, next to the
+// time it was submitted with
+type retimeDialog struct {
+	run     QueueRun
+	inputs  [retimeFields]textinput.Model
+	focused int
+}
+
+func newRetimeDialog(run QueueRun) (*retimeDialog, tea.Cmd) {
+	d := &retimeDialog{run: run}
+	for i := range d.inputs {
+		input := textinput.New()
+		input.CharLimit = 20
+		input.Width = 16
+		d.inputs[i] = input
+	}
+	d.inputs[retimeFPS].SetValue(defaultRetimeFPS)
+	d.inputs[retimeStart].Placeholder = "frame or 0:12.345"
+	d.inputs[retimeEnd].Placeholder = "frame or 1:23:45.678"
+	d.focused = retimeStart
+	return d, d.inputs[d.focused].Focus()
+}
+
+type retimedMsg struct {
+	run   QueueRun
+	time  time.Duration
+	marks []Bookmark // the run's notes, when the time was added to them
+	err   error
+}
+
+// result is the time so far, once every field reads right
+func (d *retimeDialog) result() (time.Duration, error) {
+	return retimeInputs(d.inputs[retimeFPS].Value(), d.inputs[retimeStart].Value(), d.inputs[retimeEnd].Value())
+}
+
+// update returns a nil dialog once it's closed; enter copies the time
+func (d *retimeDialog) update(msg tea.Msg) (*retimeDialog, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "tab", "down":
+			return d, d.focus((d.focused + 1) % retimeFields)
+		case "shift+tab", "up":
+			return d, d.focus((d.focused + retimeFields - 1) % retimeFields)
+		case "enter":
+			t, err := d.result()
+			if err != nil {
+				return d, statusCmd("%v", err)
+			}
+			run := d.run
+			return nil, func() tea.Msg {
+				return retimedMsg{run: run, time: t, err: copyToClipboard(formatRunTime(t))}
+			}
+		case "esc":
+			return nil, nil
+		}
+	}
+	var cmd tea.Cmd
+	d.inputs[d.focused], cmd = d.inputs[d.focused].Update(msg)
+	return d, cmd
+}
+
+func (d *retimeDialog) focus(i int) tea.Cmd {
+	d.inputs[d.focused].Blur()
+	d.focused = i
+	return d.inputs[i].Focus()
+}
+
+func (d *retimeDialog) view() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Retime the run by %s, submitted as %s\n\n",
+		strings.Join(d.run.Players, ", "), formatRunTime(d.run.Time)))
+	for i, input := range d.inputs {
+		b.WriteString(fmt.Sprintf("%-11s %s\n", retimeLabels[i], input.View()))
+	}
+	b.WriteString("\n")
+	t, err := d.result()
+	switch {
+	case d.inputs[retimeStart].Value() == "" || d.inputs[retimeEnd].Value() == "":
+		b.WriteString(urlStyle.Render("Type the first and last frame, or their timestamps"))
+	case err != nil:
+		b.WriteString(urlStyle.Render(err.Error()))
+	default:
+		b.WriteString(fmt.Sprintf("Retimed %s  %s", formatRunTime(t), renderDelta(t-d.run.Time)))
+	}
+	return alertBannerStyle.Render(b.String())
+}
+
+func (d *retimeDialog) help() string {
+	return "tab next field • enter copy the time • esc close"
+}
+
+// retimeMark is the start of a run marked in mpv, waiting for its end
+type retimeMark struct {
+	runID string
+	frame int64
+	fps   float64
+}
+
+type mpvMarkMsg struct {
+	run QueueRun
+	end bool
+	at  time.Duration
+	fps float64
+	err error
+}
+
+// mpvMarkCmd reads where mpv is, and the video's frame rate, to mark the
+// start or end of a run
+func mpvMarkCmd(run QueueRun, end bool) tea.Cmd {
+	return func() tea.Msg {
+		at, err := mpvPosition()
+		if err != nil {
+			return mpvMarkMsg{err: err}
+		}
+		fps, err := mpvFPS()
+		return mpvMarkMsg{run: run, end: end, at: at, fps: fps, err: err}
+	}
+}
+
+// retimeNoteCmd adds a retime to the run's video notes and copies the time
+func retimeNoteCmd(run QueueRun, start retimeMark, end int64, at, t time.Duration) tea.Cmd {
+	note := fmt.Sprintf("retimed to %s (frames %d to %d at %s fps)", formatRunTime(t), start.frame, end,
+		strconv.FormatFloat(start.fps, 'f', -1, 64))
+	return func() tea.Msg {
+		marks, err := addBookmark(run.ID, Bookmark{At: at, Note: note, Added: time.Now()})
+		if err != nil {
+			return retimedMsg{run: run, time: t, err: err}
+		}
+		return retimedMsg{run: run, time: t, marks: marks, err: copyToClipboard(formatRunTime(t))}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	retryFile = "retry_queue.json"
+
+	retryFirstDelay = 15 * time.Second
+	retryMaxDelay   = 10 * time.Minute
+)
+
+// Kinds of write action that are retried
+const (
+	retryMarkRead = "mark_read"
+	retryVerify   = "verify"
+	retryReject   = "reject"
+	retryComment  = "comment"
+)
+
+// writeAction is a write that failed for a reason that may pass, kept until
+// it goes through. It's saved to disk so quitting doesn't lose it.
+type writeAction struct {
+	ID              string    `json:"id"`
+	Kind            string    `json:"kind"`
+	Account         string    `json:"account,omitempty"` // whose session sends it
+	Label           string    `json:"label"`             // for the status line
+	NotificationIDs []string  `json:"notification_ids,omitempty"`
+	RunID           string    `json:"run_id,omitempty"`
+	GameID          string    `json:"game_id,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	Text            string    `json:"text,omitempty"` // a comment's
+	Attempts        int       `json:"attempts"`
+	NextTry         time.Time `json:"next_try"`
+	LastError       string    `json:"last_error"`
+}
+
+func markReadAction(account string, ids []string) writeAction {
+	return writeAction{
+		Kind:            retryMarkRead,
+		Account:         account,
+		Label:           fmt.Sprintf("mark %d notifications read", len(ids)),
+		NotificationIDs: ids,
+	}
+}
+
+func verifyAction(run QueueRun) writeAction {
+	return writeAction{Kind: retryVerify, Label: "verify run by " + strings.Join(run.Players, ", "), RunID: run.ID, GameID: run.GameID}
+}
+
+func rejectAction(run QueueRun, reason string) writeAction {
+	return writeAction{Kind: retryReject, Label: "reject run by " + strings.Join(run.Players, ", "), RunID: run.ID, GameID: run.GameID, Reason: reason}
+}
+
+func commentAction(runID, text string) writeAction {
+	return writeAction{Kind: retryComment, Label: fmt.Sprintf("post comment %q", truncate(text, 30)), RunID: runID, Text: text}
+}
+
+func (a writeAction) do(client *Client) error {
+	switch a.Kind {
+	case retryMarkRead:
+		return client.MarkNotificationsRead(a.NotificationIDs)
+	case retryVerify:
+		return client.SetRunVerification(a.RunID, runVerified, "")
+	case retryReject:
+		return client.SetRunVerification(a.RunID, runRejected, a.Reason)
+	case retryComment:
+		return client.PostRunComment(a.RunID, a.Text)
+	}
+	return fmt.Errorf("unknown action %q", a.Kind)
+}
+
+// retryable reports whether a failed write could go through later: the
+// request never got an answer, or the server had trouble with it
+func retryable(err error) bool {
+	if err == nil || errors.Is(err, errNoSession) || errors.Is(err, errSessionExpired) {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// retryDelay doubles from retryFirstDelay with each failed attempt
+func retryDelay(attempts int) time.Duration {
+	d := retryFirstDelay
+	for i := 1; i < attempts && d < retryMaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, retryMaxDelay)
+}
+
+// retryQueue holds the write actions waiting for another try
+type retryQueue struct {
+	actions  []writeAction
+	inFlight map[string]bool
+}
+
+func loadRetryQueue() retryQueue {
+	var actions []writeAction
+	if err := loadState(retryFile, &actions); err != nil {
+		log.Printf("retry queue: %v", err)
+	}
+	return retryQueue{actions: actions, inFlight: map[string]bool{}}
+}
+
+func (q *retryQueue) save() {
+	if err := saveState(retryFile, q.actions); err != nil {
+		log.Printf("retry queue: %v", err)
+	}
+}
+
+// add queues an action that just failed with err
+func (q *retryQueue) add(a writeAction, err error) tea.Cmd {
+	a.ID = fmt.Sprintf("%s-%d", a.Kind, time.Now().UnixNano())
+	a.Attempts = 1
+	a.LastError = err.Error()
+	a.NextTry = time.Now().Add(retryDelay(a.Attempts))
+	q.actions = append(q.actions, a)
+	q.save()
+	return retryTickCmd(retryDelay(a.Attempts))
+}
+
+func (q *retryQueue) remove(id string) {
+	for i, a := range q.actions {
+		if a.ID == id {
+			q.actions = append(q.actions[:i:i], q.actions[i+1:]...)
+			break
+		}
+	}
+	delete(q.inFlight, id)
+	q.save()
+}
+
+type retryTickMsg struct{}
+
+type retryResultMsg struct {
+	action writeAction
+	err    error
+}
+
+func retryTickCmd(after time.Duration) tea.Cmd {
+	return tea.Tick(after, func(time.Time) tea.Msg { return retryTickMsg{} })
+}
+
+// due starts every action whose time has come
+func (q *retryQueue) due(clientFor func(account string) *Client, now time.Time) tea.Cmd {
+	if q.inFlight == nil {
+		q.inFlight = map[string]bool{}
+	}
+	var cmds []tea.Cmd
+	for _, a := range q.actions {
+		if q.inFlight[a.ID] || a.NextTry.After(now) {
+			continue
+		}
+		q.inFlight[a.ID] = true
+		client := clientFor(a.Account)
+		cmds = append(cmds, func() tea.Msg {
+			return retryResultMsg{action: a, err: a.do(client)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// failed schedules the next try of an action that failed again
+func (q *retryQueue) failed(a writeAction, err error) tea.Cmd {
+	delete(q.inFlight, a.ID)
+	for i := range q.actions {
+		if q.actions[i].ID == a.ID {
+			q.actions[i].Attempts++
+			q.actions[i].LastError = err.Error()
+			q.actions[i].NextTry = time.Now().Add(retryDelay(q.actions[i].Attempts))
+			q.save()
+			return retryTickCmd(retryDelay(q.actions[i].Attempts))
+		}
+	}
+	return nil
+}
+
+// status is the status bar note while actions are waiting
+func (q retryQueue) status() string {
+	switch n := len(q.actions); n {
+	case 0:
+		return ""
+	case 1:
+		return "⟳ 1 action waiting to retry"
+	default:
+		return fmt.Sprintf("⟳ %d actions waiting to retry", n)
+	}
+}
+
+// handleRetry deals with the retry queue's own messages, reporting whether
+// msg was one of them
+func (m model) handleRetry(msg tea.Msg) (model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case retryTickMsg:
+		return m, m.retries.due(m.clientFor, time.Now()), true
+
+	case retryResultMsg:
+		a := msg.action
+		switch {
+		case msg.err == nil:
+			m.retries.remove(a.ID)
+			m.status = "Retried: " + a.Label
+			if a.Kind == retryVerify || a.Kind == retryReject {
+				m.queue.confirmed(QueueRun{ID: a.RunID, GameID: a.GameID})
+				m.viewport.SetContent(m.renderContent())
+			}
+			// Show the comment if its run is open
+			if t := m.openComments(); a.Kind == retryComment && t != nil && t.runID == a.RunID {
+				t.loading = true
+				return m, t.loadCmd(m.client, t.lastPage()), true
+			}
+			return m, nil, true
+		case retryable(msg.err):
+			return m, m.retries.failed(a, msg.err), true
+		}
+		// Undo what the screens already showed
+		log.Printf("retry queue: %s: %v", a.Label, msg.err)
+		m.retries.remove(a.ID)
+		m.status = fmt.Sprintf("Gave up trying to %s: %v", a.Label, msg.err)
+		switch a.Kind {
+		case retryMarkRead:
+			m = m.markUnread(a.NotificationIDs)
+		case retryVerify, retryReject:
+			m.queue.restore(a.RunID)
+		}
+		m.viewport.SetContent(m.renderContent())
+		return m, nil, true
+	}
+	return m, nil, false
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Route sends alerts about some games to some sinks, e.g. SM64 to its own
+// Discord channel. A route without games catches the alerts no other
+// route took.
+type Route struct {
+	Games []string `json:"games,omitempty"` // abbreviations, e.g. "sm64"
+	Sinks []string `json:"sinks"`           // sink names
+}
+
+// alertRouter picks the sinks for an alert by its game
+type alertRouter struct {
+	games    map[string][]Sink // abbreviation -> sinks
+	fallback []Sink
+}
+
+// buildRouter resolves the routes' sink names. With no routes at all every
+// sink gets every alert, like before there were routes.
+func buildRouter(routes []Route, configs []SinkConfig) (*alertRouter, error) {
+	if len(routes) == 0 {
+		sinks, err := buildSinks(configs)
+		if err != nil {
+			return nil, err
+		}
+		return &alertRouter{fallback: sinks}, nil
+	}
+
+	named := make(map[string]Sink)
+	for _, sc := range configs {
+		if sc.Name == "" {
+			continue
+		}
+		sink, err := buildSink(sc)
+		if err != nil {
+			return nil, err
+		}
+		named[sc.Name] = sink
+	}
+
+	r := &alertRouter{games: make(map[string][]Sink)}
+	caughtAll := false
+	for i, route := range routes {
+		if len(route.Sinks) == 0 {
+			return nil, fmt.Errorf("route %d has no sinks", i+1)
+		}
+		var sinks []Sink
+		for _, name := range route.Sinks {
+			sink, ok := named[name]
+			if !ok {
+				return nil, fmt.Errorf("route %d sends to unknown sink %q", i+1, name)
+			}
+			sinks = append(sinks, sink)
+		}
+		if len(route.Games) == 0 {
+			if caughtAll {
+				return nil, fmt.Errorf("route %d: only one route can leave out games", i+1)
+			}
+			caughtAll = true
+			r.fallback = sinks
+			continue
+		}
+		for _, game := range route.Games {
+			game = strings.ToLower(game)
+			// The first route naming a game wins
+			if _, ok := r.games[game]; !ok {
+				r.games[game] = sinks
+			}
+		}
+	}
+	return r, nil
+}
+
+// sinksFor is where an alert about game goes
+func (r *alertRouter) sinksFor(game string) []Sink {
+	if r == nil {
+		return nil
+	}
+	if sinks, ok := r.games[strings.ToLower(game)]; ok {
+		return sinks
+	}
+	return r.fallback
+}
+
+// send delivers an alert to the sinks its game is routed to
+func (r *alertRouter) send(alert Alert) {
+	sendAlert(r.sinksFor(alert.Game), alert)
+}
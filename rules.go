@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rule matches notifications and says what to do with them. Every match
+// field that's set must match; a rule with none matches everything.
+type Rule struct {
+	Type    string   `json:"type,omitempty"`    // e.g. "run_verified"
+	Game    string   `json:"game,omitempty"`    // abbreviation, e.g. "sm64"
+	Keyword string   `json:"keyword,omitempty"` // case insensitive, in the title
+	Actions []string `json:"actions"`
+	Sink    string   `json:"sink,omitempty"` // sink name for "forward"
+}
+
+const (
+	actionMute      = "mute"      // hide from the list
+	actionMarkRead  = "mark_read" // mark read on the site
+	actionHighlight = "highlight" // stand out in the list
+	actionAlert     = "alert"     // desktop notification
+	actionForward   = "forward"   // send to the named sink
+)
+
+// Notification IDs already alerted on, so a restart doesn't repeat them
+const rulesSeenFile = "rules_seen.json"
+
+var highlightedItemStyle = lipgloss.NewStyle().
+	Border(lipgloss.NormalBorder()).
+	BorderLeft(true).
+	BorderLeftForeground(lipgloss.Color("#FF9F43")).
+	Bold(true).
+	Padding(0, 1)
+
+// notificationGame guesses the game from a notification's link, whose
+// first path segment is the game abbreviation for run and forum links.
+func notificationGame(n Notification) string {
+	game, _, _ := strings.Cut(strings.TrimPrefix(n.Path, "/"), "/")
+	return strings.ToLower(game)
+}
+
+func (r Rule) matches(n Notification) bool {
+	return (r.Type == "" || r.Type == n.Type) &&
+		(r.Game == "" || strings.EqualFold(r.Game, notificationGame(n))) &&
+		(r.Keyword == "" || strings.Contains(strings.ToLower(n.Title), strings.ToLower(r.Keyword)))
+}
+
+// ruleEffect is what all the rules matching one notification add up to
+type ruleEffect struct {
+	Mute      bool
+	MarkRead  bool
+	Highlight bool
+	Sinks     []Sink
+	forwards  []string // sink names from scripts, resolved once they're done
+}
+
+// ruleSet is the config's rules with their sink names resolved, and the
+// scripts that run alongside them
+type ruleSet struct {
+	rules   []Rule
+	sinks   map[string]Sink
+	scripts *scriptSet
+}
+
+func compileRules(rules []Rule, sinkConfigs []SinkConfig) (*ruleSet, error) {
+	rs := &ruleSet{rules: rules, sinks: make(map[string]Sink)}
+	for _, sc := range sinkConfigs {
+		if sc.Name == "" {
+			continue
+		}
+		sink, err := buildSink(sc)
+		if err != nil {
+			return nil, err
+		}
+		rs.sinks[sc.Name] = sink
+	}
+
+	for i, r := range rules {
+		if len(r.Actions) == 0 {
+			return nil, fmt.Errorf("rule %d has no actions", i+1)
+		}
+		for _, a := range r.Actions {
+			switch a {
+			case actionMute, actionMarkRead, actionHighlight, actionAlert:
+			case actionForward:
+				if _, ok := rs.sinks[r.Sink]; !ok {
+					return nil, fmt.Errorf("rule %d forwards to unknown sink %q", i+1, r.Sink)
+				}
+			default:
+				return nil, fmt.Errorf("rule %d: unknown action %q", i+1, a)
+			}
+		}
+	}
+
+	var err error
+	if rs.scripts, err = loadScripts(rs.sinks); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (rs *ruleSet) apply(n Notification) ruleEffect {
+	var e ruleEffect
+	if rs == nil {
+		return e
+	}
+	for _, r := range rs.rules {
+		if !r.matches(n) {
+			continue
+		}
+		for _, a := range r.Actions {
+			switch a {
+			case actionMute:
+				e.Mute = true
+			case actionMarkRead:
+				e.MarkRead = true
+			case actionHighlight:
+				e.Highlight = true
+			case actionAlert:
+				e.Sinks = append(e.Sinks, desktopSink{})
+			case actionForward:
+				e.Sinks = append(e.Sinks, rs.sinks[r.Sink])
+			}
+		}
+	}
+	rs.scripts.apply(n, &e)
+	return e
+}
+
+// ruleWork is the side effects of applying rules to a page of
+// notifications, carried out in the background
+type ruleWork struct {
+	markRead   []string
+	deliveries []ruleDelivery
+}
+
+type ruleDelivery struct {
+	id    string
+	alert Alert
+	sinks []Sink
+}
+
+// applyRules filters a page of notifications for display and collects
+// the marking and alerting the rules ask for.
+func applyRules(rs *ruleSet, notifications []Notification) (kept []Notification, highlighted map[string]bool, muted int, work ruleWork) {
+	highlighted = make(map[string]bool)
+	for _, n := range notifications {
+		e := rs.apply(n)
+		if len(e.Sinks) > 0 && !n.Read {
+			work.deliveries = append(work.deliveries, ruleDelivery{
+				id:    n.ID,
+				alert: Alert{Title: "speedrun.com", Body: n.Title, URL: "https://www.speedrun.com" + n.Path, Game: notificationGame(n)},
+				sinks: e.Sinks,
+			})
+		}
+		if e.MarkRead && !n.Read {
+			work.markRead = append(work.markRead, n.ID)
+			n.Read = true
+		}
+		if e.Mute {
+			muted++
+			continue
+		}
+		if e.Highlight {
+			highlighted[n.ID] = true
+		}
+		kept = append(kept, n)
+	}
+	return kept, highlighted, muted, work
+}
+
+// MarkNotificationsRead marks notifications read on the site
+func (c *Client) MarkNotificationsRead(ids []string) error {
+	body := struct {
+		NotificationIDs []string `json:"notificationIds"`
+	}{
+		NotificationIDs: ids,
+	}
+	if err := c.write("PutNotificationsRead", body, nil); err != nil {
+		return fmt.Errorf("marking notifications read: %w", err)
+	}
+	return nil
+}
+
+type markReadMsg struct {
+	ids []string
+	err error
+}
+
+// markAllReadMsg is a confirmed mark all read
+type markAllReadMsg struct {
+	ids []string
+}
+
+// markRead shows notifications read straight away and tells the site
+func (m model) markRead(ids []string) (model, tea.Cmd) {
+	m.setRead(ids, true)
+	desc := "marked a notification read"
+	if len(ids) > 1 {
+		desc = fmt.Sprintf("marked %d notifications read", len(ids))
+	}
+	m = m.pushUndo(desc, func(m model) (model, tea.Cmd) {
+		return m.undoMarkRead(ids)
+	})
+	var cmds []tea.Cmd
+	for account, ids := range m.byAccount(ids) {
+		client := m.clientFor(account)
+		cmds = append(cmds, func() tea.Msg {
+			return markReadMsg{ids: ids, err: client.MarkNotificationsRead(ids)}
+		})
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// markUnread undoes markRead after the site refused it
+func (m model) markUnread(ids []string) model {
+	m.setRead(ids, false)
+	return m
+}
+
+func (m *model) setRead(ids []string, read bool) {
+	for _, id := range ids {
+		for i, n := range m.notifications {
+			if n.ID != id || n.Read == read {
+				continue
+			}
+			m.notifications[i].Read = read
+			if read {
+				m.unreadCount = max(m.unreadCount-1, 0)
+			} else {
+				m.unreadCount++
+			}
+		}
+	}
+}
+
+// ruleWorkCmd marks notifications read and delivers alerts, each alert
+// once: IDs already delivered are remembered in rulesSeenFile.
+func ruleWorkCmd(client *Client, work ruleWork) tea.Cmd {
+	if len(work.markRead) == 0 && len(work.deliveries) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		// The list already shows them read; a failure puts that right
+		var msg tea.Msg
+		if len(work.markRead) > 0 {
+			if err := client.MarkNotificationsRead(work.markRead); err != nil {
+				log.Printf("rules: %v", err)
+				msg = markReadMsg{ids: work.markRead, err: err}
+			}
+		}
+
+		if len(work.deliveries) == 0 {
+			return msg
+		}
+		var seen []string
+		if err := loadState(rulesSeenFile, &seen); err != nil {
+			log.Printf("rules: %v", err)
+		}
+		known := make(map[string]bool, len(seen))
+		for _, id := range seen {
+			known[id] = true
+		}
+		for _, d := range work.deliveries {
+			if known[d.id] {
+				continue
+			}
+			sendAlert(d.sinks, d.alert)
+			seen = append(seen, d.id)
+		}
+		// Notification pages are short, so a few hundred IDs covers them
+		if len(seen) > 500 {
+			seen = seen[len(seen)-500:]
+		}
+		if err := saveState(rulesSeenFile, seen); err != nil {
+			log.Printf("rules: %v", err)
+		}
+		return msg
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// runnerRole is how the site singles a user out by name color
+type runnerRole int
+
+const (
+	roleUser runnerRole = iota
+	roleModerator
+	roleAdmin
+	roleBanned
+)
+
+var roleStyles = map[runnerRole]lipgloss.Style{
+	roleModerator: lipgloss.NewStyle().Foreground(lipgloss.Color("#4CAF50")),
+	roleAdmin:     lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true),
+	roleBanned:    lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Strikethrough(true),
+}
+
+var pronounsStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+// Runner is what lists show about a player besides their name
+type Runner struct {
+	Name     string
+	Country  string // ISO 3166 alpha-2, e.g. "SE"
+	Pronouns string
+	Role     runnerRole
+
+	Colors    nameColors // the name's colors on the site
+	Supporter bool
+}
+
+// v1Role maps the role field of a v1 user
+func v1Role(role string) runnerRole {
+	switch role {
+	case "banned":
+		return roleBanned
+	case "admin", "programmer":
+		return roleAdmin
+	case "moderator":
+		return roleModerator
+	}
+	return roleUser
+}
+
+// pronouns decodes the v2 pronouns field, which is either a string or a
+// list of them
+type pronouns string
+
+func (p *pronouns) UnmarshalJSON(raw []byte) error {
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		*p = pronouns(strings.Join(list, ", "))
+		return nil
+	}
+	var s *string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return err
+	}
+	if s != nil {
+		*p = pronouns(*s)
+	}
+	return nil
+}
+
+// countryFlag renders a country code per the country_flags setting:
+// "emoji" (the default) as a flag, "code" as the letters, "off" not at all
+func countryFlag(code, mode string) string {
+	code = strings.ToUpper(code)
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return ""
+	}
+	switch mode {
+	case "off":
+		return ""
+	case "code":
+		return "[" + code + "]"
+	}
+	// Each letter has a regional indicator symbol; a pair of them is a flag
+	return string([]rune{rune(code[0]) - 'A' + 0x1F1E6, rune(code[1]) - 'A' + 0x1F1E6})
+}
+
+func validateFlagMode(mode string) error {
+	switch mode {
+	case "", "emoji", "code", "off":
+		return nil
+	}
+	return fmt.Errorf("unknown country_flags %q, use emoji, code or off", mode)
+}
+
+// render shows the runner the way the site does: flag, name in their
+// role's color, then pronouns
+func (r Runner) render(flags string) string {
+	var parts []string
+	if flag := countryFlag(r.Country, flags); flag != "" {
+		parts = append(parts, flag)
+	}
+	// The app's role colors say more than the site's name colors
+	name := r.Name
+	if style, ok := roleStyles[r.Role]; ok {
+		name = style.Render(name)
+	} else {
+		name = r.Colors.render(name)
+	}
+	if r.Supporter {
+		name += supporterStyle.Render(supporterMark)
+	}
+	parts = append(parts, name)
+	if r.Pronouns != "" {
+		parts = append(parts, pronounsStyle.Render("("+r.Pronouns+")"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderRunners joins runners into a cell of exactly width columns,
+// dropping the ones that don't fit and marking the cut with an ellipsis.
+// A width of 0 means no limit.
+func renderRunners(runners []Runner, flags string, width int) string {
+	var out string
+	for i, r := range runners {
+		next := r.render(flags)
+		if i > 0 {
+			next = ", " + next
+		}
+		if width > 0 && lipgloss.Width(out+next) > width {
+			if out == "" {
+				// Not even the first one fits; fall back to its plain name
+				out = truncate(r.Name, width)
+			} else if lipgloss.Width(out)+2 <= width {
+				out += " …"
+			}
+			break
+		}
+		out += next
+	}
+	if pad := width - lipgloss.Width(out); pad > 0 {
+		out += strings.Repeat(" ", pad)
+	}
+	return out
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// Starlark scripts in the scripts dir next to the config. Each can define
+// any of:
+//
+//	filter(n)          False hides the notification, like a mute rule
+//	on_notification(n) calls mark_read(), highlight(), mute(), alert() or
+//	                   forward("sink") for the notification
+//	sort_queue(run)    a key the verification queue is sorted by
+//
+// They run wherever the rules do, in the app and the daemon.
+const (
+	scriptsDir = "scripts"
+
+	// Enough for any sensible hook; stops a runaway loop from hanging
+	// the app
+	scriptMaxSteps = 1_000_000
+)
+
+// script is one loaded file's hooks
+type script struct {
+	name           string
+	filter         starlark.Callable
+	onNotification starlark.Callable
+	sortQueue      starlark.Callable
+}
+
+// scriptSet is every script, in file name order
+type scriptSet struct {
+	scripts []script
+	sinks   map[string]Sink // named sinks, for forward()
+}
+
+// loadScripts loads every .star file in the scripts dir. A script that
+// fails to load is an error, like a bad rule.
+func loadScripts(sinks map[string]Sink) (*scriptSet, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Join(dir, scriptsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading scripts dir: %w", err)
+	}
+
+	set := &scriptSet{sinks: sinks}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".star" {
+			continue
+		}
+		s, err := loadScript(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		set.scripts = append(set.scripts, s)
+	}
+	if len(set.scripts) == 0 {
+		return nil, nil
+	}
+	return set, nil
+}
+
+func loadScript(path string) (script, error) {
+	s := script{name: filepath.Base(path)}
+	thread := newScriptThread(s.name, nil)
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, path, nil, scriptBuiltins)
+	if err != nil {
+		return s, fmt.Errorf("script %s: %w", s.name, err)
+	}
+	hook := func(name string) (starlark.Callable, error) {
+		v, ok := globals[name]
+		if !ok {
+			return nil, nil
+		}
+		fn, ok := v.(starlark.Callable)
+		if !ok {
+			return nil, fmt.Errorf("script %s: %s isn't a function", s.name, name)
+		}
+		return fn, nil
+	}
+	if s.filter, err = hook("filter"); err != nil {
+		return s, err
+	}
+	if s.onNotification, err = hook("on_notification"); err != nil {
+		return s, err
+	}
+	if s.sortQueue, err = hook("sort_queue"); err != nil {
+		return s, err
+	}
+	if s.filter == nil && s.onNotification == nil && s.sortQueue == nil {
+		return s, fmt.Errorf("script %s defines none of filter, on_notification or sort_queue", s.name)
+	}
+	return s, nil
+}
+
+// scriptEffectKey is where on_notification's builtins record what they
+// were asked to do
+const scriptEffectKey = "effect"
+
+func newScriptThread(name string, effect *ruleEffect) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  name,
+		Print: func(_ *starlark.Thread, msg string) { log.Printf("script %s: %s", name, msg) },
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	thread.SetLocal(scriptEffectKey, effect)
+	return thread
+}
+
+// effectBuiltin is a builtin that only makes sense in on_notification
+func effectBuiltin(name string, do func(*ruleEffect, starlark.Tuple) error) *starlark.Builtin {
+	return starlark.NewBuiltin(name, func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		effect, _ := thread.Local(scriptEffectKey).(*ruleEffect)
+		if effect == nil {
+			return nil, fmt.Errorf("%s: only works in on_notification", b.Name())
+		}
+		if len(kwargs) > 0 {
+			return nil, fmt.Errorf("%s: unexpected keyword arguments", b.Name())
+		}
+		if err := do(effect, args); err != nil {
+			return nil, fmt.Errorf("%s: %w", b.Name(), err)
+		}
+		return starlark.None, nil
+	})
+}
+
+func noArgs(do func(*ruleEffect)) func(*ruleEffect, starlark.Tuple) error {
+	return func(e *ruleEffect, args starlark.Tuple) error {
+		if len(args) > 0 {
+			return errors.New("takes no arguments")
+		}
+		do(e)
+		return nil
+	}
+}
+
+var scriptBuiltins = starlark.StringDict{
+	"mark_read": effectBuiltin("mark_read", noArgs(func(e *ruleEffect) { e.MarkRead = true })),
+	"highlight": effectBuiltin("highlight", noArgs(func(e *ruleEffect) { e.Highlight = true })),
+	"mute":      effectBuiltin("mute", noArgs(func(e *ruleEffect) { e.Mute = true })),
+	"alert":     effectBuiltin("alert", noArgs(func(e *ruleEffect) { e.Sinks = append(e.Sinks, desktopSink{}) })),
+	"forward": effectBuiltin("forward", func(e *ruleEffect, args starlark.Tuple) error {
+		if len(args) != 1 {
+			return errors.New("takes a sink name")
+		}
+		name, ok := starlark.AsString(args[0])
+		if !ok {
+			return errors.New("takes a sink name")
+		}
+		e.forwards = append(e.forwards, name)
+		return nil
+	}),
+}
+
+func notificationValue(n Notification) starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"id":      starlark.String(n.ID),
+		"title":   starlark.String(n.Title),
+		"type":    starlark.String(n.Type),
+		"game":    starlark.String(notificationGame(n)),
+		"path":    starlark.String(n.Path),
+		"url":     starlark.String("https://www.speedrun.com" + n.Path),
+		"read":    starlark.Bool(n.Read),
+		"date":    starlark.MakeInt64(n.Date),
+		"account": starlark.String(n.Account),
+	})
+}
+
+// call runs one hook, logging a failure once per script and hook rather
+// than failing the caller
+func (s script) call(fn starlark.Callable, effect *ruleEffect, arg starlark.Value) (starlark.Value, bool) {
+	v, err := starlark.Call(newScriptThread(s.name, effect), fn, starlark.Tuple{arg}, nil)
+	if err != nil {
+		logOnce("script."+s.name+"."+fn.Name(), "script %s: %s: %v", s.name, fn.Name(), err)
+		return nil, false
+	}
+	return v, true
+}
+
+// apply runs the notification hooks of every script, adding what they ask
+// for to e
+func (ss *scriptSet) apply(n Notification, e *ruleEffect) {
+	if ss == nil {
+		return
+	}
+	v := notificationValue(n)
+	for _, s := range ss.scripts {
+		if s.filter != nil {
+			if keep, ok := s.call(s.filter, nil, v); ok && !bool(keep.Truth()) {
+				e.Mute = true
+			}
+		}
+		if s.onNotification != nil {
+			s.call(s.onNotification, e, v)
+		}
+	}
+	for _, name := range e.forwards {
+		if sink, ok := ss.sinks[name]; ok {
+			e.Sinks = append(e.Sinks, sink)
+		} else {
+			logOnce("script.sink."+name, "scripts: forward to unknown sink %q", name)
+		}
+	}
+	e.forwards = nil
+}
+
+// sortQueue orders runs by the first script's sort_queue keys. Runs whose
+// keys can't be compared keep their order.
+func (ss *scriptSet) sortQueue(runs []QueueRun, describe func(QueueRun) starlark.Value) {
+	if ss == nil {
+		return
+	}
+	var s script
+	for _, candidate := range ss.scripts {
+		if candidate.sortQueue != nil {
+			s = candidate
+			break
+		}
+	}
+	if s.sortQueue == nil {
+		return
+	}
+
+	keys := make(map[string]starlark.Value, len(runs))
+	for _, r := range runs {
+		if k, ok := s.call(s.sortQueue, nil, describe(r)); ok {
+			keys[r.ID] = k
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		a, okA := keys[runs[i].ID]
+		b, okB := keys[runs[j].ID]
+		if !okA || !okB {
+			return okA && !okB
+		}
+		less, err := starlark.Compare(syntax.LT, a, b)
+		if err != nil {
+			logOnce("script."+s.name+".sort", "script %s: sort_queue: %v", s.name, err)
+			return false
+		}
+		return less
+	})
+}
+
+// scriptRun is a queue run as sort_queue sees it
+func (q queueModel) scriptRun(r QueueRun) starlark.Value {
+	players := make([]starlark.Value, len(r.Players))
+	for i, p := range r.Players {
+		players[i] = starlark.String(p)
+	}
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"id":        starlark.String(r.ID),
+		"url":       starlark.String(r.Weblink),
+		"game":      starlark.String(q.gameName(r.GameID)),
+		"category":  starlark.String(q.categories[r.CategoryID]),
+		"platform":  starlark.String(q.platforms[r.PlatformID]),
+		"players":   starlark.NewList(players),
+		"seconds":   starlark.Float(r.Time.Seconds()),
+		"submitted": starlark.MakeInt64(r.Submitted.Unix()),
+		"video":     starlark.String(r.Video),
+		"comment":   starlark.String(r.Comment),
+		"trust":     starlark.String(r.Trust.String()),
+		"emulated":  starlark.Bool(r.Emulated),
+	})
+}
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Forum comments and run comments the app has shown, one JSON object per
+// line like historyFile, so they can be searched later
+const textsFile = "texts.jsonl"
+
+const searchResults = 100
+
+// textEntry is a piece of text seen on the site
+type textEntry struct {
+	Kind  string    `json:"kind"` // "comment" or "run"
+	ID    string    `json:"id"`
+	Title string    `json:"title"` // e.g. "Alice in Any% discussion"
+	Text  string    `json:"text"`
+	URL   string    `json:"url"`
+	Time  time.Time `json:"time"`
+}
+
+// textsMu keeps loaders running side by side from interleaving lines
+var textsMu sync.Mutex
+
+// textsKnown is the kind/ID of every archived text, read from the file on
+// the first archive and kept up to date after, guarded by textsMu
+var textsKnown map[string]bool
+
+func textsPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, textsFile), nil
+}
+
+// loadTexts reads the archive. Lines that fail to parse are skipped.
+func loadTexts() ([]textEntry, error) {
+	path, err := textsPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening texts: %w", err)
+	}
+	defer f.Close()
+
+	var entries []textEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e textEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			logOnce(fmt.Sprintf("texts.line.%d", line), "texts: skipping line %d: %v", line, err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("reading texts: %w", err)
+	}
+	return entries, nil
+}
+
+// archiveTexts appends the entries not archived yet
+func archiveTexts(entries []textEntry) error {
+	textsMu.Lock()
+	defer textsMu.Unlock()
+
+	if textsKnown == nil {
+		existing, err := loadTexts()
+		if err != nil {
+			return err
+		}
+		textsKnown = make(map[string]bool, len(existing))
+		for _, e := range existing {
+			textsKnown[e.Kind+"/"+e.ID] = true
+		}
+	}
+	known := textsKnown
+
+	path, err := textsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening texts: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		key := e.Kind + "/" + e.ID
+		if e.ID == "" || strings.TrimSpace(e.Text) == "" || known[key] {
+			continue
+		}
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("writing texts: %w", err)
+		}
+		known[key] = true
+	}
+	return nil
+}
+
+// runCommentEntry archives a run's comment
+func runCommentEntry(r QueueRun) textEntry {
+	return textEntry{
+		Kind:  "run",
+		ID:    r.ID,
+		Title: "Run by " + strings.Join(r.Players, ", "),
+		Text:  r.Comment,
+		URL:   r.Weblink,
+		Time:  r.Submitted,
+	}
+}
+
+// searchDoc is one searchable thing: a notification or an archived text
+type searchDoc struct {
+	Kind  string
+	Title string
+	Text  string
+	URL   string
+	Time  time.Time
+}
+
+// searchIndex maps every word to the documents it appears in and how
+// often. It's built from the archives each time the tab opens; a few
+// years of notifications take well under a second.
+type searchIndex struct {
+	docs     []searchDoc
+	postings map[string]map[int]int // word -> doc -> count
+	words    []string               // sorted, for prefix matches
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func buildSearchIndex(history []HistoryEntry, texts []textEntry) *searchIndex {
+	ix := &searchIndex{postings: make(map[string]map[int]int)}
+	for _, e := range history {
+		n := e.Notification
+		ix.docs = append(ix.docs, searchDoc{
+			Kind:  "notification",
+			Title: n.Title,
+			URL:   "https://www.speedrun.com" + n.Path,
+			Time:  e.Time(),
+		})
+	}
+	for _, t := range texts {
+		ix.docs = append(ix.docs, searchDoc{Kind: t.Kind, Title: t.Title, Text: t.Text, URL: t.URL, Time: t.Time})
+	}
+
+	for i, d := range ix.docs {
+		for _, w := range tokenize(d.Title + " " + d.Text) {
+			if ix.postings[w] == nil {
+				ix.postings[w] = make(map[int]int)
+			}
+			ix.postings[w][i]++
+		}
+	}
+	for w := range ix.postings {
+		ix.words = append(ix.words, w)
+	}
+	sort.Strings(ix.words)
+	return ix
+}
+
+// matches counts how often words starting with prefix appear in each doc
+func (ix *searchIndex) matches(prefix string) map[int]int {
+	counts := make(map[int]int)
+	for i := sort.SearchStrings(ix.words, prefix); i < len(ix.words) && strings.HasPrefix(ix.words[i], prefix); i++ {
+		for doc, n := range ix.postings[ix.words[i]] {
+			counts[doc] += n
+		}
+	}
+	return counts
+}
+
+// search finds the documents containing every word of the query (as a
+// prefix, so results show while typing), most matches first and newest
+// among equals
+func (ix *searchIndex) search(query string, limit int) []searchDoc {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	scores := ix.matches(terms[0])
+	for _, t := range terms[1:] {
+		counts := ix.matches(t)
+		for doc := range scores {
+			if n, ok := counts[doc]; ok {
+				scores[doc] += n
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	hits := make([]int, 0, len(scores))
+	for doc := range scores {
+		hits = append(hits, doc)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		return ix.docs[a].Time.After(ix.docs[b].Time)
+	})
+
+	results := make([]searchDoc, 0, min(len(hits), limit))
+	for _, doc := range hits[:min(len(hits), limit)] {
+		results = append(results, ix.docs[doc])
+	}
+	return results
+}
+
+type searchIndexMsg struct {
+	index *searchIndex
+	err   error
+}
+
+func loadSearchIndexCmd() tea.Cmd {
+	return func() tea.Msg {
+		history, err := loadHistory()
+		if err != nil {
+			return searchIndexMsg{err: err}
+		}
+		texts, err := loadTexts()
+		if err != nil {
+			return searchIndexMsg{err: err}
+		}
+		return searchIndexMsg{index: buildSearchIndex(history, texts)}
+	}
+}
+
+// searchGroup is one tab of results
+type searchGroup int
+
+const (
+	searchArchive searchGroup = iota
+	searchGames
+	searchUsers
+	searchThreads
+	searchGroupCount
+)
+
+var searchGroupNames = [...]string{
+	searchArchive: "Archive",
+	searchGames:   "Games",
+	searchUsers:   "Users",
+	searchThreads: "Threads",
+}
+
+// searchModel is the search tab: one query box over everything archived,
+// the site's games and users, and forum threads seen in the app
+type searchModel struct {
+	input    textinput.Model
+	index    *searchIndex
+	results  []searchDoc
+	selected int
+	loading  bool
+	err      error
+
+	client    *Client
+	group     searchGroup
+	seq       int // bumped per edit, so only the last one asks the site
+	site      *SiteSearch
+	siteQuery string
+	siteErr   error
+}
+
+func newSearchModel(client *Client) searchModel {
+	input := textinput.New()
+	input.Placeholder = "search games, users, threads and everything archived"
+	input.Width = 60
+	input.CharLimit = 200
+	return searchModel{input: input, client: client}
+}
+
+// activate rebuilds the index so what was archived since shows up
+func (s searchModel) activate() (searchModel, tea.Cmd) {
+	s.loading = true
+	return s, tea.Batch(loadSearchIndexCmd(), s.input.Focus())
+}
+
+func (s searchModel) update(msg tea.Msg) (searchModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case searchIndexMsg:
+		s.loading = false
+		s.index, s.err = msg.index, msg.err
+		s.refresh()
+		return s, nil
+
+	case siteSearchTickMsg:
+		query := strings.TrimSpace(s.input.Value())
+		if msg.seq != s.seq || query == "" || s.client == nil {
+			return s, nil
+		}
+		return s, siteSearchCmd(s.client, query)
+
+	case siteSearchMsg:
+		// An answer to a query that has since been edited
+		if msg.query != strings.TrimSpace(s.input.Value()) {
+			return s, nil
+		}
+		s.site, s.siteQuery, s.siteErr = msg.results, msg.query, msg.err
+		s.refresh()
+		return s, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if s.selected > 0 {
+				s.selected--
+			}
+			return s, nil
+		case "down", "ctrl+n":
+			if s.selected < len(s.results)-1 {
+				s.selected++
+			}
+			return s, nil
+		case "ctrl+right", "ctrl+t":
+			s.group = (s.group + 1) % searchGroupCount
+			s.selected = 0
+			s.refresh()
+			return s, nil
+		case "ctrl+left":
+			s.group = (s.group + searchGroupCount - 1) % searchGroupCount
+			s.selected = 0
+			s.refresh()
+			return s, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	before := s.input.Value()
+	s.input, cmd = s.input.Update(msg)
+	if s.input.Value() != before {
+		s.seq++
+		s.refresh()
+		if strings.TrimSpace(s.input.Value()) != "" {
+			cmd = tea.Batch(cmd, siteSearchTickCmd(s.seq))
+		}
+	}
+	return s, cmd
+}
+
+func (s *searchModel) refresh() {
+	s.results = s.groupResults(s.group)
+	s.selected = min(s.selected, max(len(s.results)-1, 0))
+}
+
+// groupResults is what one tab shows for the current query
+func (s searchModel) groupResults(g searchGroup) []searchDoc {
+	query := strings.TrimSpace(s.input.Value())
+	switch g {
+	case searchGames, searchUsers:
+		if s.site == nil || s.siteQuery != query {
+			return nil
+		}
+		if g == searchGames {
+			return s.site.games()
+		}
+		return s.site.users()
+	case searchThreads:
+		if s.index == nil {
+			return nil
+		}
+		// The best matching comment stands for its thread
+		var threads []searchDoc
+		seen := make(map[string]bool)
+		for _, d := range s.index.search(query, len(s.index.docs)) {
+			if d.Kind != "comment" || seen[d.URL] {
+				continue
+			}
+			seen[d.URL] = true
+			threads = append(threads, d)
+			if len(threads) == searchResults {
+				break
+			}
+		}
+		return threads
+	}
+	if s.index == nil {
+		return nil
+	}
+	return s.index.search(query, searchResults)
+}
+
+func (s searchModel) selectedDoc() (searchDoc, bool) {
+	if s.selected >= len(s.results) {
+		return searchDoc{}, false
+	}
+	return s.results[s.selected], true
+}
+
+// snippet is up to width characters of text around the first query word
+func snippet(text, query string, width int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	start := 0
+	if terms := tokenize(query); len(terms) > 0 {
+		if i := strings.Index(strings.ToLower(text), terms[0]); i >= 0 {
+			start = max(len([]rune(text[:i]))-width/3, 0)
+		}
+	}
+	end := min(start+width, len(runes))
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+// groupTabs shows the result groups with their counts once known
+func (s searchModel) groupTabs() string {
+	query := strings.TrimSpace(s.input.Value())
+	tabs := make([]string, searchGroupCount)
+	for g, name := range searchGroupNames {
+		label := name
+		switch {
+		case query == "":
+		case (searchGroup(g) == searchGames || searchGroup(g) == searchUsers) && (s.site == nil || s.siteQuery != query):
+		default:
+			label += fmt.Sprintf(" (%d)", len(s.groupResults(searchGroup(g))))
+		}
+		style := inactiveTabStyle
+		if searchGroup(g) == s.group {
+			style = activeTabStyle
+		}
+		tabs[g] = style.Render(label)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Center, tabs...)
+}
+
+func (s searchModel) view() string {
+	var b strings.Builder
+	b.WriteString(s.input.View() + "\n" + s.groupTabs() + "\n\n")
+	remote := s.group == searchGames || s.group == searchUsers
+	query := strings.TrimSpace(s.input.Value())
+	switch {
+	case remote && query == "":
+		b.WriteString(urlStyle.Render("Type to search speedrun.com"))
+		return b.String()
+	case remote && s.client == nil:
+		b.WriteString("Not signed in")
+		return b.String()
+	case remote && s.siteErr != nil && s.siteQuery == query:
+		b.WriteString(fmt.Sprintf("Error: %v", s.siteErr))
+		return b.String()
+	case remote && (s.site == nil || s.siteQuery != query):
+		b.WriteString("Searching...")
+		return b.String()
+	case !remote && (s.loading || s.index == nil):
+		b.WriteString("Indexing...")
+		return b.String()
+	case !remote && s.err != nil:
+		b.WriteString(fmt.Sprintf("Error: %v", s.err))
+		return b.String()
+	case !remote && query == "":
+		b.WriteString(urlStyle.Render(fmt.Sprintf("%d notifications and comments archived", len(s.index.docs))))
+		return b.String()
+	case len(s.results) == 0:
+		b.WriteString("No matches")
+		return b.String()
+	}
+
+	for i, d := range s.results {
+		var item strings.Builder
+		if d.Time.IsZero() {
+			item.WriteString(d.Kind + "\n")
+		} else {
+			item.WriteString(fmt.Sprintf("%s • %s\n", d.Kind, d.Time.Local().Format("2006-01-02")))
+		}
+		item.WriteString(d.Title)
+		if d.Text != "" {
+			item.WriteString("\n" + snippet(d.Text, s.input.Value(), 100))
+		}
+		style := unselectedItemStyle
+		if i == s.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(item.String()) + "\n")
+	}
+	return b.String()
+}
+
+func (s searchModel) help() string {
+	return "type to search • ctrl+←/→ results • ↑/↓ navigate • enter open • esc back"
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Which day sections are collapsed, so they stay that way next time
+const sectionsFile = "sections.json"
+
+var sectionHeaderStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#A0A0A0")).
+	Bold(true)
+
+type daySection int
+
+const (
+	sectionToday daySection = iota
+	sectionYesterday
+	sectionThisWeek
+	sectionOlder
+)
+
+var sectionNames = [...]string{
+	sectionToday:     "Today",
+	sectionYesterday: "Yesterday",
+	sectionThisWeek:  "This Week",
+	sectionOlder:     "Older",
+}
+
+// sectionOf puts t under the day header it's listed under. The week
+// starts on Monday, like the heatmap's.
+func sectionOf(t, now time.Time) daySection {
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	switch {
+	case !t.Before(today):
+		return sectionToday
+	case !t.Before(today.AddDate(0, 0, -1)):
+		return sectionYesterday
+	case !t.Before(monday):
+		return sectionThisWeek
+	}
+	return sectionOlder
+}
+
+// listRow is a line of the notification list below the pins: a section
+// header or a notification in an expanded section
+type listRow struct {
+	header        bool
+	section       daySection
+	count, unread int // for headers
+	n             Notification
+}
+
+func loadCollapsed() map[string]bool {
+	var collapsed map[string]bool
+	if err := loadState(sectionsFile, &collapsed); err != nil {
+		log.Printf("sections: %v", err)
+	}
+	if collapsed == nil {
+		collapsed = map[string]bool{}
+	}
+	return collapsed
+}
+
+// notificationRows groups the notifications shown by day, keeping their
+// order within a section
+func (m model) notificationRows(visible []Notification) []listRow {
+	now := time.Now()
+	var groups [len(sectionNames)][]Notification
+	for _, n := range visible {
+		s := sectionOf(time.Unix(n.Date, 0), now)
+		groups[s] = append(groups[s], n)
+	}
+
+	var rows []listRow
+	for s, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		header := listRow{header: true, section: daySection(s), count: len(group)}
+		for _, n := range group {
+			if !n.Read {
+				header.unread++
+			}
+		}
+		rows = append(rows, header)
+		if m.collapsed[sectionNames[s]] {
+			continue
+		}
+		for _, n := range group {
+			rows = append(rows, listRow{section: daySection(s), n: n})
+		}
+	}
+	return rows
+}
+
+// selectedRow is the row under the cursor, if it isn't on a pin
+func (m model) selectedRow(rows []listRow) (listRow, bool) {
+	i := m.selected - len(m.pins)
+	if i < 0 || i >= len(rows) {
+		return listRow{}, false
+	}
+	return rows[i], true
+}
+
+// toggleSection collapses or expands a section and leaves the cursor on
+// its header
+func (m model) toggleSection(s daySection, visible []Notification) model {
+	name := sectionNames[s]
+	m.collapsed[name] = !m.collapsed[name]
+	if !m.collapsed[name] {
+		delete(m.collapsed, name)
+	}
+	if err := saveState(sectionsFile, m.collapsed); err != nil {
+		m.status = fmt.Sprintf("Couldn't save sections: %v", err)
+	}
+	for i, r := range m.notificationRows(visible) {
+		if r.header && r.section == s {
+			m.selected = len(m.pins) + i
+		}
+	}
+	return m
+}
+
+func (r listRow) renderHeader(collapsed bool) string {
+	arrow := "▾"
+	if collapsed {
+		arrow = "▸"
+	}
+	text := fmt.Sprintf("%s %s · %d", arrow, sectionNames[r.section], r.count)
+	if r.unread > 0 {
+		text += fmt.Sprintf(" (%d unread)", r.unread)
+	}
+	return sectionHeaderStyle.Render(text)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+var errNoSession = errors.New("this action needs a session, pass -session")
+
+var errSessionExpired = errors.New("session is not signed in, the PHPSESSID cookie may have expired")
+
+type SessionUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Session struct {
+	SignedIn  bool         `json:"signedIn"`
+	User      *SessionUser `json:"user"`
+	CSRFToken string       `json:"csrfToken"`
+}
+
+// GetSession returns the logged in user and the CSRF token that write
+// endpoints expect alongside the session cookie.
+func (c *Client) GetSession() (*Session, error) {
+	var result struct {
+		Session Session `json:"session"`
+	}
+	if err := c.post("GetSession", struct{}{}, &result); err != nil {
+		return nil, fmt.Errorf("fetching session: %w", err)
+	}
+	return &result.Session, nil
+}
+
+// token returns the cached CSRF token, fetching a new one when there is none
+// or when refresh is set.
+func (c *Client) token(refresh bool) (string, error) {
+	c.csrfMu.Lock()
+	defer c.csrfMu.Unlock()
+
+	if c.csrfToken != "" && !refresh {
+		return c.csrfToken, nil
+	}
+
+	session, err := c.GetSession()
+	if err != nil {
+		return "", err
+	}
+	if !session.SignedIn || session.CSRFToken == "" {
+		return "", errSessionExpired
+	}
+
+	c.csrfToken = session.CSRFToken
+	return c.csrfToken, nil
+}
+
+// write posts body to a write endpoint with the CSRF token added to it. If
+// the API rejects the token (it rotates on re-login) a fresh one is fetched
+// and the request is retried once.
+func (c *Client) write(endpoint string, body any, out any) (err error) {
+	defer func() { c.audit(endpoint, body, err) }()
+
+	if c.sessionID == "" {
+		return errNoSession
+	}
+	if c.dryRun {
+		return c.skipWrite(endpoint, body)
+	}
+
+	fields, err := toFields(body)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := c.token(attempt > 0)
+		if err != nil {
+			return err
+		}
+
+		fields["csrfToken"], _ = json.Marshal(token)
+		err = c.post(endpoint, fields, out)
+		if err == nil || attempt > 0 || !isCSRFError(err) {
+			return err
+		}
+	}
+}
+
+// toFields flattens a request body into its top level JSON fields so the
+// token can be added without every body type declaring it.
+func toFields(body any) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if body == nil {
+		return fields, nil
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request body: %w", err)
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("request body must be a JSON object: %w", err)
+	}
+	return fields, nil
+}
+
+func isCSRFError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+	return strings.Contains(strings.ToLower(apiErr.Body), "csrf")
+}
+
+// skipWrite logs a write instead of sending it, under -dry-run. It always
+// succeeds, so the app carries on as if the site had accepted it.
+func (c *Client) skipWrite(endpoint string, body any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	log.Printf("dry run, not sent: %s %s", endpoint, raw)
+	c.skipped.Add(1)
+	return nil
+}
+
+// DryRun reports whether writes are being skipped, and how many have been
+func (c *Client) DryRun() (bool, int64) {
+	return c.dryRun, c.skipped.Load()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Alert is something worth telling the user about outside the list, like
+// losing a leaderboard position.
+type Alert struct {
+	Title string
+	Body  string
+	URL   string
+	Game  string // abbreviation, for routes; empty when it's about no game
+}
+
+// Sink delivers alerts somewhere: the desktop, a chat webhook, ...
+type Sink interface {
+	Send(Alert) error
+}
+
+// SinkConfig is one entry of the "sinks" list in the config file
+type SinkConfig struct {
+	Type string `json:"type"` // "desktop" or "webhook"
+	URL  string `json:"url,omitempty"`
+	Name string `json:"name,omitempty"` // for rules that forward here
+}
+
+func buildSinks(configs []SinkConfig) ([]Sink, error) {
+	var sinks []Sink
+	for _, sc := range configs {
+		sink, err := buildSink(sc)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+func buildSink(sc SinkConfig) (Sink, error) {
+	switch sc.Type {
+	case "desktop":
+		return desktopSink{}, nil
+	case "webhook":
+		if sc.URL == "" {
+			return nil, fmt.Errorf("webhook sink needs a url")
+		}
+		return webhookSink{url: sc.URL}, nil
+	}
+	return nil, fmt.Errorf("unknown sink type %q", sc.Type)
+}
+
+// sendAlert delivers an alert to every sink. Failures are logged rather
+// than returned since one broken sink shouldn't stop the others.
+func sendAlert(sinks []Sink, alert Alert) {
+	for _, s := range sinks {
+		if err := s.Send(alert); err != nil {
+			log.Printf("sink %T: %v", s, err)
+		}
+	}
+}
+
+// webhookSink posts alerts as JSON. The payload carries both "content"
+// (Discord) and "text" (Slack, Mattermost) so common chat webhooks work.
+type webhookSink struct {
+	url string
+}
+
+func (w webhookSink) Send(a Alert) error {
+	msg := fmt.Sprintf("**%s**\n%s", a.Title, a.Body)
+	if a.URL != "" {
+		msg += "\n" + a.URL
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"content": msg,
+		"text":    msg,
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling webhook payload: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(w.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
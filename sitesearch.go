@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How long the query has to sit still before the site is asked, so typing
+// a word doesn't send a request per letter
+const siteSearchDelay = 300 * time.Millisecond
+
+const siteSearchLimit = 25
+
+// SiteSearch is what the site's search box finds for a query
+type SiteSearch struct {
+	Games []Game   `json:"gameList"`
+	Users []Player `json:"userList"`
+}
+
+// Search asks the site for games and users matching query. The site's
+// search doesn't cover the forums.
+func (c *Client) Search(query string) (*SiteSearch, error) {
+	body := struct {
+		Query        string `json:"query"`
+		IncludeGames bool   `json:"includeGames"`
+		IncludeUsers bool   `json:"includeUsers"`
+		Limit        int    `json:"limit"`
+	}{
+		Query:        query,
+		IncludeGames: true,
+		IncludeUsers: true,
+		Limit:        siteSearchLimit,
+	}
+
+	var result SiteSearch
+	if err := c.post("GetSearch", body, &result); err != nil {
+		return nil, fmt.Errorf("searching the site: %w", err)
+	}
+	return &result, nil
+}
+
+// siteSearchTickMsg fires once the query has sat still; seq says which
+// keystroke it was for
+type siteSearchTickMsg struct{ seq int }
+
+type siteSearchMsg struct {
+	query   string
+	results *SiteSearch
+	err     error
+}
+
+func siteSearchTickCmd(seq int) tea.Cmd {
+	return tea.Tick(siteSearchDelay, func(time.Time) tea.Msg { return siteSearchTickMsg{seq} })
+}
+
+func siteSearchCmd(client *Client, query string) tea.Cmd {
+	return func() tea.Msg {
+		results, err := client.Search(query)
+		return siteSearchMsg{query: query, results: results, err: err}
+	}
+}
+
+// games and users turn site results into rows like the archive's
+func (s *SiteSearch) games() []searchDoc {
+	docs := make([]searchDoc, 0, len(s.Games))
+	for _, g := range s.Games {
+		docs = append(docs, searchDoc{Kind: "game", Title: g.Name, URL: "https://www.speedrun.com/" + g.URL})
+	}
+	return docs
+}
+
+func (s *SiteSearch) users() []searchDoc {
+	docs := make([]searchDoc, 0, len(s.Users))
+	for _, u := range s.Users {
+		docs = append(docs, searchDoc{Kind: "user", Title: u.Name, URL: "https://www.speedrun.com/users/" + u.URL})
+	}
+	return docs
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const splitsIOURL = "https://splits.io"
+
+// A splits.io run page, or its API link as v1 gives it
+var splitsIOLink = regexp.MustCompile(`splits\.io/(?:api/v\d+/runs/)?([0-9a-z]+)`)
+
+// Segment is one split of a run from splits.io
+type Segment struct {
+	Name     string
+	Duration time.Duration // 0 when it was skipped
+}
+
+// splitsIOID finds the splits.io run in the first text that links one
+func splitsIOID(texts ...string) string {
+	for _, t := range texts {
+		if m := splitsIOLink.FindStringSubmatch(t); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// GetSplits fetches a run's segments from splits.io. Real time is used, or
+// game time for runs timed without it.
+func GetSplits(id string) ([]Segment, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(splitsIOURL + "/api/v4/runs/" + url.PathEscape(id))
+	if err != nil {
+		return nil, fmt.Errorf("fetching splits: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching splits: unexpected status code %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Run struct {
+			Segments []struct {
+				Name            string `json:"name"`
+				RealtimeMS      int64  `json:"realtime_duration_ms"`
+				GametimeMS      int64  `json:"gametime_duration_ms"`
+				RealtimeSkipped bool   `json:"realtime_skipped"`
+			} `json:"segments"`
+		} `json:"run"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding splits: %w", err)
+	}
+
+	segments := make([]Segment, len(result.Run.Segments))
+	for i, s := range result.Run.Segments {
+		ms := s.RealtimeMS
+		if ms == 0 || s.RealtimeSkipped {
+			ms = s.GametimeMS
+		}
+		segments[i] = Segment{Name: s.Name, Duration: time.Duration(ms) * time.Millisecond}
+	}
+	return segments, nil
+}
+
+// subcategories lists which of a category's variables split its board
+func (c *Client) subcategories(categoryID string) (map[string]bool, error) {
+	var result struct {
+		Data []struct {
+			ID            string `json:"id"`
+			IsSubcategory bool   `json:"is-subcategory"`
+		} `json:"data"`
+	}
+	if err := c.getV1("/categories/"+url.PathEscape(categoryID)+"/variables", &result); err != nil {
+		return nil, fmt.Errorf("fetching variables: %w", err)
+	}
+	subs := make(map[string]bool)
+	for _, v := range result.Data {
+		if v.IsSubcategory {
+			subs[v.ID] = true
+		}
+	}
+	return subs, nil
+}
+
+// getWorldRecord fetches the top run of the board a run is on
+func (c *Client) getWorldRecord(run v1Run) (*v1Run, error) {
+	subs, err := c.subcategories(run.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/leaderboards/" + url.PathEscape(run.Game) + "/category/" + url.PathEscape(run.Category)
+	if run.Level != "" {
+		path = "/leaderboards/" + url.PathEscape(run.Game) + "/level/" + url.PathEscape(run.Level) + "/" + url.PathEscape(run.Category)
+	}
+	q := url.Values{"top": {"1"}, "embed": {"players"}}
+	for variable, value := range run.Values {
+		if subs[variable] {
+			q.Set("var-"+variable, value)
+		}
+	}
+
+	var result struct {
+		Data struct {
+			Runs []struct {
+				Place int   `json:"place"`
+				Run   v1Run `json:"run"`
+			} `json:"runs"`
+		} `json:"data"`
+	}
+	if err := c.getV1(path+"?"+q.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("fetching world record: %w", err)
+	}
+	if len(result.Data.Runs) == 0 {
+		return nil, nil
+	}
+	return &result.Data.Runs[0].Run, nil
+}
+
+// splitsLink is the splits.io link the run was submitted with, if any
+func (r v1Run) splitsLink() string {
+	if r.Splits == nil {
+		return ""
+	}
+	return r.Splits.URI
+}
+
+// matchSegments pairs each of a run's segments with the comparison's,
+// by position when both have as many and by name otherwise. Segments
+// without a match get -1.
+func matchSegments(run, wr []Segment) []int {
+	match := make([]int, len(run))
+	for i := range run {
+		match[i] = -1
+		if len(run) == len(wr) {
+			match[i] = i
+			continue
+		}
+		for j, s := range wr {
+			if strings.EqualFold(strings.TrimSpace(s.Name), strings.TrimSpace(run[i].Name)) {
+				match[i] = j
+				break
+			}
+		}
+	}
+	return match
+}
+
+// runSplits renders a run's splits next to the world record's, with how
+// much each segment gained or lost against it and where that leaves the
+// run so far
+func runSplits(client *Client, run v1Run, id string) string {
+	var b strings.Builder
+	b.WriteString("\n" + titleStyle.Render("Splits") + "\n\n")
+
+	segments, err := GetSplits(id)
+	if err != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n", err))
+		return b.String()
+	}
+	if len(segments) == 0 {
+		b.WriteString(urlStyle.Render("No segments on splits.io") + "\n")
+		return b.String()
+	}
+
+	var wrSegments []Segment
+	wr, err := client.getWorldRecord(run)
+	switch {
+	case err != nil:
+		b.WriteString(urlStyle.Render(fmt.Sprintf("Couldn't load the world record: %v", err)) + "\n\n")
+	case wr == nil:
+	case wr.ID == run.ID:
+		b.WriteString(urlStyle.Render("This run is the world record") + "\n\n")
+	default:
+		wrRun := wr.queueRun()
+		wrID := splitsIOID(wr.splitsLink(), wr.Comment)
+		if wrID == "" {
+			b.WriteString(urlStyle.Render(fmt.Sprintf("The world record by %s has no splits", strings.Join(wrRun.Players, ", "))) + "\n\n")
+			break
+		}
+		if wrSegments, err = GetSplits(wrID); err != nil {
+			b.WriteString(urlStyle.Render(fmt.Sprintf("Couldn't load the world record's splits: %v", err)) + "\n\n")
+			break
+		}
+		b.WriteString(urlStyle.Render(fmt.Sprintf("Against the world record by %s, %s", strings.Join(wrRun.Players, ", "), formatRunTime(wrRun.Time))) + "\n\n")
+	}
+
+	match := matchSegments(segments, wrSegments)
+	if len(wrSegments) > 0 {
+		b.WriteString(fmt.Sprintf("%-24s %11s %11s %11s %11s\n", "Segment", "Time", "WR", "Segment", "Total"))
+	} else {
+		b.WriteString(fmt.Sprintf("%-24s %11s\n", "Segment", "Time"))
+	}
+	var total, wrTotal time.Duration
+	for i, s := range segments {
+		total += s.Duration
+		line := fmt.Sprintf("%-24s %11s", truncate(s.Name, 24), formatSegment(s.Duration))
+		if j := match[i]; j >= 0 {
+			w := wrSegments[j]
+			// Totals only line up while every segment so far has a match
+			wrTotal += w.Duration
+			line += fmt.Sprintf(" %11s", formatSegment(w.Duration))
+			if s.Duration > 0 && w.Duration > 0 {
+				line += " " + padDelta(renderDelta(s.Duration-w.Duration))
+				if wrTotal > 0 && !slices.Contains(match[:i+1], -1) {
+					line += " " + padDelta(renderDelta(total-wrTotal))
+				}
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// formatSegment is a segment's time, or a dash for one that was skipped
+func formatSegment(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return formatRunTime(d)
+}
+
+// padDelta right aligns a colored delta in the table's columns
+func padDelta(delta string) string {
+	return strings.Repeat(" ", max(11-lipgloss.Width(delta), 0)) + delta
+}
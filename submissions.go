@@ -0,0 +1,1011 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Unfinished submissions, saved after every step so quitting halfway
+// through doesn't lose them
+const draftsFile = "drafts.json"
+
+type submitStep int
+
+const (
+	stepGame submitStep = iota
+	stepCategory
+	stepValues
+	stepPlatform
+	stepTime
+	stepVideo
+	stepComment
+	stepDate
+	stepReview
+)
+
+var stepNames = [...]string{
+	stepGame:     "Game",
+	stepCategory: "Category",
+	stepValues:   "Subcategory",
+	stepPlatform: "Platform",
+	stepTime:     "Time",
+	stepVideo:    "Video",
+	stepComment:  "Comment",
+	stepDate:     "Date played",
+	stepReview:   "Review",
+}
+
+// Draft is a run submission in progress
+type Draft struct {
+	ID         string            `json:"id"`
+	Step       submitStep        `json:"step"`
+	Game       string            `json:"game"` // abbreviation, e.g. "sm64"
+	GameName   string            `json:"game_name,omitempty"`
+	GameID     string            `json:"game_id,omitempty"`
+	CategoryID string            `json:"category_id,omitempty"`
+	Category   string            `json:"category,omitempty"`
+	Values     map[string]string `json:"values,omitempty"` // variable ID -> value ID
+	PlatformID string            `json:"platform_id,omitempty"`
+	Platform   string            `json:"platform,omitempty"`
+	Time       string            `json:"time,omitempty"` // as typed, e.g. 1:23:45.678
+	Video      string            `json:"video,omitempty"`
+	VideoTitle string            `json:"video_title,omitempty"` // read with yt-dlp, for suggestions
+	Comment    string            `json:"comment,omitempty"`
+	Date       string            `json:"date,omitempty"` // YYYY-MM-DD
+	Updated    time.Time         `json:"updated"`
+}
+
+func (d Draft) empty() bool {
+	return d.Game == "" && d.Time == "" && d.Video == "" && d.Comment == ""
+}
+
+// describe is the one line shown in the drafts list
+func (d Draft) describe() string {
+	parts := []string{}
+	if d.GameName != "" {
+		parts = append(parts, d.GameName)
+	} else if d.Game != "" {
+		parts = append(parts, d.Game)
+	}
+	if d.Category != "" {
+		parts = append(parts, d.Category)
+	}
+	what := strings.Join(parts, " › ")
+	if what == "" {
+		what = "Untitled"
+	}
+	if d.Time != "" {
+		what += " in " + d.Time
+	}
+	return what
+}
+
+func loadDrafts() ([]Draft, error) {
+	var drafts []Draft
+	err := loadState(draftsFile, &drafts)
+	return drafts, err
+}
+
+// storeDraft adds or replaces d by ID and saves the list
+func storeDraft(drafts []Draft, d Draft) ([]Draft, error) {
+	d.Updated = time.Now()
+	kept := make([]Draft, 0, len(drafts)+1)
+	for _, existing := range drafts {
+		if existing.ID != d.ID {
+			kept = append(kept, existing)
+		}
+	}
+	kept = append(kept, d)
+	if err := saveState(draftsFile, kept); err != nil {
+		return drafts, fmt.Errorf("saving draft: %w", err)
+	}
+	return kept, nil
+}
+
+func deleteDraft(drafts []Draft, id string) ([]Draft, error) {
+	kept := slices.DeleteFunc(slices.Clone(drafts), func(d Draft) bool { return d.ID == id })
+	if err := saveState(draftsFile, kept); err != nil {
+		return drafts, fmt.Errorf("saving drafts: %w", err)
+	}
+	return kept, nil
+}
+
+// subcategoryVars are the variables that pick a board within a category,
+// in the order the site asks for them
+func subcategoryVars(data *GameData, categoryID string) []Variable {
+	var vars []Variable
+	for _, v := range data.Variables {
+		if v.IsSubcategory && (v.CategoryID == "" || v.CategoryID == categoryID) {
+			vars = append(vars, v)
+		}
+	}
+	sort.SliceStable(vars, func(i, j int) bool { return vars[i].Pos < vars[j].Pos })
+	return vars
+}
+
+type runValue struct {
+	VariableID string `json:"variableId"`
+	ValueID    string `json:"valueId"`
+}
+
+type runTimeParts struct {
+	Hour        int `json:"hour"`
+	Minute      int `json:"minute"`
+	Second      int `json:"second"`
+	Millisecond int `json:"millisecond"`
+}
+
+func splitRunTime(d time.Duration) runTimeParts {
+	return runTimeParts{
+		Hour:        int(d / time.Hour),
+		Minute:      int(d % time.Hour / time.Minute),
+		Second:      int(d % time.Minute / time.Second),
+		Millisecond: int(d % time.Second / time.Millisecond),
+	}
+}
+
+// runSettings is the body of the site's run form
+type runSettings struct {
+	RunID       string       `json:"runId,omitempty"`
+	GameID      string       `json:"gameId"`
+	CategoryID  string       `json:"categoryId"`
+	LevelID     string       `json:"levelId,omitempty"`
+	PlayerNames []string     `json:"playerNames"`
+	Time        runTimeParts `json:"time"`
+	PlatformID  string       `json:"platformId"`
+	Emulator    bool         `json:"emulator"`
+	Video       string       `json:"video"`
+	Comment     string       `json:"comment"`
+	Date        int64        `json:"date"`
+	Values      []runValue   `json:"values"`
+}
+
+// SubmitRun submits a finished draft as the signed in user and returns the
+// new run's ID
+func (c *Client) SubmitRun(d Draft) (string, error) {
+	runTime, err := parseRunTime(d.Time)
+	if err != nil {
+		return "", err
+	}
+	date, err := time.ParseInLocation(time.DateOnly, d.Date, time.Local)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q", d.Date)
+	}
+	session, err := c.GetSession()
+	if err != nil {
+		return "", err
+	}
+	if !session.SignedIn || session.User == nil {
+		return "", errNoSession
+	}
+
+	settings := runSettings{
+		GameID:      d.GameID,
+		CategoryID:  d.CategoryID,
+		PlayerNames: []string{session.User.Name},
+		Time:        splitRunTime(runTime),
+		PlatformID:  d.PlatformID,
+		Video:       d.Video,
+		Comment:     d.Comment,
+		Date:        date.Unix(),
+		Values:      []runValue{},
+	}
+	for variable, value := range d.Values {
+		settings.Values = append(settings.Values, runValue{VariableID: variable, ValueID: value})
+	}
+
+	body := struct {
+		Settings runSettings `json:"settings"`
+	}{
+		Settings: settings,
+	}
+	var result struct {
+		RunID string `json:"runId"`
+	}
+	if err := c.write("PutRunSettings", body, &result); err != nil {
+		return "", fmt.Errorf("submitting run: %w", err)
+	}
+	return result.RunID, nil
+}
+
+type submitGameMsg struct {
+	data *GameData
+	err  error
+}
+
+type submitResultMsg struct {
+	draft Draft
+	runID string
+	err   error
+}
+
+type videoCheckMsg struct {
+	draftID string
+	video   string
+	issues  []VideoIssue
+}
+
+// checkVideoCmd checks a draft's video against its time, for the review
+func checkVideoCmd(twitch *TwitchClient, d Draft) tea.Cmd {
+	return func() tea.Msg {
+		runTime, _ := parseRunTime(d.Time)
+		return videoCheckMsg{draftID: d.ID, video: d.Video, issues: checkVideo(d.Video, runTime, twitch)}
+	}
+}
+
+type liveSplitRunMsg struct {
+	run *LiveSplitRun
+	err error
+}
+
+// watchOBSCmd waits for OBS to stop recording the run
+func watchOBSCmd(cfg OBSConfig) tea.Cmd {
+	return func() tea.Msg {
+		obs, err := DialOBS(cfg)
+		if err != nil {
+			return liveSplitRunMsg{err: err}
+		}
+		defer obs.Close()
+		run, err := obs.WatchRecording(cfg.TimerSource, 250*time.Millisecond)
+		if err != nil {
+			return liveSplitRunMsg{err: fmt.Errorf("reading OBS: %w", err)}
+		}
+		return liveSplitRunMsg{run: run}
+	}
+}
+
+// watchLiveSplitCmd waits for the run on LiveSplit to end
+func watchLiveSplitCmd(addr string) tea.Cmd {
+	return func() tea.Msg {
+		ls, err := DialLiveSplit(addr)
+		if err != nil {
+			return liveSplitRunMsg{err: err}
+		}
+		defer ls.Close()
+		run, err := ls.WatchRun(250 * time.Millisecond)
+		if err != nil {
+			return liveSplitRunMsg{err: fmt.Errorf("reading LiveSplit: %w", err)}
+		}
+		return liveSplitRunMsg{run: run}
+	}
+}
+
+type submitOption struct {
+	ID   string
+	Name string
+}
+
+// submitWizard walks through a submission one field at a time
+type submitWizard struct {
+	draft    Draft
+	game     *GameData
+	options  []submitOption // for the choice steps
+	selected int
+	variable Variable // the subcategory being asked for
+	input    textinput.Model
+	err      error
+
+	suggestions []submitSuggestion // for the category step
+
+	// What the check of the video found, shown on the review
+	checkingVideo bool
+	videoChecked  bool
+	videoIssues   []VideoIssue
+}
+
+func (w *submitWizard) choosing() bool {
+	switch w.draft.Step {
+	case stepCategory, stepValues, stepPlatform:
+		return true
+	}
+	return false
+}
+
+// enter sets up a step: its choices, or its text field
+func (w *submitWizard) enter(step submitStep) tea.Cmd {
+	w.draft.Step = step
+	w.options = nil
+	w.selected = 0
+	w.err = nil
+	w.input.Blur()
+
+	var current string
+	switch step {
+	case stepCategory:
+		current = w.draft.CategoryID
+		for _, c := range w.game.Categories {
+			if !c.IsPerLevel && !c.Archived {
+				w.options = append(w.options, submitOption{ID: c.ID, Name: c.Name})
+			}
+		}
+	case stepValues:
+		vars := subcategoryVars(w.game, w.draft.CategoryID)
+		i := 0
+		for i < len(vars) && w.draft.Values[vars[i].ID] != "" {
+			i++
+		}
+		if i == len(vars) {
+			return w.enter(stepPlatform)
+		}
+		w.variable = vars[i]
+		current = w.variable.DefaultValue
+		for _, v := range w.game.Values {
+			if v.VariableID == w.variable.ID {
+				w.options = append(w.options, submitOption{ID: v.ID, Name: v.Name})
+			}
+		}
+	case stepPlatform:
+		current = w.draft.PlatformID
+		for _, p := range w.game.Platforms {
+			w.options = append(w.options, submitOption{ID: p.ID, Name: p.Name})
+		}
+	case stepDate:
+		if w.draft.Date == "" {
+			w.draft.Date = time.Now().Format(time.DateOnly)
+		}
+	case stepReview:
+		return nil
+	}
+	if w.choosing() {
+		for i, o := range w.options {
+			if o.ID == current {
+				w.selected = i
+			}
+		}
+		return nil
+	}
+
+	w.input.SetValue(*w.field())
+	w.input.Placeholder = map[submitStep]string{
+		stepGame:    "game abbreviation from the site URL, e.g. sm64",
+		stepTime:    "1:23:45.678",
+		stepVideo:   "https://youtu.be/...",
+		stepComment: "optional",
+		stepDate:    "YYYY-MM-DD",
+	}[step]
+	w.input.CursorEnd()
+	return w.input.Focus()
+}
+
+// field is the draft field a text step edits
+func (w *submitWizard) field() *string {
+	switch w.draft.Step {
+	case stepGame:
+		return &w.draft.Game
+	case stepTime:
+		return &w.draft.Time
+	case stepVideo:
+		return &w.draft.Video
+	case stepComment:
+		return &w.draft.Comment
+	case stepDate:
+		return &w.draft.Date
+	}
+	return new(string)
+}
+
+// accept checks and stores the current step's answer. A game step
+// answer needs its data fetched before moving on, which the caller does.
+func (w *submitWizard) accept() error {
+	if w.choosing() {
+		if w.selected >= len(w.options) {
+			return fmt.Errorf("nothing to choose from")
+		}
+		o := w.options[w.selected]
+		switch w.draft.Step {
+		case stepCategory:
+			if o.ID != w.draft.CategoryID {
+				w.draft.Values = nil
+			}
+			w.draft.CategoryID, w.draft.Category = o.ID, o.Name
+		case stepValues:
+			if w.draft.Values == nil {
+				w.draft.Values = make(map[string]string)
+			}
+			w.draft.Values[w.variable.ID] = o.ID
+		case stepPlatform:
+			w.draft.PlatformID, w.draft.Platform = o.ID, o.Name
+		}
+		return nil
+	}
+
+	value := strings.TrimSpace(w.input.Value())
+	switch w.draft.Step {
+	case stepGame:
+		if value == "" {
+			return fmt.Errorf("enter a game")
+		}
+		if value != w.draft.Game {
+			// Nothing chosen for the old game applies to the new one
+			w.draft.CategoryID, w.draft.Category = "", ""
+			w.draft.PlatformID, w.draft.Platform = "", ""
+			w.draft.Values = nil
+		}
+	case stepTime:
+		d, err := parseRunTime(value)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return fmt.Errorf("the time must be more than zero")
+		}
+	case stepVideo:
+		if value == "" && w.game.Game.RequireVideo {
+			return fmt.Errorf("%s requires a video", w.game.Game.Name)
+		}
+	case stepDate:
+		date, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+		if err != nil {
+			return fmt.Errorf("use YYYY-MM-DD")
+		}
+		if date.After(time.Now()) {
+			return fmt.Errorf("the date is in the future")
+		}
+	}
+	*w.field() = value
+	return nil
+}
+
+// suggest answers the category and its subcategories from a suggestion,
+// moving on to whatever it leaves
+func (w *submitWizard) suggest(sg submitSuggestion) tea.Cmd {
+	w.draft.CategoryID, w.draft.Category = sg.CategoryID, sg.Category
+	w.draft.Values = maps.Clone(sg.Values)
+	return w.enter(stepValues)
+}
+
+// back returns to the previous question, undoing a subcategory answer so
+// it's asked again
+func (w *submitWizard) back() tea.Cmd {
+	switch w.draft.Step {
+	case stepValues, stepPlatform:
+		vars := subcategoryVars(w.game, w.draft.CategoryID)
+		for i := len(vars) - 1; i >= 0; i-- {
+			if w.draft.Values[vars[i].ID] != "" {
+				delete(w.draft.Values, vars[i].ID)
+				return w.enter(stepValues)
+			}
+		}
+		return w.enter(stepCategory)
+	}
+	return w.enter(w.draft.Step - 1)
+}
+
+// submissionsModel is the Submissions tab: saved drafts, the wizard and
+// my runs waiting for verification
+type submissionsModel struct {
+	client *Client
+	games  *GameCache
+	times  TimeFormat
+	twitch *TwitchClient // for checking Twitch VODs without yt-dlp
+
+	drafts   []Draft
+	selected int // 0 is "new submission", then the drafts, then pending runs
+	wizard   *submitWizard
+	loading  bool
+	err      error
+
+	obs     OBSConfig
+	waiting string // LiveSplit or OBS, while waiting for a run to end to start a draft from
+
+	pending        []pendingRun
+	pendingLoaded  bool
+	pendingLoading bool
+	pendingErr     error
+	editor         *pendingEditor
+
+	requests []GameRequest // games I've asked the site to add
+}
+
+func newSubmissionsModel(client *Client, cfg Config) submissionsModel {
+	s := submissionsModel{client: client, times: cfg.TimeFormat, twitch: NewTwitchClient(cfg.Twitch), obs: cfg.OBS}
+	s.games, s.err = NewGameCache(client)
+	if s.err == nil {
+		s.drafts, s.err = loadDrafts()
+	}
+	return s
+}
+
+func (s submissionsModel) activate() (submissionsModel, tea.Cmd) {
+	if s.pendingLoaded || s.pendingLoading {
+		return s, nil
+	}
+	s.pendingLoading = true
+	return s, loadPendingCmd(s.client, s.games)
+}
+
+// capturing reports whether the wizard or an edit prompt wants every key
+func (s submissionsModel) capturing() bool {
+	return s.wizard != nil || s.editor != nil
+}
+
+// selectedPending is the pending run under the cursor, if it's on one
+func (s submissionsModel) selectedPending() (pendingRun, bool) {
+	i := s.selected - 1 - len(s.drafts)
+	if i < 0 || i >= len(s.pending) {
+		return pendingRun{}, false
+	}
+	return s.pending[i], true
+}
+
+func (s submissionsModel) loadGameCmd(game string) tea.Cmd {
+	games := s.games
+	return func() tea.Msg {
+		data, err := games.Get(game)
+		return submitGameMsg{data: data, err: err}
+	}
+}
+
+func submitCmd(client *Client, d Draft) tea.Cmd {
+	return func() tea.Msg {
+		id, err := client.SubmitRun(d)
+		return submitResultMsg{draft: d, runID: id, err: err}
+	}
+}
+
+// start opens the wizard on a draft, fetching the game first when the
+// draft is past the game step
+func (s submissionsModel) start(d Draft) (submissionsModel, tea.Cmd) {
+	input := textinput.New()
+	input.CharLimit = 500
+	input.Width = 60
+	s.wizard = &submitWizard{draft: d, input: input}
+	s.err = nil
+	if d.Step > stepGame && d.Game != "" && s.games != nil {
+		s.loading = true
+		return s, s.loadGameCmd(d.Game)
+	}
+	return s, s.wizard.enter(stepGame)
+}
+
+// offer opens the wizard on a run timed elsewhere, or keeps it as a draft
+// rather than take over a submission in progress
+func (s submissionsModel) offer(d Draft) (submissionsModel, tea.Cmd) {
+	switch {
+	case s.games == nil:
+		return s, nil
+	case s.wizard != nil:
+		s.drafts, s.err = storeDraft(s.drafts, d)
+		return s, statusCmd("Saved the run as a draft: %s", d.describe())
+	}
+	return s.start(d)
+}
+
+// persist saves the wizard's draft, including a half typed answer
+func (s *submissionsModel) persist() {
+	w := s.wizard
+	if w == nil {
+		return
+	}
+	if !w.choosing() && w.draft.Step != stepReview {
+		*w.field() = strings.TrimSpace(w.input.Value())
+	}
+	if w.draft.empty() {
+		return
+	}
+	var err error
+	if s.drafts, err = storeDraft(s.drafts, w.draft); err != nil {
+		w.err = err
+	}
+}
+
+func (s submissionsModel) update(msg tea.Msg) (submissionsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case submitGameMsg:
+		s.loading = false
+		if s.wizard == nil {
+			return s, nil
+		}
+		w := s.wizard
+		if msg.err != nil {
+			cmd := w.enter(stepGame)
+			w.err = msg.err
+			return s, cmd
+		}
+		w.game = msg.data
+		w.suggestions = nil
+		w.draft.GameID, w.draft.GameName = msg.data.Game.ID, msg.data.Game.Name
+		// A resumed draft picks up where it was left
+		step := w.draft.Step
+		if step <= stepGame {
+			step = stepCategory
+		}
+		cmd := w.enter(step)
+		s.persist()
+		return s, tea.Batch(cmd, suggestCmd(s.client, msg.data, w.draft))
+
+	case submitSuggestionsMsg:
+		// Unless the wizard has moved on to another draft or game
+		if w := s.wizard; w != nil && w.draft.ID == msg.draftID && w.draft.GameID == msg.gameID {
+			w.suggestions = msg.suggestions
+		}
+		return s, nil
+
+	case videoCheckMsg:
+		// Unless the video was changed since
+		if w := s.wizard; w != nil && w.draft.ID == msg.draftID && w.draft.Video == msg.video {
+			w.checkingVideo, w.videoChecked = false, true
+			w.videoIssues = msg.issues
+		}
+		return s, nil
+
+	case liveSplitRunMsg:
+		s.waiting = ""
+		if msg.err != nil {
+			s.err = msg.err
+			return s, nil
+		}
+		return s.offer(Draft{ID: fmt.Sprint(time.Now().UnixNano()), Time: formatRunTime(msg.run.Final), Comment: msg.run.comment()})
+
+	case submitResultMsg:
+		s.loading = false
+		if msg.err != nil {
+			if s.wizard != nil {
+				s.wizard.err = msg.err
+			}
+			return s, nil
+		}
+		s.wizard = nil
+		s.drafts, s.err = deleteDraft(s.drafts, msg.draft.ID)
+		s.selected = 0
+		// The new run is pending now
+		s.pendingLoading = true
+		return s, tea.Batch(
+			statusCmd("Submitted %s, it's waiting for verification", msg.draft.describe()),
+			loadPendingCmd(s.client, s.games),
+		)
+
+	case pendingMsg:
+		s.pendingLoading = false
+		s.pendingLoaded = true
+		s.pending = msg.runs
+		s.pendingErr = msg.err
+		s.selected = min(s.selected, len(s.drafts)+len(s.pending))
+
+	case withdrawRunMsg:
+		return s, deleteRunCmd(s.client, msg.id)
+
+	case deleteDraftMsg:
+		s.drafts, s.err = deleteDraft(s.drafts, msg.id)
+		s.selected = min(s.selected, len(s.drafts)+len(s.pending))
+		return s, nil
+
+	case pendingEditMsg:
+		if msg.err != nil {
+			return s, statusCmd("%v", msg.err)
+		}
+		s.pendingLoading = true
+		status := "Run updated"
+		if msg.deleted {
+			status = "Run withdrawn"
+		}
+		return s, tea.Batch(statusCmd(status), loadPendingCmd(s.client, s.games))
+
+	case tea.KeyMsg:
+		if s.loading {
+			return s, nil
+		}
+		if s.wizard != nil {
+			return s.updateWizard(msg)
+		}
+		if s.editor != nil {
+			var cmd tea.Cmd
+			s.editor, cmd = s.editor.update(msg, s.client)
+			return s, cmd
+		}
+		if p, ok := s.selectedPending(); ok {
+			switch msg.String() {
+			case "enter":
+				openBrowser(p.run.Weblink)
+				return s, nil
+			case "c":
+				s.editor = newPendingEditor(p, editComment)
+				return s, s.editor.input.Focus()
+			case "u":
+				s.editor = newPendingEditor(p, editVideo)
+				return s, s.editor.input.Focus()
+			case "W":
+				return s, confirmCmd(withdrawRunMsg{id: p.raw.ID}, "Withdraw your %s › %s run? It can't be undone.", p.game, p.category)
+			}
+		}
+		switch msg.String() {
+		case "up", "k":
+			if s.selected > 0 {
+				s.selected--
+			}
+		case "down", "j":
+			if s.selected < len(s.drafts)+len(s.pending) {
+				s.selected++
+			}
+		case "enter", "n":
+			if s.games == nil {
+				return s, nil
+			}
+			if msg.String() == "enter" && s.selected > 0 {
+				return s.start(s.drafts[s.selected-1])
+			}
+			return s.start(Draft{ID: fmt.Sprint(time.Now().UnixNano())})
+		case "l":
+			if s.games != nil && s.waiting == "" {
+				s.waiting = "LiveSplit"
+				s.err = nil
+				return s, watchLiveSplitCmd(defaultLiveSplitAddr)
+			}
+		case "o":
+			if s.games != nil && s.waiting == "" {
+				s.waiting = "OBS"
+				s.err = nil
+				return s, watchOBSCmd(s.obs)
+			}
+		case "d":
+			if s.selected > 0 && s.selected <= len(s.drafts) {
+				d := s.drafts[s.selected-1]
+				return s, confirmCmd(deleteDraftMsg{id: d.ID}, "Delete the draft %s?", d.describe())
+			}
+		case "r":
+			if !s.pendingLoading {
+				s.pendingLoading = true
+				return s, loadPendingCmd(s.client, s.games)
+			}
+		}
+		return s, nil
+	}
+
+	// Cursor blinks
+	var cmd tea.Cmd
+	switch {
+	case s.wizard != nil:
+		s.wizard.input, cmd = s.wizard.input.Update(msg)
+	case s.editor != nil:
+		s.editor.input, cmd = s.editor.input.Update(msg)
+	}
+	return s, cmd
+}
+
+func (s submissionsModel) updateWizard(msg tea.KeyMsg) (submissionsModel, tea.Cmd) {
+	w := s.wizard
+	switch msg.String() {
+	case "ctrl+s":
+		// Keep it for later
+		s.persist()
+		s.wizard = nil
+		return s, statusCmd("Draft saved")
+	case "esc":
+		if w.draft.Step == stepGame {
+			s.persist()
+			s.wizard = nil
+			return s, nil
+		}
+		cmd := w.back()
+		s.persist()
+		return s, cmd
+	case "enter":
+		if w.draft.Step == stepReview {
+			s.loading = true
+			return s, submitCmd(s.client, w.draft)
+		}
+		if err := w.accept(); err != nil {
+			w.err = err
+			return s, nil
+		}
+		if w.draft.Step == stepGame {
+			s.loading = true
+			w.draft.Step = stepCategory
+			return s, s.loadGameCmd(w.draft.Game)
+		}
+		var check tea.Cmd
+		if w.draft.Step == stepVideo {
+			w.checkingVideo, w.videoChecked, w.videoIssues = w.draft.Video != "", false, nil
+			if w.checkingVideo {
+				check = checkVideoCmd(s.twitch, w.draft)
+			}
+		}
+		next := w.draft.Step + 1
+		if w.draft.Step == stepValues {
+			// Until every subcategory has an answer
+			next = stepValues
+		}
+		cmd := w.enter(next)
+		s.persist()
+		return s, tea.Batch(cmd, check)
+	}
+
+	if w.draft.Step == stepCategory {
+		if key := msg.String(); len(key) == 1 && key >= "1" && int(key[0]-'1') < len(w.suggestions) {
+			cmd := w.suggest(w.suggestions[key[0]-'1'])
+			s.persist()
+			return s, cmd
+		}
+	}
+
+	if w.choosing() {
+		switch msg.String() {
+		case "up", "k":
+			if w.selected > 0 {
+				w.selected--
+			}
+		case "down", "j":
+			if w.selected < len(w.options)-1 {
+				w.selected++
+			}
+		}
+		return s, nil
+	}
+
+	var cmd tea.Cmd
+	w.input, cmd = w.input.Update(msg)
+	return s, cmd
+}
+
+func (s submissionsModel) view() string {
+	var b strings.Builder
+	if s.err != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n\n", s.err))
+	}
+	if s.wizard != nil {
+		b.WriteString(s.wizardView())
+		return b.String()
+	}
+
+	switch s.waiting {
+	case "LiveSplit":
+		b.WriteString(urlStyle.Render("Waiting for the LiveSplit run to end...") + "\n\n")
+	case "OBS":
+		b.WriteString(urlStyle.Render("Waiting for OBS to stop recording...") + "\n\n")
+	}
+	entries := []string{"New submission"}
+	for _, d := range s.drafts {
+		entries = append(entries, "Resume draft: "+d.describe()+
+			urlStyle.Render(fmt.Sprintf("  at %s, saved %s", stepNames[d.Step], d.Updated.Local().Format("Jan 2 15:04"))))
+	}
+	for i, e := range entries {
+		style := unselectedItemStyle
+		if i == s.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(e))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + titleStyle.Render(fmt.Sprintf("Waiting for verification (%d)", len(s.pending))) + "\n")
+	switch {
+	case s.pendingLoading:
+		b.WriteString("Loading...\n")
+	case s.pendingErr != nil:
+		b.WriteString(fmt.Sprintf("Error: %v\n", s.pendingErr))
+	case len(s.pending) == 0:
+		b.WriteString(urlStyle.Render("Nothing pending") + "\n")
+	}
+	now := time.Now()
+	for i, p := range s.pending {
+		var item strings.Builder
+		age := now.Sub(p.run.Submitted)
+		item.WriteString(fmt.Sprintf("[%s] %s › %s\n", ageStyle(age).Render(formatAge(age)), p.game, p.category))
+		item.WriteString(s.times.render(p.run.Time, p.millis))
+		if p.run.Video == "" {
+			item.WriteString(urlStyle.Render("  no video"))
+		}
+		if p.raw.Comment != "" {
+			item.WriteString("\n" + urlStyle.Render(truncate(strings.SplitN(p.raw.Comment, "\n", 2)[0], 70)))
+		}
+		style := unselectedItemStyle
+		if i+1+len(s.drafts) == s.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(item.String()))
+		b.WriteString("\n")
+	}
+
+	if len(s.requests) > 0 {
+		b.WriteString("\n" + gameRequestsView(s.requests))
+	}
+
+	if s.editor != nil {
+		b.WriteString("\n" + s.editor.view())
+	}
+	return b.String()
+}
+
+func (s submissionsModel) wizardView() string {
+	w := s.wizard
+	var b strings.Builder
+	b.WriteString(urlStyle.Render(fmt.Sprintf("Step %d of %d", w.draft.Step+1, len(stepNames))))
+	b.WriteString("\n")
+
+	title := stepNames[w.draft.Step]
+	if w.draft.Step == stepValues {
+		title = w.variable.Name
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	switch {
+	case s.loading && w.draft.Step == stepReview:
+		b.WriteString("Submitting...")
+	case s.loading:
+		b.WriteString("Loading " + w.draft.Game + "...")
+	case w.draft.Step == stepReview:
+		d := w.draft
+		rows := [][2]string{{"Game", d.GameName}, {"Category", d.Category}}
+		for _, v := range subcategoryVars(w.game, d.CategoryID) {
+			for _, val := range w.game.Values {
+				if val.ID == d.Values[v.ID] {
+					rows = append(rows, [2]string{v.Name, val.Name})
+				}
+			}
+		}
+		rows = append(rows,
+			[2]string{"Platform", d.Platform},
+			[2]string{"Time", d.Time},
+			[2]string{"Video", d.Video},
+			[2]string{"Comment", d.Comment},
+			[2]string{"Date", d.Date},
+		)
+		for _, r := range rows {
+			b.WriteString(fmt.Sprintf("%-12s %s\n", r[0], r[1]))
+		}
+		switch {
+		case w.checkingVideo:
+			b.WriteString("\n" + urlStyle.Render("Checking the video..."))
+		case w.videoChecked && len(w.videoIssues) == 0:
+			b.WriteString("\n" + okStyle.Render("The video checks out"))
+		}
+		for _, issue := range w.videoIssues {
+			style := urlStyle
+			if issue.Fatal {
+				style = failStyle
+			}
+			b.WriteString("\n" + style.Render(issue.String()))
+		}
+	case w.choosing():
+		if w.draft.Step == stepCategory && len(w.suggestions) > 0 {
+			for i, sg := range w.suggestions {
+				b.WriteString(fmt.Sprintf("%d %s", i+1, sg.Label) + urlStyle.Render("  from "+sg.Reason) + "\n")
+			}
+			b.WriteString("\n")
+		}
+		for i, o := range w.options {
+			cursor := "  "
+			if i == w.selected {
+				cursor = "> "
+			}
+			b.WriteString(cursor + o.Name + "\n")
+		}
+		if len(w.options) == 0 {
+			b.WriteString(urlStyle.Render("Nothing to choose from"))
+		}
+	default:
+		b.WriteString(w.input.View())
+	}
+
+	if w.err != nil {
+		b.WriteString("\n\n" + failStyle.Render(w.err.Error()))
+	}
+	return b.String()
+}
+
+func (s submissionsModel) help() string {
+	if _, ok := s.selectedPending(); ok && s.wizard == nil && s.editor == nil {
+		return "j/k navigate • enter open • c comment • u video • W withdraw • r refresh"
+	}
+	switch {
+	case s.editor != nil:
+		return s.editor.help()
+	case s.wizard == nil:
+		return "j/k navigate • enter open • n new • l/o from LiveSplit/OBS • d delete draft • r refresh"
+	case s.wizard.draft.Step == stepReview:
+		return "enter submit • esc back • ctrl+s save draft"
+	case s.wizard.draft.Step == stepCategory && len(s.wizard.suggestions) > 0:
+		return fmt.Sprintf("1-%d use a suggestion • j/k choose • enter next • esc back • ctrl+s save draft", len(s.wizard.suggestions))
+	case s.wizard.choosing():
+		return "j/k choose • enter next • esc back • ctrl+s save draft"
+	}
+	return "enter next • esc back • ctrl+s save draft"
+}
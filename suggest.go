@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Suggestions offered on the category step, one key each
+const maxSubmitSuggestions = 3
+
+// submitSuggestion is a category and subcategory answers the run is likely
+// for, from the video's title or the boards I usually submit to
+type submitSuggestion struct {
+	CategoryID string
+	Category   string
+	Values     map[string]string // variable ID -> value ID
+	Label      string            // "Any% › NTSC"
+	Reason     string
+}
+
+// key tells suggestions for the same board apart
+func (s submitSuggestion) key() string {
+	ids := slices.Sorted(maps.Keys(s.Values))
+	for i, id := range ids {
+		ids[i] = id + "=" + s.Values[id]
+	}
+	return s.CategoryID + " " + strings.Join(ids, " ")
+}
+
+// GetUserGameRuns returns every run a user has submitted for a game,
+// whatever became of it, oldest first
+func (c *Client) GetUserGameRuns(userID, gameID string) ([]v1Run, error) {
+	q := url.Values{
+		"user":      {userID},
+		"game":      {gameID},
+		"orderby":   {"submitted"},
+		"direction": {"asc"},
+	}
+	runs, err := c.listRuns(q, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching your runs: %w", err)
+	}
+	return runs, nil
+}
+
+type submitSuggestionsMsg struct {
+	draftID     string
+	gameID      string
+	suggestions []submitSuggestion
+}
+
+// suggestCmd works out suggestions for a draft whose game is loaded. Both
+// sources are a bonus, so failing to read either leaves the other.
+func suggestCmd(client *Client, data *GameData, d Draft) tea.Cmd {
+	return func() tea.Msg {
+		title := d.VideoTitle
+		if title == "" && d.Video != "" {
+			if info, err := fetchVideoInfo(d.Video); err == nil {
+				title = info.Title
+			}
+		}
+		var runs []v1Run
+		session, err := client.GetSession()
+		if err == nil && session.SignedIn && session.User != nil {
+			runs, err = client.GetUserGameRuns(session.User.ID, data.Game.ID)
+		}
+		if err != nil {
+			log.Printf("suggestions: %v", err)
+		}
+		return submitSuggestionsMsg{draftID: d.ID, gameID: data.Game.ID, suggestions: submitSuggestions(data, runs, title)}
+	}
+}
+
+// submitSuggestions puts the board the title names first, then the boards
+// of my earlier runs, the most submitted to first
+func submitSuggestions(data *GameData, runs []v1Run, title string) []submitSuggestion {
+	type board struct {
+		s     submitSuggestion
+		count int
+		last  int
+	}
+	boards := make(map[string]*board)
+	for i, r := range runs {
+		c, ok := submitCategory(data, r.Category)
+		if r.Level != "" || !ok {
+			continue
+		}
+		values := make(map[string]string)
+		for _, v := range subcategoryVars(data, c.ID) {
+			if id := r.Values[v.ID]; id != "" {
+				values[v.ID] = id
+			}
+		}
+		s := submitSuggestion{CategoryID: c.ID, Category: c.Name, Values: values}
+		b := boards[s.key()]
+		if b == nil {
+			b = &board{s: s}
+			boards[s.key()] = b
+		}
+		b.count++
+		b.last = i
+	}
+	history := slices.Collect(maps.Values(boards))
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].count != history[j].count {
+			return history[i].count > history[j].count
+		}
+		return history[i].last > history[j].last
+	})
+
+	var out []submitSuggestion
+	if c, ok := guessCategory(data, title); ok {
+		s := submitSuggestion{CategoryID: c.ID, Category: c.Name, Values: titleValues(data, c.ID, title), Reason: "the video title"}
+		// What the title leaves out, the way I usually run it
+		for _, b := range history {
+			if b.s.CategoryID != c.ID {
+				continue
+			}
+			for id, value := range b.s.Values {
+				if s.Values[id] == "" {
+					s.Values[id] = value
+				}
+			}
+			break
+		}
+		out = append(out, s)
+	}
+	for _, b := range history {
+		s := b.s
+		s.Reason = "1 of your runs"
+		if b.count > 1 {
+			s.Reason = fmt.Sprintf("%d of your runs", b.count)
+		}
+		if len(out) > 0 && out[0].key() == s.key() {
+			out[0].Reason += ", " + s.Reason
+			continue
+		}
+		out = append(out, s)
+	}
+	if len(out) > maxSubmitSuggestions {
+		out = out[:maxSubmitSuggestions]
+	}
+	for i := range out {
+		out[i].Label = suggestionLabel(data, out[i])
+	}
+	return out
+}
+
+// submitCategory is a category the wizard still offers
+func submitCategory(data *GameData, id string) (Category, bool) {
+	for _, c := range data.Categories {
+		if c.ID == id && !c.IsPerLevel && !c.Archived {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// titleValues are the subcategory values the title names
+func titleValues(data *GameData, categoryID, title string) map[string]string {
+	words := titleWords(title)
+	values := make(map[string]string)
+	for _, v := range subcategoryVars(data, categoryID) {
+		best := ""
+		for _, val := range data.Values {
+			n := titleWords(val.Name)
+			if val.VariableID == v.ID && strings.Contains(words, n) && len(n) > len(best) {
+				values[v.ID], best = val.ID, n
+			}
+		}
+	}
+	return values
+}
+
+func suggestionLabel(data *GameData, s submitSuggestion) string {
+	parts := []string{s.Category}
+	for _, v := range subcategoryVars(data, s.CategoryID) {
+		for _, val := range data.Values {
+			if val.ID == s.Values[v.ID] {
+				parts = append(parts, val.Name)
+			}
+		}
+	}
+	return strings.Join(parts, " › ")
+}
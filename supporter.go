@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Supporters of the site pick their name's colors, a gradient across the
+// name and optionally animated; everyone else has the site's default
+// color. The v1 API gives both as hex colors in a user's name-style.
+
+const supporterMark = "✦"
+
+var supporterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(defaultAccent))
+
+// v1NameStyle is the name-style of a v1 user, with a light and a dark
+// variant of each color
+type v1NameStyle struct {
+	Style     string   `json:"style"` // solid or gradient
+	Color     *v1Color `json:"color"`
+	ColorFrom *v1Color `json:"color-from"`
+	ColorTo   *v1Color `json:"color-to"`
+}
+
+type v1Color struct {
+	Light string `json:"light"`
+	Dark  string `json:"dark"`
+}
+
+// nameColors is how a user's name is colored: one color, or a gradient
+// from From to To
+type nameColors struct {
+	From string
+	To   string // empty for one color
+}
+
+// colors picks the dark page variants, which read on a dark terminal
+func (s *v1NameStyle) colors() nameColors {
+	switch {
+	case s == nil:
+		return nameColors{}
+	case s.Style == "gradient" && s.ColorFrom != nil && s.ColorTo != nil:
+		return nameColors{From: s.ColorFrom.Dark, To: s.ColorTo.Dark}
+	case s.Color != nil:
+		return nameColors{From: s.Color.Dark}
+	}
+	return nameColors{}
+}
+
+// gradient reports whether it's a gradient, which only supporters get
+func (c nameColors) gradient() bool {
+	return c.To != "" && c.To != c.From
+}
+
+// render colors a name, blending letter by letter for a gradient
+func (c nameColors) render(name string) string {
+	if _, _, _, ok := parseHexColor(c.From); !ok {
+		return name
+	}
+	if !c.gradient() {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c.From)).Render(name)
+	}
+	r1, g1, b1, _ := parseHexColor(c.From)
+	r2, g2, b2, ok := parseHexColor(c.To)
+	if !ok {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c.From)).Render(name)
+	}
+	letters := []rune(name)
+	var b strings.Builder
+	for i, l := range letters {
+		t := 0.0
+		if len(letters) > 1 {
+			t = float64(i) / float64(len(letters)-1)
+		}
+		color := "#" + hexByte(r1+(r2-r1)*t) + hexByte(g1+(g2-g1)*t) + hexByte(b1+(b2-b1)*t)
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(string(l)))
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The app's own accent, used outside game screens or when game themes are
+// off
+const defaultAccent = "#FFD700"
+
+// GameTheme is the color scheme a game's page uses on the site
+type GameTheme struct {
+	PrimaryColor    string `json:"primaryColor"`
+	PanelColor      string `json:"panelColor"`
+	BackgroundColor string `json:"backgroundColor"`
+}
+
+// parseHexColor reads "#rrggbb" or "#rgb"
+func parseHexColor(s string) (r, g, b float64, ok bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return float64(v>>16&0xff) / 255, float64(v>>8&0xff) / 255, float64(v&0xff) / 255, true
+}
+
+// luminance is how bright a color looks, 0 for black to 1 for white
+func luminance(r, g, b float64) float64 {
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// accent picks the theme color to tint the app with. Themes often use a
+// dark primary color made for a light page, so dark colors are mixed with
+// white until they read on a dark terminal.
+func (t *GameTheme) accent() (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	r, g, b, ok := parseHexColor(t.PrimaryColor)
+	if !ok {
+		return "", false
+	}
+	for luminance(r, g, b) < 0.35 {
+		r, g, b = r+(1-r)*0.2, g+(1-g)*0.2, b+(1-b)*0.2
+	}
+	return "#" + hexByte(r) + hexByte(g) + hexByte(b), true
+}
+
+func hexByte(v float64) string {
+	s := strconv.FormatUint(uint64(v*255+0.5), 16)
+	if len(s) == 1 {
+		s = "0" + s
+	}
+	return s
+}
+
+// setAccent re-tints the styles that use the accent color
+func setAccent(color string) {
+	c := lipgloss.Color(color)
+	// Black text on light accents, white on the rest
+	text := lipgloss.Color("#000000")
+	if r, g, b, ok := parseHexColor(color); ok && luminance(r, g, b) < 0.5 {
+		text = lipgloss.Color("#FFFFFF")
+	}
+	titleStyle = titleStyle.Background(c).Foreground(text)
+	unreadCountStyle = unreadCountStyle.Foreground(c).BorderForeground(c)
+	selectedItemStyle = selectedItemStyle.BorderLeftForeground(c)
+	unreadDotStyle = unreadDotStyle.Foreground(c)
+	activeTabStyle = activeTabStyle.Foreground(c)
+}
+
+// syncTheme tints the app for the game on screen when game themes are on
+func (m model) syncTheme() {
+	if !m.gameThemes {
+		return
+	}
+	color := defaultAccent
+	if m.screen == screenBoards && m.boards.level != boardsGames && m.boards.game != nil {
+		if accent, ok := m.boards.game.Theme.accent(); ok {
+			color = accent
+		}
+	}
+	setAccent(color)
+}
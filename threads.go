@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Comments seen of each subscribed thread, by thread ID, for the unread
+// counts
+const threadsReadFile = "threads_read.json"
+
+// ThreadSubscription is a forum thread I'm subscribed to
+type ThreadSubscription struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	ForumName       string `json:"forumName"`
+	ForumURL        string `json:"forumUrl"`
+	CommentCount    int    `json:"commentsCount"`
+	LastCommentDate int64  `json:"lastCommentDate"` // Unix seconds
+}
+
+func (t ThreadSubscription) url() string {
+	return "https://www.speedrun.com/forums/" + t.ForumURL + "/" + t.ID
+}
+
+// GetThreadSubscriptions lists the threads the signed in user is
+// subscribed to, most recently replied to first
+func (c *Client) GetThreadSubscriptions() ([]ThreadSubscription, error) {
+	var result struct {
+		Threads []ThreadSubscription `json:"threadList"`
+	}
+	if err := c.post("GetThreadSubscriptionList", struct{}{}, &result); err != nil {
+		return nil, fmt.Errorf("fetching thread subscriptions: %w", err)
+	}
+	return result.Threads, nil
+}
+
+// SetThreadSubscription subscribes to a thread's replies, or stops
+func (c *Client) SetThreadSubscription(threadID string, subscribed bool) error {
+	body := struct {
+		ThreadID   string `json:"threadId"`
+		Subscribed bool   `json:"subscribed"`
+	}{
+		ThreadID:   threadID,
+		Subscribed: subscribed,
+	}
+	if err := c.write("PutThreadSubscription", body, nil); err != nil {
+		return fmt.Errorf("updating thread subscription: %w", err)
+	}
+	return nil
+}
+
+type threadsMsg struct {
+	threads []ThreadSubscription
+	err     error
+}
+
+type threadSubscribedMsg struct {
+	id         string
+	subscribed bool
+	err        error
+}
+
+func loadThreadsCmd(client *Client) tea.Cmd {
+	return func() tea.Msg {
+		threads, err := client.GetThreadSubscriptions()
+		return threadsMsg{threads: threads, err: err}
+	}
+}
+
+func subscribeThreadCmd(client *Client, id string, subscribed bool) tea.Cmd {
+	return func() tea.Msg {
+		return threadSubscribedMsg{id: id, subscribed: subscribed, err: client.SetThreadSubscription(id, subscribed)}
+	}
+}
+
+// threadsModel is the Threads tab: my subscribed forum threads, with how
+// many replies came in since I last opened each one
+type threadsModel struct {
+	client   *Client
+	threads  []ThreadSubscription
+	seen     map[string]int // comments when last opened, by thread ID
+	selected int
+	loading  bool
+	loaded   bool
+	err      error
+}
+
+func newThreadsModel(client *Client) threadsModel {
+	t := threadsModel{client: client, seen: make(map[string]int)}
+	if err := loadState(threadsReadFile, &t.seen); err != nil {
+		log.Printf("threads: %v", err)
+	}
+	// The first load starts with the app, so a thread opened from a
+	// notification knows whether it's subscribed to
+	t.loading = true
+	return t
+}
+
+func (t threadsModel) loadCmd() tea.Cmd {
+	return loadThreadsCmd(t.client)
+}
+
+func (t threadsModel) activate() (threadsModel, tea.Cmd) {
+	if t.loading {
+		return t, nil
+	}
+	t.loading = true
+	return t, t.loadCmd()
+}
+
+// subscribed reports whether a thread is in the list, once it's loaded
+func (t threadsModel) subscribed(id string) (subscribed, known bool) {
+	for _, th := range t.threads {
+		if th.ID == id {
+			return true, true
+		}
+	}
+	return false, t.loaded
+}
+
+// unread is how many replies a thread has that I haven't opened
+func (t threadsModel) unread(th ThreadSubscription) int {
+	return max(th.CommentCount-t.seen[th.ID], 0)
+}
+
+func (t threadsModel) totalUnread() int {
+	n := 0
+	for _, th := range t.threads {
+		n += t.unread(th)
+	}
+	return n
+}
+
+// markRead records a thread's replies as seen, on opening it
+func (t threadsModel) markRead(id string) threadsModel {
+	for _, th := range t.threads {
+		if th.ID == id && t.seen[th.ID] != th.CommentCount {
+			t.seen[th.ID] = th.CommentCount
+			if err := saveState(threadsReadFile, t.seen); err != nil {
+				log.Printf("threads: %v", err)
+			}
+		}
+	}
+	return t
+}
+
+func (t threadsModel) selectedThread() (ThreadSubscription, bool) {
+	if t.selected >= len(t.threads) {
+		return ThreadSubscription{}, false
+	}
+	return t.threads[t.selected], true
+}
+
+func (t threadsModel) update(msg tea.Msg) (threadsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case threadsMsg:
+		t.loading = false
+		t.err = msg.err
+		if msg.err != nil {
+			return t, nil
+		}
+		t.loaded = true
+		t.threads = msg.threads
+		t.selected = min(t.selected, max(len(t.threads)-1, 0))
+		// A thread new to the list starts out read, so subscribing to an
+		// old thread doesn't bring all of its replies
+		changed := false
+		for _, th := range t.threads {
+			if _, ok := t.seen[th.ID]; !ok {
+				t.seen[th.ID] = th.CommentCount
+				changed = true
+			}
+		}
+		if changed {
+			if err := saveState(threadsReadFile, t.seen); err != nil {
+				log.Printf("threads: %v", err)
+			}
+		}
+		return t, nil
+
+	case threadSubscribedMsg:
+		if msg.err != nil {
+			return t, statusCmd("%v", msg.err)
+		}
+		status := "Subscribed to the thread"
+		if !msg.subscribed {
+			status = "Unsubscribed from the thread"
+		}
+		t.loading = true
+		return t, tea.Batch(statusCmd(status), t.loadCmd())
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if t.selected > 0 {
+				t.selected--
+			}
+		case "down", "j":
+			if t.selected < len(t.threads)-1 {
+				t.selected++
+			}
+		case "u":
+			if th, ok := t.selectedThread(); ok {
+				return t, subscribeThreadCmd(t.client, th.ID, false)
+			}
+		case "r":
+			return t.activate()
+		}
+	}
+	return t, nil
+}
+
+func (t threadsModel) view() string {
+	switch {
+	case t.loading && !t.loaded:
+		return "Loading threads..."
+	case t.err != nil:
+		return fmt.Sprintf("Error: %v", t.err)
+	case len(t.threads) == 0:
+		return urlStyle.Render("Not subscribed to any threads. s on a thread subscribes to it.")
+	}
+
+	var b strings.Builder
+	now := time.Now()
+	for i, th := range t.threads {
+		var item strings.Builder
+		dot := "  "
+		if t.unread(th) > 0 {
+			dot = unreadDotStyle.Render("●") + " "
+		}
+		item.WriteString(dot + th.Name)
+		if n := t.unread(th); n > 0 {
+			item.WriteString(fmt.Sprintf("  %d new", n))
+		}
+		item.WriteString("\n  " + urlStyle.Render(th.ForumName))
+		if th.LastCommentDate > 0 {
+			item.WriteString(urlStyle.Render(fmt.Sprintf(" • %d replies, last %s ago", th.CommentCount,
+				formatAge(now.Sub(time.Unix(th.LastCommentDate, 0))))))
+		}
+		style := unselectedItemStyle
+		if i == t.selected {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(item.String()) + "\n")
+	}
+	return b.String()
+}
+
+func (t threadsModel) title() string {
+	if n := t.totalUnread(); n > 0 {
+		return fmt.Sprintf("%d new replies", n)
+	}
+	return ""
+}
+
+func (t threadsModel) help() string {
+	return "j/k navigate • enter open • u unsubscribe • r refresh"
+}
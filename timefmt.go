@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatRunTime renders a run duration the way speedrun.com does, e.g.
+// 1:23:45.678, 23:45.678 or 45.678
+func formatRunTime(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	ms := int(d % time.Second / time.Millisecond)
+
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%s%d:%02d:%02d.%03d", sign, h, m, s, ms)
+	case m > 0:
+		return fmt.Sprintf("%s%d:%02d.%03d", sign, m, s, ms)
+	default:
+		return fmt.Sprintf("%s%d.%03d", sign, s, ms)
+	}
+}
+
+// TimeFormat controls how run times are shown on boards and in the queue.
+// The zero value is speedrun.com's own style.
+type TimeFormat struct {
+	// "clock" for 1:23:45.678 or "iso8601" for PT1H23M45.678S
+	Style string `json:"style,omitempty"`
+
+	// "game" follows each game's setting on speedrun.com, "always" or
+	// "never" override it. Defaults to "game".
+	Milliseconds string `json:"milliseconds,omitempty"`
+}
+
+func (f TimeFormat) validate() error {
+	switch f.Style {
+	case "", "clock", "iso8601":
+	default:
+		return fmt.Errorf("unknown time_format style %q, use clock or iso8601", f.Style)
+	}
+	switch f.Milliseconds {
+	case "", "game", "always", "never":
+	default:
+		return fmt.Errorf("unknown time_format milliseconds %q, use game, always or never", f.Milliseconds)
+	}
+	return nil
+}
+
+// showMillis decides for a game that does or doesn't time in milliseconds
+func (f TimeFormat) showMillis(gameMillis bool) bool {
+	switch f.Milliseconds {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return gameMillis
+}
+
+// toggleMillis flips what showMillis would answer for the game
+func (f TimeFormat) toggleMillis(gameMillis bool) TimeFormat {
+	if f.showMillis(gameMillis) {
+		f.Milliseconds = "never"
+	} else {
+		f.Milliseconds = "always"
+	}
+	return f
+}
+
+// render formats a run time for a game, per gameMillis from its settings
+func (f TimeFormat) render(d time.Duration, gameMillis bool) string {
+	millis := f.showMillis(gameMillis)
+	if f.Style == "iso8601" {
+		return formatISODuration(d, millis)
+	}
+	if millis {
+		return formatRunTime(d)
+	}
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%s%d:%02d:%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%s%d:%02d", sign, m, s)
+}
+
+// formatISODuration renders an ISO 8601 duration such as PT1H23M45.678S,
+// the form the v1 API uses for run times.
+func formatISODuration(d time.Duration, millis bool) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	b.WriteString("PT")
+
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	ms := int(d % time.Second / time.Millisecond)
+	if !millis {
+		ms = 0
+	}
+
+	if h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	switch {
+	case ms > 0:
+		fmt.Fprintf(&b, "%d.%03dS", s, ms)
+	case s > 0 || h == 0 && m == 0:
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
+// parseRunTime parses h:mm:ss.fff, m:ss.fff or s.fff with any number of
+// fractional digits, as produced by LiveSplit and typed by users.
+func parseRunTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 || s == "" {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	var d time.Duration
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if !last {
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("invalid time %q", s)
+			}
+			d = d*60 + time.Duration(n)
+			continue
+		}
+
+		secs, err := strconv.ParseFloat(part, 64)
+		if err != nil || secs < 0 {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		d = d*60*time.Second + time.Duration(secs*float64(time.Second)).Round(time.Millisecond)
+	}
+
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const timerStateFile = "timer.json"
+
+var (
+	timerClockStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
+			Bold(true).
+			Padding(1, 2)
+
+	timerAheadStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#00FF00"))
+
+	timerBehindStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FF5555"))
+
+	timerGoldStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
+			Bold(true)
+)
+
+// timerRecord is what we remember between sessions for one named timer
+type timerRecord struct {
+	PB   []time.Duration `json:"pb"`   // cumulative split times of the best run
+	Best []time.Duration `json:"best"` // best time ever for each segment
+}
+
+func (r timerRecord) pbFinal() time.Duration {
+	if len(r.PB) == 0 {
+		return 0
+	}
+	return r.PB[len(r.PB)-1]
+}
+
+type timerTickMsg time.Time
+
+func timerTick() tea.Cmd {
+	return tea.Tick(50*time.Millisecond, func(t time.Time) tea.Msg {
+		return timerTickMsg(t)
+	})
+}
+
+// timerModel is a minimal split timer: space starts and splits, enter ends
+// the run, u undoes the last split and r resets.
+type timerModel struct {
+	name     string
+	record   timerRecord
+	start    time.Time
+	now      time.Time
+	splits   []time.Duration // cumulative
+	running  bool
+	finished bool
+	err      error
+
+	attempts attemptLog
+
+	// n names the timer, usually for a game and category like sm64-120,
+	// each name keeping its own PB and attempts
+	naming bool
+	input  textinput.Model
+
+	// The goal pacing this timer name, if any, and its time once a WR
+	// pace goal has looked the record up
+	target *Goal
+	goal   time.Duration
+
+	opts timerOptions
+}
+
+// timerOptions stay with the timer through resets and renames
+type timerOptions struct {
+	hotkeys *hotkeyListener // nil when they're off
+	goals   []Goal
+	client  *Client // for looking up WR pace goals
+}
+
+func newTimerModel(name string, opts timerOptions) timerModel {
+	input := textinput.New()
+	input.Placeholder = "sm64-120"
+	input.CharLimit = 60
+	t := timerModel{name: name, opts: opts, input: input}
+	if g, ok := timerGoal(opts.goals, name); ok {
+		t.target = &g
+		t.goal, _ = g.fixed()
+	}
+
+	records := make(map[string]timerRecord)
+	if err := loadState(timerStateFile, &records); err != nil {
+		t.err = err
+	}
+	t.record = records[name]
+	attempts, err := loadAttempts(name)
+	if err != nil {
+		t.err = err
+	}
+	t.attempts = attempts
+	return t
+}
+
+// count adds n attempts to today's
+func (t timerModel) count(n int) timerModel {
+	attempts, err := addAttempts(t.name, n)
+	if err != nil {
+		t.err = err
+		return t
+	}
+	t.attempts = attempts
+	return t
+}
+
+func (t timerModel) elapsed() time.Duration {
+	if t.finished && len(t.splits) > 0 {
+		return t.splits[len(t.splits)-1]
+	}
+	if !t.running {
+		return 0
+	}
+	return t.now.Sub(t.start)
+}
+
+// Final returns the finished run's time, or false while it's still going
+func (t timerModel) Final() (time.Duration, bool) {
+	if !t.finished || len(t.splits) == 0 {
+		return 0, false
+	}
+	return t.splits[len(t.splits)-1], true
+}
+
+func (t timerModel) update(msg tea.Msg) (timerModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case timerTickMsg:
+		if t.running {
+			t.now = time.Time(msg)
+			return t, timerTick()
+		}
+
+	case timerHotkeyMsg:
+		var cmd tea.Cmd
+		t, cmd = t.do(msg.action)
+		return t, tea.Batch(cmd, t.opts.hotkeys.wait())
+
+	case timerGoalMsg:
+		if msg.name != t.name {
+			return t, nil
+		}
+		if msg.err != nil {
+			t.err = fmt.Errorf("looking up the WR for the goal: %w", msg.err)
+			return t, nil
+		}
+		t.goal = msg.time
+
+	case tea.KeyMsg:
+		if t.naming {
+			return t.updateName(msg)
+		}
+		switch msg.String() {
+		case "+", "=":
+			return t.count(1), nil
+		case "-":
+			return t.count(-1), nil
+		case "n":
+			if !t.running {
+				t.naming = true
+				t.input.SetValue(t.name)
+				t.input.CursorEnd()
+				return t, t.input.Focus()
+			}
+		case " ":
+			return t.do(timerSplit)
+		case "enter":
+			return t.do(timerFinish)
+		case "u", "backspace":
+			return t.do(timerUndo)
+		case "r":
+			return t.do(timerReset)
+		}
+
+	default:
+		// Cursor blinks for the name box
+		if t.naming {
+			var cmd tea.Cmd
+			t.input, cmd = t.input.Update(msg)
+			return t, cmd
+		}
+	}
+
+	return t, nil
+}
+
+// do carries out an action, from a key or a global hotkey
+func (t timerModel) do(action timerAction) (timerModel, tea.Cmd) {
+	switch action {
+	case timerSplit:
+		switch {
+		case t.finished:
+		case !t.running:
+			t.start = time.Now()
+			t.now = t.start
+			t.running = true
+			return t.count(1), timerTick()
+		default:
+			t.splits = append(t.splits, time.Since(t.start))
+		}
+	case timerFinish:
+		if t.running {
+			t.splits = append(t.splits, time.Since(t.start))
+			t.running = false
+			t.finished = true
+			t.saveRecord()
+		}
+	case timerUndo:
+		// A finished run has already been saved, so only undo mid-run
+		if t.running && len(t.splits) > 0 {
+			t.splits = t.splits[:len(t.splits)-1]
+		}
+	case timerReset:
+		// Reload so the next attempt compares against any new PB
+		next := newTimerModel(t.name, t.opts)
+		return next, next.goalCmd()
+	}
+	return t, nil
+}
+
+// updateName takes the keys while the timer is being named. A new name
+// starts fresh with that name's PB and attempts, and is remembered for
+// next time.
+func (t timerModel) updateName(msg tea.KeyMsg) (timerModel, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		name := strings.TrimSpace(t.input.Value())
+		t.naming = false
+		t.input.Blur()
+		if name == "" || name == t.name {
+			return t, nil
+		}
+		next := newTimerModel(name, t.opts)
+		if err := saveConfigField("timer_name", name); err != nil {
+			next.err = err
+		}
+		return next, next.goalCmd()
+	case "esc":
+		t.naming = false
+		t.input.Blur()
+		return t, nil
+	}
+	var cmd tea.Cmd
+	t.input, cmd = t.input.Update(msg)
+	return t, cmd
+}
+
+// activate starts listening for the global hotkeys when the timer is first
+// shown, and looks up a WR pace goal
+func (t timerModel) activate() tea.Cmd {
+	cmd := t.opts.hotkeys.start()
+	if t.target != nil && t.goal == 0 {
+		cmd = tea.Batch(cmd, t.goalCmd())
+	}
+	return cmd
+}
+
+// saveRecord folds a finished run into the stored best segments and PB.
+// t.record is left alone so the splits keep comparing against the old PB
+// until the timer is reset.
+func (t *timerModel) saveRecord() {
+	updated := timerRecord{
+		PB:   t.record.PB,
+		Best: append([]time.Duration(nil), t.record.Best...),
+	}
+
+	var prev time.Duration
+	for i, split := range t.splits {
+		seg := split - prev
+		prev = split
+		if i >= len(updated.Best) {
+			updated.Best = append(updated.Best, seg)
+		} else if seg < updated.Best[i] {
+			updated.Best[i] = seg
+		}
+	}
+
+	final, _ := t.Final()
+	if pb := t.record.pbFinal(); pb == 0 || final < pb {
+		updated.PB = append([]time.Duration(nil), t.splits...)
+	}
+
+	records := make(map[string]timerRecord)
+	if err := loadState(timerStateFile, &records); err != nil {
+		t.err = err
+		return
+	}
+	records[t.name] = updated
+	if err := saveState(timerStateFile, records); err != nil {
+		t.err = err
+	}
+}
+
+func (t timerModel) view() string {
+	var b strings.Builder
+
+	// Green while ahead of the goal's pace, red behind it
+	style := timerClockStyle
+	if ahead, ok := t.onPace(); ok {
+		style = style.Foreground(timerBehindStyle.GetForeground())
+		if ahead {
+			style = style.Foreground(timerAheadStyle.GetForeground())
+		}
+	}
+	clock := style.Render(formatRunTime(t.elapsed()))
+	b.WriteString(clock)
+	b.WriteString("\n")
+	if t.target != nil {
+		line := urlStyle.Render("Goal " + t.target.label())
+		if _, fixed := t.target.fixed(); !fixed && t.goal > 0 {
+			line += urlStyle.Render(" " + formatRunTime(t.goal))
+		}
+		if t.goal > 0 {
+			if final, ok := t.Final(); ok {
+				line += " " + renderDelta(final-t.goal)
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	if t.naming {
+		b.WriteString("Name " + t.input.View() + "\n\n")
+	}
+	b.WriteString(urlStyle.Render(t.attempts.summary()) + "\n\n")
+
+	var prev time.Duration
+	for i, split := range t.splits {
+		seg := split - prev
+		prev = split
+
+		line := fmt.Sprintf("%3d  %12s %12s", i+1, formatRunTime(seg), formatRunTime(split))
+		if i < len(t.record.PB) {
+			line += "  " + renderDelta(split-t.record.PB[i])
+		}
+		if i < len(t.record.Best) && seg < t.record.Best[i] {
+			line += " " + timerGoldStyle.Render("★")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	if pb := t.record.pbFinal(); pb > 0 {
+		b.WriteString(fmt.Sprintf("\nPB %s", formatRunTime(pb)))
+		var sob time.Duration
+		for _, seg := range t.record.Best {
+			sob += seg
+		}
+		b.WriteString(fmt.Sprintf(" • sum of best %s", formatRunTime(sob)))
+		b.WriteString("\n")
+	}
+
+	if t.err != nil {
+		b.WriteString(fmt.Sprintf("\nError: %v\n", t.err))
+	}
+	if status := t.opts.hotkeys.status(); status != "" {
+		b.WriteString("\n" + urlStyle.Render(status) + "\n")
+	}
+
+	return b.String()
+}
+
+// renderDelta shows time against a comparison, green and signed - when
+// ahead and red and + when behind
+func renderDelta(delta time.Duration) string {
+	style := timerAheadStyle
+	sign := "-"
+	if delta > 0 {
+		style = timerBehindStyle
+		sign = "+"
+	}
+	if delta < 0 {
+		delta = -delta
+	}
+	return style.Render(sign + formatRunTime(delta))
+}
+
+func (t timerModel) help() string {
+	switch {
+	case t.naming:
+		return "enter use name • esc cancel"
+	case t.finished:
+		return "r reset • +/- attempts • n name"
+	case t.running:
+		return "space split • enter finish • u undo • r reset • +/- attempts"
+	default:
+		return "space start • +/- attempts • n name"
+	}
+}
+
+// standaloneTimer runs the timer screen on its own, without a session
+type standaloneTimer struct {
+	timer timerModel
+}
+
+func (s standaloneTimer) Init() tea.Cmd {
+	return s.timer.activate()
+}
+
+func (s standaloneTimer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c":
+			return s, tea.Quit
+		case "q", "esc":
+			if !s.timer.running && !s.timer.naming {
+				return s, tea.Quit
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	s.timer, cmd = s.timer.update(msg)
+	return s, cmd
+}
+
+func (s standaloneTimer) View() string {
+	header := titleStyle.Render("TIMER: " + strings.ToUpper(s.timer.name))
+	statusBar := statusBarStyle.Render(s.timer.help() + " • q quit")
+	return appStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, s.timer.view(), statusBar))
+}
+
+// runTimer runs the standalone timer. A finished run's time is printed on
+// exit so it can be handed to other tools.
+func runTimer(args []string, cfg Config) error {
+	fs := flag.NewFlagSet("timer", flag.ExitOnError)
+	name := fs.String("name", cfg.timerName(), "name to store PB, best segments and attempts under, e.g. sm64-120")
+	fs.Parse(args)
+
+	p := tea.NewProgram(standaloneTimer{timer: newTimerModel(*name, newTimerOptions(cfg, newConfiguredClient(cfg, "")))}, tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := final.(standaloneTimer).timer.Final(); ok {
+		fmt.Println(formatRunTime(d))
+	}
+	return nil
+}
+
+// timerName is the name the timer starts with
+func (c Config) timerName() string {
+	if c.TimerName == "" {
+		return "default"
+	}
+	return c.TimerName
+}
+
+func newTimerOptions(cfg Config, client *Client) timerOptions {
+	return timerOptions{hotkeys: newHotkeyListener(cfg.TimerHotkeys), goals: cfg.Goals, client: client}
+}
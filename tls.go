@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSConfig is for networks that intercept HTTPS, like a corporate proxy
+// with its own certificate authority
+type TLSConfig struct {
+	// PEM file of extra CA certificates to trust, on top of the system's
+	CAFile string `json:"ca_file,omitempty"`
+
+	// Oldest TLS version to accept: "1.2" (the default) or "1.3"
+	MinVersion string `json:"min_version,omitempty"`
+}
+
+// appTLS is the TLS config for connections that don't go through HTTP,
+// like Twitch chat; nil means Go's defaults
+var appTLS *tls.Config
+
+var tlsVersions = map[string]uint16{"": tls.VersionTLS12, "1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13}
+
+// build makes the tls.Config, nil when nothing is set
+func (c TLSConfig) build() (*tls.Config, error) {
+	if c == (TLSConfig{}) {
+		return nil, nil
+	}
+	version, ok := tlsVersions[c.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("tls: min_version must be 1.2 or 1.3, got %q", c.MinVersion)
+	}
+	config := &tls.Config{MinVersion: version}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: reading ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("tls: ca_file has no PEM certificates")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// applyTLS makes every HTTP client in the app use the TLS settings: the
+// site's, GitHub's, the sinks' and the rest all go through the default
+// transport
+func applyTLS(c TLSConfig) error {
+	config, err := c.build()
+	if err != nil || config == nil {
+		return err
+	}
+	appTLS = config
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	http.DefaultTransport = transport
+	return nil
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	twitchAuthURL = "https://id.twitch.tv/oauth2/token"
+	twitchAPIURL  = "https://api.twitch.tv/helix"
+
+	// Helix accepts at most this many user_login params per request
+	twitchBatchSize = 100
+)
+
+type TwitchConfig struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	// Open the stream's chat alongside the board whenever w opens a stream
+	Chat bool `json:"chat,omitempty"`
+}
+
+type TwitchStream struct {
+	UserLogin   string    `json:"user_login"`
+	UserName    string    `json:"user_name"`
+	GameName    string    `json:"game_name"`
+	Title       string    `json:"title"`
+	ViewerCount int       `json:"viewer_count"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+func (s TwitchStream) URL() string {
+	return "https://www.twitch.tv/" + s.UserLogin
+}
+
+// TwitchClient queries the Helix API with an app access token obtained
+// from the user's own client credentials.
+type TwitchClient struct {
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewTwitchClient returns nil when no credentials are configured, which
+// callers treat as "Twitch features off".
+func NewTwitchClient(cfg TwitchConfig) *TwitchClient {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil
+	}
+	return &TwitchClient{
+		clientID:     cfg.ClientID,
+		clientSecret: cfg.ClientSecret,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t *TwitchClient) appToken() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Now().Before(t.expires) {
+		return t.token, nil
+	}
+
+	form := url.Values{
+		"client_id":     {t.clientID},
+		"client_secret": {t.clientSecret},
+		"grant_type":    {"client_credentials"},
+	}
+	resp, err := t.httpClient.PostForm(twitchAuthURL, form)
+	if err != nil {
+		return "", fmt.Errorf("requesting twitch token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting twitch token: unexpected status code %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding twitch token: %w", err)
+	}
+
+	t.token = result.AccessToken
+	// Renew a minute early so a request never goes out with a dying token
+	t.expires = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return t.token, nil
+}
+
+// LiveStreams returns the streams that are live right now for the given
+// Twitch logins, keyed by lower case login.
+func (t *TwitchClient) LiveStreams(logins []string) (map[string]TwitchStream, error) {
+	live := make(map[string]TwitchStream)
+	if len(logins) == 0 {
+		return live, nil
+	}
+
+	for start := 0; start < len(logins); start += twitchBatchSize {
+		end := min(start+twitchBatchSize, len(logins))
+
+		q := url.Values{}
+		for _, login := range logins[start:end] {
+			q.Add("user_login", login)
+		}
+		q.Set("first", fmt.Sprint(twitchBatchSize))
+
+		var result struct {
+			Data []TwitchStream `json:"data"`
+		}
+		if err := t.get("/streams", q, &result); err != nil {
+			return nil, fmt.Errorf("fetching streams: %w", err)
+		}
+
+		for _, s := range result.Data {
+			live[strings.ToLower(s.UserLogin)] = s
+		}
+	}
+
+	return live, nil
+}
+
+type TwitchVideo struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Type     string `json:"type"` // archive, highlight or upload
+	Duration string `json:"duration"`
+}
+
+// Length parses Helix's "1h2m3s" style duration
+func (v TwitchVideo) Length() time.Duration {
+	d, _ := time.ParseDuration(v.Duration)
+	return d
+}
+
+func (t *TwitchClient) Video(id string) (*TwitchVideo, error) {
+	var result struct {
+		Data []TwitchVideo `json:"data"`
+	}
+	if err := t.get("/videos", url.Values{"id": {id}}, &result); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, errors.New("the Twitch video doesn't exist or was deleted")
+		}
+		return nil, fmt.Errorf("fetching video: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return nil, errors.New("the Twitch video doesn't exist or was deleted")
+	}
+	return &result.Data[0], nil
+}
+
+func (t *TwitchClient) get(path string, q url.Values, out any) error {
+	token, err := t.appToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", twitchAPIURL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Client-Id", t.clientID)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// twitchLogin extracts the channel name from a profile link such as
+// https://www.twitch.tv/somerunner
+func twitchLogin(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || !strings.HasSuffix(u.Host, "twitch.tv") {
+		return ""
+	}
+	login, _, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	return strings.ToLower(login)
+}
+
+// GetTwitchLogin looks up the Twitch channel a user linked on their
+// profile, returning "" when they haven't linked one.
+func (c *Client) GetTwitchLogin(userID string) (string, error) {
+	var result struct {
+		Data struct {
+			Twitch *struct {
+				URI string `json:"uri"`
+			} `json:"twitch"`
+		} `json:"data"`
+	}
+	if err := c.getV1("/users/"+url.PathEscape(userID), &result); err != nil {
+		return "", fmt.Errorf("fetching user %s: %w", userID, err)
+	}
+	if result.Data.Twitch == nil {
+		return "", nil
+	}
+	return twitchLogin(result.Data.Twitch.URI), nil
+}
+
+// liveRunners maps speedrun.com user IDs to their live stream, for the
+// users that are streaming. Profile links are cached on disk since they
+// rarely change.
+func liveRunners(client *Client, twitch *TwitchClient, userIDs []string) (map[string]TwitchStream, error) {
+	cache, err := openCache("twitch")
+	if err != nil {
+		return nil, err
+	}
+
+	logins := make(map[string]string)
+	var mu sync.Mutex
+	var jobs []Job
+	for _, id := range userIDs {
+		var login string
+		if cache.load(id, staticDataTTL, &login) {
+			if login != "" {
+				logins[id] = login
+			}
+			continue
+		}
+		jobs = append(jobs, Job{
+			Name: id,
+			Host: hostOf(client.baseURL),
+			Run: func() error {
+				login, err := client.GetTwitchLogin(id)
+				if err != nil {
+					return err
+				}
+				cache.store(id, login)
+				if login != "" {
+					mu.Lock()
+					logins[id] = login
+					mu.Unlock()
+				}
+				return nil
+			},
+		})
+	}
+	// Missing a few profiles just means fewer live markers
+	poolErr := NewPool(defaultPoolWorkers, defaultPerHost).Run(jobs)
+
+	var names []string
+	for _, login := range logins {
+		names = append(names, login)
+	}
+	streams, err := twitch.LiveStreams(names)
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]TwitchStream)
+	for id, login := range logins {
+		if s, ok := streams[login]; ok {
+			live[id] = s
+		}
+	}
+	return live, poolErr
+}
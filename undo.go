@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// undoDepth is how many actions z can step back through
+const undoDepth = 20
+
+// undoEntry is a recent action and how to take it back
+type undoEntry struct {
+	desc   string // e.g. "pinned Celeste Any%"
+	revert func(model) (model, tea.Cmd)
+}
+
+func (m model) pushUndo(desc string, revert func(model) (model, tea.Cmd)) model {
+	m.undo = append(m.undo, undoEntry{desc: desc, revert: revert})
+	if len(m.undo) > undoDepth {
+		m.undo = m.undo[len(m.undo)-undoDepth:]
+	}
+	return m
+}
+
+// undoLast takes back the most recent action
+func (m model) undoLast() (model, tea.Cmd) {
+	if len(m.undo) == 0 {
+		m.status = "Nothing to undo"
+		return m, nil
+	}
+	last := m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+
+	var cmd tea.Cmd
+	m, cmd = last.revert(m)
+	m.status = "Undid: " + last.desc
+	m.viewport.SetContent(m.renderContent())
+	return m, cmd
+}
+
+// MarkNotificationsUnread marks notifications unread again on the site
+func (c *Client) MarkNotificationsUnread(ids []string) error {
+	body := struct {
+		NotificationIDs []string `json:"notificationIds"`
+		Read            bool     `json:"read"`
+	}{
+		NotificationIDs: ids,
+		Read:            false,
+	}
+	if err := c.write("PutNotificationsRead", body, nil); err != nil {
+		return fmt.Errorf("marking notifications unread: %w", err)
+	}
+	return nil
+}
+
+type markUnreadMsg struct {
+	ids []string
+	err error
+}
+
+// undoMarkRead shows notifications unread again and tells the site
+func (m model) undoMarkRead(ids []string) (model, tea.Cmd) {
+	m = m.markUnread(ids)
+	var cmds []tea.Cmd
+	for account, ids := range m.byAccount(ids) {
+		client := m.clientFor(account)
+		cmds = append(cmds, func() tea.Msg {
+			return markUnreadMsg{ids: ids, err: client.MarkNotificationsUnread(ids)}
+		})
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// version is set at release time with -ldflags "-X main.version=v1.2.3".
+// Builds from source stay "dev" and don't look for updates.
+var version = "dev"
+
+const (
+	releasesURL = "https://api.github.com/repos/marcusziade/speedrunner-tui/releases/latest"
+
+	// Release assets: one binary per platform and their SHA-256 sums, in
+	// the "<sum>  <name>" format of sha256sum
+	checksumsAsset = "checksums.txt"
+
+	// How often the app asks GitHub for a new release
+	updateCheckEvery = 24 * time.Hour
+	updateCheckFile  = "update_check.json"
+)
+
+type release struct {
+	Tag    string `json:"tag_name"`
+	Assets []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r release) asset(name string) (string, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, true
+		}
+	}
+	return "", false
+}
+
+// binaryAsset is the release asset for this platform
+func binaryAsset() string {
+	name := fmt.Sprintf("speedrunner_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+var githubClient = &http.Client{Timeout: time.Minute}
+
+func githubGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", appName+"/"+version)
+	resp, err := githubClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return resp, nil
+}
+
+func latestRelease() (*release, error) {
+	resp, err := githubGet(releasesURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decoding latest release: %w", err)
+	}
+	return &r, nil
+}
+
+// parseVersion reads "v1.2.3" into its numbers
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	fields := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		// Pre-release suffixes, like "-rc1", don't count
+		f, _, _ = strings.Cut(f, "-")
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// newerVersion reports whether latest is a later release than current.
+// Versions that don't parse, such as dev builds, never are.
+func newerVersion(current, latest string) bool {
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// releaseChecksum finds this platform's sum in the release's checksums
+func releaseChecksum(r *release, name string) (string, error) {
+	url, ok := r.asset(checksumsAsset)
+	if !ok {
+		return "", fmt.Errorf("release %s has no %s", r.Tag, checksumsAsset)
+	}
+	resp, err := githubGet(url)
+	if err != nil {
+		return "", fmt.Errorf("fetching checksums: %w", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading checksums: %w", err)
+	}
+	return "", fmt.Errorf("no checksum for %s in release %s", name, r.Tag)
+}
+
+// download saves url to path, returning its SHA-256
+func download(url, path string) (string, error) {
+	resp, err := githubGet(url)
+	if err != nil {
+		return "", fmt.Errorf("downloading: %w", err)
+	}
+	defer resp.Body.Close()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("creating %s: %w", path, err)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		f.Close()
+		return "", fmt.Errorf("downloading: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// runUpdate replaces the running binary with the latest release's, once
+// its checksum matches
+func runUpdate(args []string) error {
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	force := fs.Bool("force", false, "install the latest release even over a newer or dev build")
+	fs.Parse(args)
+
+	r, err := latestRelease()
+	if err != nil {
+		return err
+	}
+	if !*force {
+		if version == "dev" {
+			return errors.New("this is a build from source; use -force to replace it with the latest release")
+		}
+		if !newerVersion(version, r.Tag) {
+			fmt.Printf("Already up to date (%s)\n", version)
+			return nil
+		}
+	}
+
+	name := binaryAsset()
+	url, ok := r.asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no build for %s/%s", r.Tag, runtime.GOOS, runtime.GOARCH)
+	}
+	want, err := releaseChecksum(r, name)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating executable: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("locating executable: %w", err)
+	}
+
+	// Downloaded next to the binary so the swap is a rename on one disk
+	fmt.Printf("Downloading %s %s...\n", name, r.Tag)
+	tmp := exe + ".new"
+	got, err := download(url, tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if got != want {
+		os.Remove(tmp)
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+
+	// A running binary can't be overwritten on Windows, but it can be
+	// moved out of the way
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("moving the old binary: %w", err)
+	}
+	if err := os.Rename(tmp, exe); err != nil {
+		os.Rename(old, exe)
+		return fmt.Errorf("installing the new binary: %w", err)
+	}
+	os.Remove(old)
+
+	fmt.Printf("Updated %s to %s\n", version, r.Tag)
+	return nil
+}
+
+// updateCheck remembers the last look at the releases, so the app asks
+// GitHub at most once a day
+type updateCheck struct {
+	Checked time.Time `json:"checked"`
+	Latest  string    `json:"latest"`
+}
+
+type updateMsg string // the newer release, if any
+
+// updateCheckCmd looks for a newer release in the background. Failures
+// only go to the log; the notice is a nicety.
+func updateCheckCmd() tea.Cmd {
+	return func() tea.Msg {
+		var check updateCheck
+		if err := loadState(updateCheckFile, &check); err != nil {
+			logOnce("update.state", "update: %v", err)
+		}
+		if time.Since(check.Checked) > updateCheckEvery {
+			r, err := latestRelease()
+			if err != nil {
+				logOnce("update.check", "update: %v", err)
+				return nil
+			}
+			check = updateCheck{Checked: time.Now(), Latest: r.Tag}
+			if err := saveState(updateCheckFile, check); err != nil {
+				logOnce("update.state", "update: %v", err)
+			}
+		}
+		if newerVersion(version, check.Latest) {
+			return updateMsg(check.Latest)
+		}
+		return nil
+	}
+}
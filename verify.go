@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Run verification states as the v2 API numbers them
+const (
+	runPending  = 0
+	runVerified = 1
+	runRejected = 2
+)
+
+// SetRunVerification verifies or rejects a run. reason is shown to the
+// runner and only used for rejections.
+func (c *Client) SetRunVerification(runID string, verified int, reason string) error {
+	body := struct {
+		RunID    string `json:"runId"`
+		Verified int    `json:"verified"`
+		Reason   string `json:"reason,omitempty"`
+	}{
+		RunID:    runID,
+		Verified: verified,
+		Reason:   reason,
+	}
+	if err := c.write("PutRunVerification", body, nil); err != nil {
+		return fmt.Errorf("updating run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// trustLevel is how well a moderator knows a runner, going by how many of
+// their runs in the game are already verified.
+type trustLevel int
+
+const (
+	trustNew       trustLevel = iota // no verified runs, or a guest
+	trustReturning                   // a few
+	trustRegular                     // trustRegularRuns or more
+)
+
+const trustRegularRuns = 10
+
+var trustNames = [...]string{
+	trustNew:       "new",
+	trustReturning: "returning",
+	trustRegular:   "regular",
+}
+
+func (t trustLevel) String() string {
+	return trustNames[t]
+}
+
+func trustFor(verifiedRuns int) trustLevel {
+	switch {
+	case verifiedRuns >= trustRegularRuns:
+		return trustRegular
+	case verifiedRuns > 0:
+		return trustReturning
+	}
+	return trustNew
+}
+
+// CountVerifiedRuns counts a user's verified runs in a game, stopping at
+// limit since only the first few matter.
+func (c *Client) CountVerifiedRuns(userID, gameID string, limit int) (int, error) {
+	q := url.Values{
+		"user":   {userID},
+		"game":   {gameID},
+		"status": {"verified"},
+		"max":    {fmt.Sprint(limit)},
+	}
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.getV1("/runs?"+q.Encode(), &result); err != nil {
+		return 0, fmt.Errorf("fetching runs of %s: %w", userID, err)
+	}
+	return len(result.Data), nil
+}
+
+// queueFilter narrows the queue; empty fields match everything
+type queueFilter struct {
+	Game     string        `json:"game,omitempty"`
+	Category string        `json:"category,omitempty"`
+	Platform string        `json:"platform,omitempty"`
+	MinTrust trustLevel    `json:"min_trust,omitempty"`
+	MinAge   time.Duration `json:"min_age,omitempty"` // since it was submitted
+}
+
+func (f queueFilter) active() bool {
+	return f != queueFilter{}
+}
+
+func (f queueFilter) match(r QueueRun) bool {
+	return (f.Game == "" || r.GameID == f.Game) &&
+		(f.Category == "" || r.CategoryID == f.Category) &&
+		(f.Platform == "" || r.PlatformID == f.Platform) &&
+		r.Trust >= f.MinTrust &&
+		(f.MinAge == 0 || time.Since(r.Submitted) >= f.MinAge)
+}
+
+// cycle steps to the next of the options seen in the queue, wrapping back
+// to "" (any) after the last
+func cycle(current string, options []string) string {
+	for i, o := range options {
+		if o == current {
+			if i == len(options)-1 {
+				return ""
+			}
+			return options[i+1]
+		}
+	}
+	if len(options) > 0 && current == "" {
+		return options[0]
+	}
+	return ""
+}
+
+type verifyResult struct {
+	run    QueueRun
+	err    error
+	queued bool // failed, but waiting in the retry queue
+}
+
+// verifyBatch verifies runs one at a time so the progress bar moves and
+// each failure is reported against its run.
+type verifyBatch struct {
+	runs    []QueueRun
+	results []verifyResult
+}
+
+func (b *verifyBatch) done() bool {
+	return len(b.results) == len(b.runs)
+}
+
+func (b *verifyBatch) failed() int {
+	n := 0
+	for _, r := range b.results {
+		if r.err != nil && !r.queued {
+			n++
+		}
+	}
+	return n
+}
+
+func (b *verifyBatch) queued() int {
+	n := 0
+	for _, r := range b.results {
+		if r.queued {
+			n++
+		}
+	}
+	return n
+}
+
+type verifyResultMsg verifyResult
+
+// verifyAllMsg is a confirmed V, verifying every run the filter shows
+type verifyAllMsg struct{}
+
+func verifyRunCmd(client *Client, run QueueRun) tea.Cmd {
+	return func() tea.Msg {
+		err := client.SetRunVerification(run.ID, runVerified, "")
+		return verifyResultMsg{run: run, err: err}
+	}
+}
+
+var (
+	progressFullStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#3B82F6"))
+	progressEmptyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#444444"))
+	okStyle            = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
+	failStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
+)
+
+// progressBar draws done out of total as a bar width cells wide
+func progressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * width / total
+	}
+	return progressFullStyle.Render(strings.Repeat("█", filled)) +
+		progressEmptyStyle.Render(strings.Repeat("░", width-filled)) +
+		fmt.Sprintf(" %d/%d", done, total)
+}
+
+func (q queueModel) batchView() string {
+	b := q.batch
+	var out strings.Builder
+	if b.done() {
+		out.WriteString(fmt.Sprintf("Verified %d of %d runs", len(b.runs)-b.failed()-b.queued(), len(b.runs)))
+		if n := b.failed(); n > 0 {
+			out.WriteString(failStyle.Render(fmt.Sprintf(", %d failed", n)))
+		}
+		if n := b.queued(); n > 0 {
+			out.WriteString(fmt.Sprintf(", %d will retry", n))
+		}
+		out.WriteString(urlStyle.Render("  (x to dismiss)"))
+	} else {
+		out.WriteString("Verifying " + progressBar(len(b.results), len(b.runs), 30))
+	}
+	out.WriteString("\n")
+
+	for _, r := range b.results {
+		desc := fmt.Sprintf("%s • %s by %s", q.categories[r.run.CategoryID], q.formatTime(r.run), strings.Join(r.run.Players, ", "))
+		switch {
+		case r.queued:
+			out.WriteString("⟳ " + desc + urlStyle.Render(": will retry, "+r.err.Error()) + "\n")
+		case r.err != nil:
+			out.WriteString(failStyle.Render("✗ ") + desc + failStyle.Render(": "+r.err.Error()) + "\n")
+		default:
+			out.WriteString(okStyle.Render("✓ ") + desc + "\n")
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// How far a video may be shorter than the submitted time before we call it
+// wrong, to allow for trimmed start/end frames.
+const videoDurationTolerance = 5 * time.Second
+
+type VideoIssue struct {
+	Fatal   bool
+	Message string
+}
+
+func (i VideoIssue) String() string {
+	if i.Fatal {
+		return "error: " + i.Message
+	}
+	return "warning: " + i.Message
+}
+
+// videoInfo is the subset of yt-dlp's JSON metadata we care about
+type videoInfo struct {
+	Title        string  `json:"title"`
+	Duration     float64 `json:"duration"`
+	Availability string  `json:"availability"` // public, unlisted, private, needs_auth, ...
+	LiveStatus   string  `json:"live_status"`  // not_live, is_live, was_live, ...
+	Extractor    string  `json:"extractor_key"`
+	UploadDate   string  `json:"upload_date"` // YYYYMMDD
+}
+
+// fetchVideoInfo reads metadata with yt-dlp, which handles YouTube, Twitch
+// and most other hosts. It returns exec.ErrNotFound if yt-dlp isn't installed.
+func fetchVideoInfo(videoURL string) (*videoInfo, error) {
+	path, err := exec.LookPath("yt-dlp")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := exec.Command(path, "--dump-json", "--skip-download", "--no-playlist", videoURL).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	var info videoInfo
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("decoding yt-dlp output: %w", err)
+	}
+	return &info, nil
+}
+
+// checkVideo looks for the usual reasons a run gets rejected over its
+// video: broken or private links, VODs that will expire, and a video that
+// is shorter than the time being submitted. runTime may be zero to skip
+// the duration check.
+func checkVideo(videoURL string, runTime time.Duration, twitch *TwitchClient) []VideoIssue {
+	var issues []VideoIssue
+	fatal := func(format string, args ...any) {
+		issues = append(issues, VideoIssue{Fatal: true, Message: fmt.Sprintf(format, args...)})
+	}
+	warn := func(format string, args ...any) {
+		issues = append(issues, VideoIssue{Message: fmt.Sprintf(format, args...)})
+	}
+
+	u, err := url.Parse(videoURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fatal("%q is not a valid http(s) link", videoURL)
+		return issues
+	}
+
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	isYouTube := host == "youtube.com" || host == "youtu.be" || host == "m.youtube.com"
+	isTwitch := strings.HasSuffix(host, "twitch.tv")
+
+	switch {
+	case isYouTube && u.Query().Has("list") && !u.Query().Has("v"):
+		fatal("this is a playlist link, link the video itself")
+	case isYouTube && (strings.HasPrefix(u.Path, "/@") || strings.HasPrefix(u.Path, "/channel/")):
+		fatal("this is a channel link, link the video itself")
+	case isTwitch && !strings.HasPrefix(u.Path, "/videos/") && !strings.Contains(u.Path, "/clip/") && host != "clips.twitch.tv":
+		fatal("this looks like a Twitch channel link; link the VOD or highlight instead")
+	case !isYouTube && !isTwitch:
+		warn("%s is not YouTube or Twitch; check the game's rules allow it", host)
+	}
+
+	var duration time.Duration
+	info, err := fetchVideoInfo(videoURL)
+	switch {
+	case err == nil:
+		switch info.Availability {
+		case "private", "needs_auth", "subscriber_only", "premium_only":
+			fatal("the video is %s, verifiers won't be able to watch it", strings.ReplaceAll(info.Availability, "_", " "))
+		}
+		if info.LiveStatus == "is_live" {
+			fatal("the stream is still live; wait for the VOD")
+		}
+		duration = time.Duration(info.Duration * float64(time.Second))
+	case errors.Is(err, exec.ErrNotFound):
+		// No yt-dlp: fall back to what the platforms expose without it
+		if isYouTube {
+			if err := checkYouTubeOEmbed(videoURL); err != nil {
+				fatal("%v", err)
+			}
+		}
+		if isTwitch && twitch != nil && strings.HasPrefix(u.Path, "/videos/") {
+			vod, err := twitch.Video(strings.TrimPrefix(u.Path, "/videos/"))
+			if err != nil {
+				fatal("%v", err)
+			} else {
+				duration = vod.Length()
+				if vod.Type == "archive" {
+					warn("past broadcasts are deleted after 7-60 days; highlight it or upload it to YouTube")
+				}
+			}
+		}
+		if duration == 0 {
+			warn("video length not checked, install yt-dlp for a full check")
+		}
+	default:
+		fatal("the video could not be loaded: %v", err)
+	}
+
+	if isTwitch && strings.HasPrefix(u.Path, "/videos/") && info != nil && info.LiveStatus == "was_live" {
+		warn("past broadcasts are deleted after 7-60 days; highlight it or upload it to YouTube")
+	}
+
+	if duration > 0 && runTime > 0 {
+		if duration+videoDurationTolerance < runTime {
+			fatal("the video is %s long but the run is %s", formatClock(duration), formatRunTime(runTime))
+		} else if duration > runTime*3 && duration-runTime > time.Hour {
+			warn("the video is %s long for a %s run; add a timestamp to the start of the run", formatClock(duration), formatRunTime(runTime))
+		}
+	}
+
+	return issues
+}
+
+// checkYouTubeOEmbed confirms a video exists and is embeddable without an
+// API key. YouTube answers 401 for private videos and 404 for missing ones.
+func checkYouTubeOEmbed(videoURL string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get("https://www.youtube.com/oembed?format=json&url=" + url.QueryEscape(videoURL))
+	if err != nil {
+		return fmt.Errorf("checking video: %w", err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errors.New("the video is private, verifiers won't be able to watch it")
+	case http.StatusNotFound, http.StatusBadRequest:
+		return errors.New("the video doesn't exist or was removed")
+	default:
+		return fmt.Errorf("checking video: unexpected status code %d", resp.StatusCode)
+	}
+}
+
+func runCheckVideo(args []string, cfg Config) error {
+	fs := flag.NewFlagSet("check-video", flag.ExitOnError)
+	runTime := fs.String("time", "", "submitted time, e.g. 1:23:45.678, to compare with the video length")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: speedrunner check-video [-time h:mm:ss] <url>")
+	}
+
+	var d time.Duration
+	if *runTime != "" {
+		var err error
+		if d, err = parseRunTime(*runTime); err != nil {
+			return err
+		}
+	}
+
+	issues := checkVideo(fs.Arg(0), d, NewTwitchClient(cfg.Twitch))
+	if len(issues) == 0 {
+		fmt.Println("Video looks good")
+		return nil
+	}
+
+	fatal := false
+	for _, issue := range issues {
+		fmt.Println(issue)
+		fatal = fatal || issue.Fatal
+	}
+	if fatal {
+		return errors.New("video would likely be rejected")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// submit -video starts a submission from a video that's already up: the
+// title usually names the game, the category and the time, and yt-dlp
+// reads it without a browser.
+
+// A run time in a video title, like 1:39:28 or 14:05.27
+var titleTimePattern = regexp.MustCompile(`\b(?:\d{1,2}:)?\d{1,2}:\d{2}(?:\.\d{1,3})?\b`)
+
+// runSubmitVideo reads the -video flag and guesses a draft from the video
+func runSubmitVideo(args []string, cfg Config, client *Client) (Draft, error) {
+	fs := flag.NewFlagSet("submit", flag.ExitOnError)
+	video := fs.String("video", "", "link to the run's video, to prefill the submission from")
+	fs.Parse(args)
+
+	if *video == "" || fs.NArg() != 0 {
+		return Draft{}, errors.New("usage: speedrunner submit -video <url>")
+	}
+	info, err := fetchVideoInfo(*video)
+	if errors.Is(err, exec.ErrNotFound) {
+		return Draft{}, errors.New("submit -video reads the video with yt-dlp, install it first")
+	}
+	if err != nil {
+		return Draft{}, fmt.Errorf("reading the video: %w", err)
+	}
+
+	games, err := NewGameCache(client)
+	if err != nil {
+		return Draft{}, err
+	}
+	return draftFromVideo(*video, info, games, cfg.FollowedGames), nil
+}
+
+// draftFromVideo fills in what the video tells: the game and category
+// its title names among the followed games, the time from the title (or
+// the video's length without one) and the day it went up
+func draftFromVideo(videoURL string, info *videoInfo, games *GameCache, followed []string) Draft {
+	d := Draft{ID: fmt.Sprint(time.Now().UnixNano()), Video: videoURL, VideoTitle: info.Title}
+
+	if t := titleTimePattern.FindString(info.Title); t != "" {
+		if parsed, err := parseRunTime(t); err == nil {
+			d.Time = formatRunTime(parsed)
+		}
+	}
+	if d.Time == "" && info.Duration > 0 {
+		d.Time = formatRunTime(time.Duration(info.Duration * float64(time.Second)).Round(time.Millisecond))
+	}
+	if day, err := time.Parse("20060102", info.UploadDate); err == nil {
+		d.Date = day.Format(time.DateOnly)
+	}
+
+	data := guessGame(games, followed, info.Title)
+	if data == nil {
+		return d
+	}
+	d.Game, d.GameID, d.GameName = data.Game.URL, data.Game.ID, data.Game.Name
+	d.Step = stepCategory
+	if c, ok := guessCategory(data, info.Title); ok {
+		d.CategoryID, d.Category = c.ID, c.Name
+	}
+	return d
+}
+
+// guessGame is the followed game whose name (or abbreviation) the title
+// has, the longest name winning
+func guessGame(games *GameCache, followed []string, title string) *GameData {
+	words := titleWords(title)
+	var best *GameData
+	bestLen := 0
+	for _, abbr := range followed {
+		data, err := games.Get(abbr)
+		if err != nil {
+			log.Printf("submit: %v", err)
+			continue
+		}
+		for _, name := range []string{data.Game.Name, data.Game.URL} {
+			n := titleWords(name)
+			if strings.Contains(words, n) && len(n) > bestLen {
+				best, bestLen = data, len(n)
+			}
+		}
+	}
+	return best
+}
+
+// guessCategory is the category the title names, the longest name winning
+func guessCategory(data *GameData, title string) (Category, bool) {
+	words := titleWords(title)
+	var best Category
+	for _, c := range data.Categories {
+		if c.IsPerLevel || c.Archived {
+			continue
+		}
+		n := titleWords(c.Name)
+		if strings.Contains(words, n) && len(n) > len(titleWords(best.Name)) {
+			best = c
+		}
+	}
+	return best, best.ID != ""
+}
+
+// titleWords is text lower cased down to its letters and digits, space
+// separated and padded, so names only match whole words
+func titleWords(s string) string {
+	fields := tokenize(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return " " + strings.Join(fields, " ") + " "
+}
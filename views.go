@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SavedView is a named notification filter. Empty fields match everything.
+type SavedView struct {
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	Game   string `json:"game,omitempty"`
+	Unread bool   `json:"unread,omitempty"`
+	Search string `json:"search,omitempty"`
+}
+
+func (v SavedView) match(n Notification) bool {
+	return (v.Type == "" || v.Type == n.Type) &&
+		(v.Game == "" || strings.EqualFold(v.Game, notificationGame(n))) &&
+		(!v.Unread || !n.Read) &&
+		(v.Search == "" || strings.Contains(strings.ToLower(n.Title), strings.ToLower(v.Search)))
+}
+
+func (v SavedView) active() bool {
+	v.Name = ""
+	return v != SavedView{}
+}
+
+// describe lists the criteria, e.g. "run_verified • sm64 • unread"
+func (v SavedView) describe() string {
+	var parts []string
+	if v.Type != "" {
+		parts = append(parts, v.Type)
+	}
+	if v.Game != "" {
+		parts = append(parts, v.Game)
+	}
+	if v.Unread {
+		parts = append(parts, "unread")
+	}
+	if v.Search != "" {
+		parts = append(parts, fmt.Sprintf("%q", v.Search))
+	}
+	return strings.Join(parts, " • ")
+}
+
+const (
+	promptNone = iota
+	promptSearch
+	promptName
+)
+
+// inboxFilter narrows the notification list. t, g and u cycle the type,
+// game and unread filters, / searches titles, S saves the combination as
+// a named view and V picks a saved one.
+type inboxFilter struct {
+	current SavedView
+	views   []SavedView
+
+	prompt  int
+	input   textinput.Model
+	menu    bool
+	menuSel int
+	err     error
+}
+
+func newInboxFilter(views []SavedView) inboxFilter {
+	input := textinput.New()
+	input.CharLimit = 100
+	input.Width = 40
+	return inboxFilter{views: views, input: input}
+}
+
+// capturing reports whether a prompt or menu wants every key
+func (f inboxFilter) capturing() bool {
+	return f.prompt != promptNone || f.menu
+}
+
+func (f inboxFilter) apply(notifications []Notification) []Notification {
+	if !f.current.active() {
+		return notifications
+	}
+	var kept []Notification
+	for _, n := range notifications {
+		if f.current.match(n) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// update handles the filter keys, reporting whether it used the key
+func (f inboxFilter) update(msg tea.KeyMsg, notifications []Notification) (inboxFilter, tea.Cmd, bool) {
+	switch {
+	case f.prompt != promptNone:
+		return f.updatePrompt(msg)
+	case f.menu:
+		return f.updateMenu(msg), nil, true
+	}
+
+	f.err = nil
+	switch msg.String() {
+	case "/":
+		f.prompt = promptSearch
+		f.input.Placeholder = "search titles"
+		f.input.SetValue(f.current.Search)
+		f.input.CursorEnd()
+		return f, f.input.Focus(), true
+	case "t":
+		f.current.Type = cycle(f.current.Type, distinct(notifications, func(n Notification) string { return n.Type }))
+	case "g":
+		f.current.Game = cycle(f.current.Game, distinct(notifications, notificationGame))
+	case "u":
+		f.current.Unread = !f.current.Unread
+	case "S":
+		if !f.current.active() {
+			return f, nil, true
+		}
+		f.prompt = promptName
+		f.input.Placeholder = "view name"
+		f.input.SetValue(f.current.Name)
+		f.input.CursorEnd()
+		return f, f.input.Focus(), true
+	case "V":
+		f.menu = true
+		f.menuSel = 0
+	case "esc":
+		if !f.current.active() {
+			return f, nil, false
+		}
+		f.current = SavedView{}
+	default:
+		return f, nil, false
+	}
+	f.current.Name = ""
+	return f, nil, true
+}
+
+func (f inboxFilter) updatePrompt(msg tea.KeyMsg) (inboxFilter, tea.Cmd, bool) {
+	switch msg.String() {
+	case "enter":
+		value := strings.TrimSpace(f.input.Value())
+		switch f.prompt {
+		case promptSearch:
+			f.current.Search = value
+			f.current.Name = ""
+		case promptName:
+			if value == "" {
+				return f, nil, true
+			}
+			f.current.Name = value
+			f.err = f.save(f.current)
+		}
+		fallthrough
+	case "esc":
+		f.prompt = promptNone
+		f.input.Blur()
+		return f, nil, true
+	}
+
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return f, cmd, true
+}
+
+// save adds or replaces a view by name and writes the list to the config
+func (f *inboxFilter) save(v SavedView) error {
+	views := make([]SavedView, 0, len(f.views)+1)
+	for _, existing := range f.views {
+		if existing.Name != v.Name {
+			views = append(views, existing)
+		}
+	}
+	views = append(views, v)
+	if err := saveConfigField("views", views); err != nil {
+		return fmt.Errorf("saving view: %w", err)
+	}
+	f.views = views
+	return nil
+}
+
+func (f inboxFilter) updateMenu(msg tea.KeyMsg) inboxFilter {
+	// Entry 0 is "All notifications", then the saved views
+	switch msg.String() {
+	case "up", "k":
+		if f.menuSel > 0 {
+			f.menuSel--
+		}
+	case "down", "j":
+		if f.menuSel < len(f.views) {
+			f.menuSel++
+		}
+	case "enter":
+		f.menu = false
+		if f.menuSel == 0 {
+			f.current = SavedView{}
+		} else {
+			f.current = f.views[f.menuSel-1]
+		}
+	case "esc", "q", "V":
+		f.menu = false
+	}
+	return f
+}
+
+// distinct lists the values of a notification field present in the list,
+// in list order
+func distinct(notifications []Notification, field func(Notification) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, n := range notifications {
+		if v := field(n); v != "" && !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// view is the filter bar, prompt or menu shown above the list, or ""
+func (f inboxFilter) view(shown, total int) string {
+	switch {
+	case f.prompt != promptNone:
+		return alertBannerStyle.Render(f.input.View())
+	case f.menu:
+		var b strings.Builder
+		entries := []string{"All notifications"}
+		for _, v := range f.views {
+			entries = append(entries, v.Name+urlStyle.Render("  "+v.describe()))
+		}
+		for i, e := range entries {
+			cursor := "  "
+			if i == f.menuSel {
+				cursor = "> "
+			}
+			b.WriteString(cursor + e)
+			if i < len(entries)-1 {
+				b.WriteString("\n")
+			}
+		}
+		return alertBannerStyle.Render(b.String())
+	}
+
+	var line string
+	if f.current.active() {
+		line = fmt.Sprintf("%s (%d of %d)", f.current.describe(), shown, total)
+		if f.current.Name != "" {
+			line = f.current.Name + ": " + line
+		}
+	}
+	if f.err != nil {
+		line += " " + failStyle.Render(f.err.Error())
+	}
+	if line == "" {
+		return ""
+	}
+	return statusBarStyle.Render(line)
+}
+
+func (f inboxFilter) help() string {
+	switch {
+	case f.prompt == promptSearch:
+		return "enter search • esc cancel"
+	case f.prompt == promptName:
+		return "enter save view • esc cancel"
+	case f.menu:
+		return "j/k choose view • enter apply • esc close"
+	}
+	return "/ search • t/g/u filter • S save • V views"
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const summaryWindow = 7 * 24 * time.Hour
+
+// Notification types counted as someone talking to you
+var commentTypes = map[string]bool{
+	"run_comment":  true,
+	"forum_reply":  true,
+	"thread_reply": true,
+	"mention":      true,
+}
+
+// wrChange is the world record standing on a followed board
+type wrChange struct {
+	Game     string
+	Category string
+	Time     time.Duration
+	Millis   bool // the game times runs to the millisecond
+	Players  string
+	Date     time.Time
+	URL      string
+}
+
+// weeklySummary is the "what happened while I was away" digest
+type weeklySummary struct {
+	Since     time.Time
+	Verified  []Notification
+	Rejected  []Notification
+	Followers []Notification
+	Comments  []Notification
+	Other     int
+	WRs       []wrChange
+}
+
+// summarizeHistory sorts the archived notifications from the window into
+// the digest's sections.
+func summarizeHistory(entries []HistoryEntry, since time.Time) weeklySummary {
+	s := weeklySummary{Since: since}
+	for _, e := range entries {
+		if e.Time().Before(since) {
+			continue
+		}
+		n := e.Notification
+		switch {
+		case n.Type == "run_verified":
+			s.Verified = append(s.Verified, n)
+		case n.Type == "run_rejected":
+			s.Rejected = append(s.Rejected, n)
+		case n.Type == "new_follower":
+			s.Followers = append(s.Followers, n)
+		case commentTypes[n.Type]:
+			s.Comments = append(s.Comments, n)
+		default:
+			s.Other++
+		}
+	}
+	return s
+}
+
+// recentWRs lists the records on followed boards set since then
+func recentWRs(client *Client, cache *GameCache, followed []string, since time.Time) ([]wrChange, error) {
+	wrs, err := currentWRs(client, cache, followed)
+	var recent []wrChange
+	for _, wr := range wrs {
+		if !wr.Date.Before(since) {
+			recent = append(recent, wr)
+		}
+	}
+	sort.Slice(recent, func(i, j int) bool { return recent[i].Date.After(recent[j].Date) })
+	return recent, err
+}
+
+type summaryMsg struct {
+	summary weeklySummary
+	// Per day, for the heatmap
+	activity      map[string]int
+	verifications map[string]int
+	err           error
+}
+
+// summaryModel is the Week tab
+type summaryModel struct {
+	client   *Client
+	games    *GameCache
+	followed []string
+
+	summary       weeklySummary
+	activity      map[string]int
+	verifications map[string]int
+	onlyVerified  bool // heatmap of verifications instead of everything
+	loaded        bool
+	loading       bool
+	err           error
+}
+
+func newSummaryModel(client *Client, cfg Config) summaryModel {
+	s := summaryModel{client: client, followed: cfg.FollowedGames}
+	s.games, s.err = NewGameCache(client)
+	return s
+}
+
+func (s summaryModel) loadCmd() tea.Cmd {
+	client, cache, followed := s.client, s.games, s.followed
+	return func() tea.Msg {
+		since := time.Now().Add(-summaryWindow)
+		entries, err := loadHistory()
+		if err != nil {
+			return summaryMsg{err: err}
+		}
+		msg := summaryMsg{
+			summary:  summarizeHistory(entries, since),
+			activity: dailyCounts(entries, nil),
+			verifications: dailyCounts(entries, func(n Notification) bool {
+				return n.Type == "run_verified"
+			}),
+		}
+		if cache != nil && len(followed) > 0 {
+			msg.summary.WRs, msg.err = recentWRs(client, cache, followed, since)
+		}
+		return msg
+	}
+}
+
+func (s summaryModel) activate() (summaryModel, tea.Cmd) {
+	if s.loaded || s.loading {
+		return s, nil
+	}
+	s.loading = true
+	return s, s.loadCmd()
+}
+
+func (s summaryModel) update(msg tea.Msg) (summaryModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case summaryMsg:
+		s.loading = false
+		s.loaded = true
+		s.summary = msg.summary
+		s.activity = msg.activity
+		s.verifications = msg.verifications
+		s.err = msg.err
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			if !s.loading {
+				s.loading = true
+				return s, s.loadCmd()
+			}
+		case "h":
+			s.onlyVerified = !s.onlyVerified
+		}
+	}
+	return s, nil
+}
+
+func (s summaryModel) view() string {
+	if s.loading {
+		return "Putting the week together..."
+	}
+
+	var b strings.Builder
+	if s.err != nil {
+		b.WriteString(fmt.Sprintf("Error: %v\n\n", s.err))
+	}
+	if !s.loaded {
+		return b.String()
+	}
+
+	sum := s.summary
+	b.WriteString(urlStyle.Render(fmt.Sprintf("Since %s", sum.Since.Local().Format("Mon Jan 2 15:04"))))
+	b.WriteString("\n\n")
+
+	section := func(title string, items []Notification) {
+		b.WriteString(titleStyle.Render(fmt.Sprintf("%s (%d)", title, len(items))))
+		b.WriteString("\n")
+		for _, n := range items {
+			b.WriteString("  • " + n.Title + "\n")
+		}
+		if len(items) == 0 {
+			b.WriteString(urlStyle.Render("  nothing this week") + "\n")
+		}
+		b.WriteString("\n")
+	}
+	section("Runs verified", sum.Verified)
+	if len(sum.Rejected) > 0 {
+		section("Runs rejected", sum.Rejected)
+	}
+	section("New followers", sum.Followers)
+	section("Comments and replies", sum.Comments)
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("New world records (%d)", len(sum.WRs))))
+	b.WriteString("\n")
+	switch {
+	case len(s.followed) == 0:
+		b.WriteString(urlStyle.Render("  add followed_games to the config to watch their records") + "\n")
+	case len(sum.WRs) == 0:
+		b.WriteString(urlStyle.Render("  no new records on followed boards") + "\n")
+	}
+	for _, wr := range sum.WRs {
+		b.WriteString(fmt.Sprintf("  • %s %s in %s by %s, %s\n",
+			wr.Game, wr.Category, formatRunTime(wr.Time), wr.Players, wr.Date.Local().Format("Mon Jan 2")))
+	}
+
+	if sum.Other > 0 {
+		b.WriteString(urlStyle.Render(fmt.Sprintf("\n%d other notifications", sum.Other)))
+		b.WriteString("\n")
+	}
+
+	heatmapTitle, counts := "Notification activity", s.activity
+	if s.onlyVerified {
+		heatmapTitle, counts = "Runs verified", s.verifications
+	}
+	b.WriteString("\n")
+	b.WriteString(titleStyle.Render(heatmapTitle))
+	b.WriteString("\n")
+	b.WriteString(renderHeatmap(counts, time.Now(), heatmapWeeks))
+	return b.String()
+}
+
+func (s summaryModel) help() string {
+	if s.onlyVerified {
+		return "h show all activity • r refresh"
+	}
+	return "h show verifications only • r refresh"
+}
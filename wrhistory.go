@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// Working out the progression means paging through every verified run
+	// in the category, so it's kept for a while
+	wrHistoryTTL = 6 * time.Hour
+
+	wrSparkWidth = 60
+)
+
+// wrRecord is a run that was the world record when it was set
+type wrRecord struct {
+	Time    time.Duration `json:"time"`
+	Players []string      `json:"players"`
+	Date    time.Time     `json:"date"`
+}
+
+type wrHistoryMsg struct {
+	records []wrRecord
+	err     error
+}
+
+// matchesValues reports whether a run is on the board the filters select
+func matchesValues(r v1Run, filters []ValueFilter) bool {
+	for _, f := range filters {
+		if len(f.ValueIDs) > 0 && r.Values[f.VariableID] != f.ValueIDs[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// wrProgression walks runs in date order and keeps each that beat the
+// record standing at the time
+func wrProgression(runs []v1Run, filters []ValueFilter) []wrRecord {
+	var records []wrRecord
+	for _, r := range runs {
+		date, err := time.Parse(time.DateOnly, r.Date)
+		if err != nil || !matchesValues(r, filters) {
+			continue
+		}
+		q := r.queueRun()
+		if q.Time <= 0 {
+			continue
+		}
+		if len(records) == 0 || q.Time < records[len(records)-1].Time {
+			records = append(records, wrRecord{Time: q.Time, Players: q.Players, Date: date})
+		}
+	}
+	return records
+}
+
+// GetWRHistory lists every world record a board has had, oldest first
+func (c *Client) GetWRHistory(params LeaderboardParams) ([]wrRecord, error) {
+	q := url.Values{}
+	q.Set("game", params.GameID)
+	q.Set("category", params.CategoryID)
+	if params.LevelID != "" {
+		q.Set("level", params.LevelID)
+	}
+	q.Set("status", "verified")
+	q.Set("orderby", "date")
+	q.Set("direction", "asc")
+	q.Set("embed", "players")
+
+	runs, err := c.listRuns(q, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching run history: %w", err)
+	}
+	return wrProgression(runs, params.Values), nil
+}
+
+func (b boardsModel) loadHistoryCmd() tea.Cmd {
+	client, params := b.client, b.params
+	return func() tea.Msg {
+		cache, err := openCache("wrhistory")
+		if err != nil {
+			return wrHistoryMsg{err: err}
+		}
+		key := fmt.Sprintf("%x", sha256.Sum256([]byte(boardKey(params))))[:16]
+
+		var records []wrRecord
+		if cache.load(key, wrHistoryTTL, &records) {
+			return wrHistoryMsg{records: records}
+		}
+		records, err = client.GetWRHistory(params)
+		if err == nil {
+			err = cache.store(key, records)
+		}
+		return wrHistoryMsg{records: records, err: err}
+	}
+}
+
+// wrSparkline samples the standing record at even steps from the first
+// record to now, so long reigns take up as much room as they lasted
+func wrSparkline(records []wrRecord, now time.Time, width int) string {
+	if len(records) == 0 {
+		return ""
+	}
+	start := records[0].Date
+	span := now.Sub(start)
+	best := records[len(records)-1].Time
+
+	values := make([]int, width)
+	current := 0
+	for i := range values {
+		at := start.Add(span * time.Duration(i) / time.Duration(max(width-1, 1)))
+		for current+1 < len(records) && !records[current+1].Date.After(at) {
+			current++
+		}
+		// Relative to the current record so the improvements show
+		values[i] = int((records[current].Time - best) / time.Millisecond)
+	}
+	return sparkline(values)
+}
+
+func (b boardsModel) historyView() string {
+	records := b.history
+	if len(records) == 0 {
+		return "No verified runs with dates on this board"
+	}
+
+	var out strings.Builder
+	first, last := records[0], records[len(records)-1]
+	out.WriteString(fmt.Sprintf("%s  %s → %s\n",
+		wrSparkline(records, time.Now(), wrSparkWidth), b.times.render(first.Time, b.millis()), b.times.render(last.Time, b.millis())))
+	out.WriteString(urlStyle.Render(fmt.Sprintf("%d records since %s", len(records), first.Date.Format("Jan 2006"))))
+	out.WriteString("\n\n")
+
+	// Newest first, like the site's progression table
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		improvement := ""
+		if i > 0 {
+			improvement = urlStyle.Render("  -" + b.times.render(records[i-1].Time-r.Time, b.millis()))
+		}
+		out.WriteString(fmt.Sprintf("%s  %12s  %s%s\n",
+			r.Date.Format(time.DateOnly), b.times.render(r.Time, b.millis()), strings.Join(r.Players, ", "), improvement))
+	}
+	return out.String()
+}
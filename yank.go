@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// yankedRun is a run as y and Y copy it, from the queue or a board
+type yankedRun struct {
+	ID      string   `json:"id"`
+	URL     string   `json:"url"`
+	Board   string   `json:"board"` // game › category
+	Place   int      `json:"place,omitempty"`
+	Players []string `json:"players"`
+	Time    string   `json:"time"`
+	Seconds float64  `json:"seconds"`
+	Date    string   `json:"date,omitempty"`
+	Video   string   `json:"video,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// markdown formats the run for pasting into Discord
+func (r yankedRun) markdown() string {
+	var b strings.Builder
+	if r.Place > 0 {
+		b.WriteString(fmt.Sprintf("#%d ", r.Place))
+	}
+	b.WriteString(fmt.Sprintf("**%s** in **%s** by %s", r.Board, r.Time, strings.Join(r.Players, ", ")))
+	if r.Date != "" {
+		b.WriteString(" (" + r.Date + ")")
+	}
+	b.WriteString("\n" + r.URL)
+	if r.Video != "" && r.Video != r.URL {
+		b.WriteString("\nVideo: " + r.Video)
+	}
+	return b.String()
+}
+
+func notificationMarkdown(n Notification) string {
+	return fmt.Sprintf("**%s**\n%s • https://www.speedrun.com%s",
+		n.Title, time.Unix(n.Date, 0).Format("2006-01-02 15:04"), n.Path)
+}
+
+// yankCmd copies v to the clipboard, as Markdown or as indented JSON
+func yankCmd(what, markdown string, v any, asJSON bool) tea.Cmd {
+	return func() tea.Msg {
+		text, format := markdown, "Markdown"
+		if asJSON {
+			raw, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return statusMsg(fmt.Sprintf("Copying failed: %v", err))
+			}
+			text, format = string(raw), "JSON"
+		}
+		if err := copyToClipboard(text); err != nil {
+			return statusMsg(fmt.Sprintf("Copying failed: %v", err))
+		}
+		return statusMsg(fmt.Sprintf("Copied %s as %s", what, format))
+	}
+}
+
+// Clipboard commands to try, in order
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux":   {{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}},
+}
+
+// copyToClipboard uses the system's clipboard tool, or the terminal's
+// OSC 52 escape when there isn't one, which also works over SSH
+func copyToClipboard(text string) error {
+	for _, args := range clipboardCommands[runtime.GOOS] {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err == nil {
+			return nil
+		}
+	}
+	_, err := fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+func (q queueModel) yanked(r QueueRun) yankedRun {
+	return yankedRun{
+		ID:      r.ID,
+		URL:     r.Weblink,
+		Board:   q.gameName(r.GameID) + " › " + q.categories[r.CategoryID],
+		Players: r.Players,
+		Time:    q.formatTime(r),
+		Seconds: r.Time.Seconds(),
+		Date:    r.Submitted.Local().Format(time.DateOnly),
+		Video:   r.Video,
+		Comment: r.Comment,
+	}
+}
+
+func (b boardsModel) yanked(r Run) yankedRun {
+	y := yankedRun{
+		ID:      r.ID,
+		URL:     b.runURL(r),
+		Board:   b.title(),
+		Place:   r.Place,
+		Time:    b.formatTime(r),
+		Seconds: r.Duration().Seconds(),
+		Video:   r.Video,
+		Comment: r.Comment,
+	}
+	for _, runner := range b.board.Runners(r, b.mods) {
+		y.Players = append(y.Players, runner.Name)
+	}
+	if r.Date > 0 {
+		y.Date = time.Unix(r.Date, 0).Format(time.DateOnly)
+	}
+	return y
+}